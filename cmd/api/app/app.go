@@ -4,11 +4,17 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/PeterM45/perfolio-api/internal/common/config"
 	"github.com/PeterM45/perfolio-api/internal/common/middleware"
 	"github.com/PeterM45/perfolio-api/internal/platform/cache"
 	"github.com/PeterM45/perfolio-api/internal/platform/database"
+	"github.com/PeterM45/perfolio-api/internal/platform/moderation"
+	"github.com/PeterM45/perfolio-api/internal/platform/ranking"
+	"github.com/PeterM45/perfolio-api/internal/platform/storage"
+	"github.com/PeterM45/perfolio-api/internal/platform/unfurl"
+	"github.com/PeterM45/perfolio-api/internal/platform/worker"
 
 	"github.com/PeterM45/perfolio-api/internal/user/handler"
 	"github.com/PeterM45/perfolio-api/internal/user/repository"
@@ -19,11 +25,12 @@ import (
 
 // Application represents the API application
 type Application struct {
-	config *config.Config
-	server *http.Server
-	logger logger.Logger
-	db     *database.DB
-	cache  cache.Cache
+	config  *config.Config
+	server  *http.Server
+	logger  logger.Logger
+	db      *database.DB
+	cache   cache.Cache
+	workers []*worker.Worker
 }
 
 // New creates a new application
@@ -59,27 +66,154 @@ func New(cfg *config.Config, log logger.Logger) (*Application, error) {
 		cacheClient = cache.NewInMemoryCache(cfg.Cache.DefaultTTL)
 	}
 
+	// Initialize media storage
+	var mediaStorage storage.Storage
+	var localStorage *storage.LocalStorage
+	switch cfg.Media.Storage {
+	case "s3":
+		mediaStorage, err = storage.NewS3Storage(storage.S3Config{
+			Endpoint:        cfg.Media.S3.Endpoint,
+			Region:          cfg.Media.S3.Region,
+			Bucket:          cfg.Media.S3.Bucket,
+			AccessKeyID:     cfg.Media.S3.AccessKeyID,
+			SecretAccessKey: cfg.Media.S3.SecretAccessKey,
+			PathStyle:       cfg.Media.S3.PathStyle,
+			PublicURL:       cfg.Media.S3.PublicURL,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure media storage: %w", err)
+		}
+	default:
+		localStorage, err = storage.NewLocalStorage(cfg.Media.Local.Dir, cfg.Media.Local.BaseURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure media storage: %w", err)
+		}
+		mediaStorage = localStorage
+	}
+
 	// Initialize repositories
 	userRepository := repository.NewUserRepository(db)
 	postRepository := repository.NewPostRepository(db)
 	widgetRepository := repository.NewWidgetRepository(db)
+	reactionRepository := repository.NewReactionRepository(db)
+	commentRepository := repository.NewCommentRepository(db)
+	bookmarkRepository := repository.NewBookmarkRepository(db)
+	linkPreviewRepository := repository.NewLinkPreviewRepository(db)
+	mediaRepository := repository.NewMediaRepository(db)
+	reportRepository := repository.NewReportRepository(db)
+
+	// Initialize content filters
+	contentFilter := moderation.NewChain()
+	if cfg.ContentFilter.Enabled {
+		contentFilter = moderation.NewChain(
+			moderation.NewWordListFilter(cfg.ContentFilter.BlockedWords, cfg.ContentFilter.FlaggedWords),
+			moderation.NewLinkDensityFilter(cfg.ContentFilter.MaxLinks, cfg.ContentFilter.MaxLinkRatio),
+			moderation.NewRepeatedContentFilter(
+				cacheClient,
+				cfg.ContentFilter.RepeatWindow,
+				cfg.ContentFilter.RepeatFlagAfter,
+				cfg.ContentFilter.RepeatRejectAfter,
+			),
+		)
+	}
+	contentChecker := service.NewContentChecker(contentFilter, reportRepository, log)
 
 	// Initialize auth middleware
 	authMiddleware := middleware.NewAuthMiddleware(cfg.Auth.JWTSecret)
 
 	// Initialize services
-	userSvc := service.NewUserService(userRepository, cacheClient, log)
-	postSvc := service.NewPostService(postRepository, userSvc, cacheClient, log)
-	widgetSvc := service.NewWidgetService(widgetRepository, userSvc, cacheClient, log)
+	userSvc := service.NewUserService(userRepository, contentChecker, cacheClient, log)
+	postSvc := service.NewPostService(postRepository, userSvc, contentChecker, service.FeedConfig{
+		FanOutMaxFollowers: cfg.Feed.FanOutMaxFollowers,
+		TopWindow:          cfg.Feed.Top.Window,
+		TopCandidates:      cfg.Feed.Top.MaxCandidates,
+		AffinityWindow:     cfg.Feed.Top.AffinityWindow,
+		Strategy: ranking.NewWeightedStrategy(ranking.Weights{
+			HalfLife:        cfg.Feed.Top.HalfLife,
+			Reactions:       cfg.Feed.Top.ReactionWeights,
+			DefaultReaction: 1,
+			Comment:         cfg.Feed.Top.CommentWeight,
+			Affinity:        cfg.Feed.Top.AffinityWeight,
+		}),
+		ExploreWindow:       cfg.Feed.Explore.Window,
+		ExploreSize:         cfg.Feed.Explore.Size,
+		ExploreMaxPerAuthor: cfg.Feed.Explore.MaxPerAuthor,
+		ExploreInterval:     cfg.Feed.Explore.Interval,
+	}, cacheClient, log)
+	widgetSvc := service.NewWidgetService(widgetRepository, userSvc, contentChecker, cacheClient, log)
+	reactionSvc := service.NewReactionService(reactionRepository, postSvc, cacheClient, log)
+	commentSvc := service.NewCommentService(commentRepository, postSvc, cacheClient, log)
+	bookmarkSvc := service.NewBookmarkService(bookmarkRepository, postSvc, cacheClient, log)
+	mediaSvc := service.NewMediaService(mediaRepository, mediaStorage, service.MediaConfig{
+		MaxUploadBytes: cfg.Media.MaxUploadBytes,
+		MaxPixels:      cfg.Media.MaxPixels,
+		MaxFrames:      cfg.Media.MaxFrames,
+		ThumbnailSize:  cfg.Media.ThumbnailSize,
+		OrphanTTL:      cfg.Media.OrphanTTL,
+	}, log)
+	reportSvc := service.NewReportService(reportRepository, postSvc, userSvc, cacheClient, log)
 
 	// Initialize handlers
 	userHandler := handler.NewUserHandler(userSvc, log)
 	postHandler := handler.NewPostHandler(postSvc, log)
 	widgetHandler := handler.NewWidgetHandler(widgetSvc, log)
+	reactionHandler := handler.NewReactionHandler(reactionSvc, log)
+	commentHandler := handler.NewCommentHandler(commentSvc, log)
+	bookmarkHandler := handler.NewBookmarkHandler(bookmarkSvc, log)
+	mediaHandler := handler.NewMediaHandler(mediaSvc, log)
+	reportHandler := handler.NewReportHandler(reportSvc, log)
 	authHandler := handler.NewAuthHandler(userSvc, authMiddleware, log)
 
+	// Initialize background workers
+	var workers []*worker.Worker
+	if cfg.Scheduler.Enabled {
+		batchSize := cfg.Scheduler.BatchSize
+		workers = append(workers, worker.New("post-publisher", cfg.Scheduler.Interval, func(ctx context.Context) error {
+			_, err := postSvc.PublishScheduledPosts(ctx, batchSize)
+			return err
+		}, log))
+	}
+	if cfg.Unfurl.Enabled {
+		unfurler := unfurl.New(unfurl.Config{
+			Timeout:      cfg.Unfurl.Timeout,
+			MaxBodyBytes: cfg.Unfurl.MaxBodyBytes,
+			UserAgent:    cfg.Unfurl.UserAgent,
+		})
+		linkPreviewSvc := service.NewLinkPreviewService(linkPreviewRepository, unfurler, log)
+		batchSize := cfg.Unfurl.BatchSize
+		workers = append(workers, worker.New("link-unfurler", cfg.Unfurl.Interval, func(ctx context.Context) error {
+			_, err := linkPreviewSvc.ProcessPending(ctx, batchSize)
+			return err
+		}, log))
+	}
+	workers = append(workers, worker.New("timeline-invalidator", cfg.Feed.InvalidateInterval, func(ctx context.Context) error {
+		_, err := postSvc.InvalidateFollowerTimelines(ctx)
+		return err
+	}, log))
+	workers = append(workers, worker.New("explore-builder", cfg.Feed.Explore.Interval, func(ctx context.Context) error {
+		_, err := postSvc.BuildExplore(ctx)
+		return err
+	}, log))
+	workers = append(workers, worker.New("post-purger", cfg.Trash.PurgeInterval, func(ctx context.Context) error {
+		_, err := postSvc.PurgeDeletedPosts(ctx, cfg.Trash.PurgeBatchSize)
+		return err
+	}, log))
+	workers = append(workers, worker.New("media-gc", cfg.Media.GCInterval, func(ctx context.Context) error {
+		_, err := mediaSvc.CollectOrphans(ctx, 100)
+		return err
+	}, log))
+
 	// Initialize router
-	router := NewRouter(userHandler, postHandler, widgetHandler, authHandler, authMiddleware, log)
+	router := NewRouter(userHandler, postHandler, widgetHandler, reactionHandler, commentHandler, bookmarkHandler, mediaHandler, reportHandler, authHandler, authMiddleware, userSvc, log)
+
+	// Serve locally stored uploads from the path of their base URL
+	if localStorage != nil {
+		baseURL, err := url.Parse(cfg.Media.Local.BaseURL)
+		if err != nil || baseURL.Path == "" || baseURL.Path == "/" {
+			return nil, fmt.Errorf("media.local.base_url must include a path to serve uploads from")
+		}
+		router.Static(baseURL.Path, localStorage.Dir())
+	}
 
 	// Create server
 	server := &http.Server{
@@ -91,21 +225,34 @@ func New(cfg *config.Config, log logger.Logger) (*Application, error) {
 	}
 
 	return &Application{
-		config: cfg,
-		server: server,
-		logger: log,
-		db:     db,
-		cache:  cacheClient,
+		config:  cfg,
+		server:  server,
+		logger:  log,
+		db:      db,
+		cache:   cacheClient,
+		workers: workers,
 	}, nil
 }
 
-// Start starts the HTTP server
+// Start starts the background workers and the HTTP server
 func (a *Application) Start() error {
+	for _, w := range a.workers {
+		w.Start()
+	}
+
 	return a.server.ListenAndServe()
 }
 
 // Stop gracefully shuts down the server
 func (a *Application) Stop(ctx context.Context) error {
+	// Stop workers before closing the connections they use
+	a.logger.Info().Msg("Stopping background workers...")
+	for _, w := range a.workers {
+		if err := w.Stop(ctx); err != nil {
+			a.logger.Error().Err(err).Msg("Error stopping background worker")
+		}
+	}
+
 	a.logger.Info().Msg("Closing database connections...")
 	if err := a.db.Close(); err != nil {
 		a.logger.Error().Err(err).Msg("Error closing database connections")
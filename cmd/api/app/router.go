@@ -16,8 +16,14 @@ func NewRouter(
 	userHandler *userHandler.UserHandler,
 	postHandler *contentHandler.PostHandler,
 	widgetHandler *contentHandler.WidgetHandler,
+	reactionHandler *contentHandler.ReactionHandler,
+	commentHandler *contentHandler.CommentHandler,
+	bookmarkHandler *contentHandler.BookmarkHandler,
+	mediaHandler *contentHandler.MediaHandler,
+	reportHandler *contentHandler.ReportHandler,
 	authHandler *userHandler.AuthHandler,
 	authMiddleware *middleware.AuthMiddleware,
+	accounts middleware.AccountChecker,
 	log logger.Logger,
 ) *gin.Engine {
 	// Set Gin mode based on environment
@@ -61,21 +67,36 @@ func NewRouter(
 			})
 		}
 
-		// Protected routes - require authentication
+		// Protected routes - require authentication by an active account
 		protected := v1.Group("")
-		protected.Use(authMiddleware.Authenticate())
+		protected.Use(authMiddleware.Authenticate(), authMiddleware.RequireActive(accounts))
 		{
 			// Register user routes that need authentication
 			userGroup := protected.Group("/users")
 			userHandler.RegisterProtectedRoutes(userGroup)
+			bookmarkHandler.RegisterProtectedUserRoutes(userGroup)
 
 			// Register protected post routes
 			postGroup := protected.Group("/posts")
 			postHandler.RegisterProtectedRoutes(postGroup)
+			reactionHandler.RegisterProtectedRoutes(postGroup)
+			commentHandler.RegisterProtectedRoutes(postGroup)
+			bookmarkHandler.RegisterProtectedRoutes(postGroup)
+
+			// Register protected media routes
+			mediaHandler.RegisterProtectedRoutes(protected.Group("/media"))
 
 			// Register protected widget routes
 			widgetGroup := protected.Group("/widgets")
 			widgetHandler.RegisterProtectedRoutes(widgetGroup)
+
+			// Register report routes
+			reportHandler.RegisterProtectedRoutes(protected.Group("/reports"))
+
+			// Admin routes - require the admin role
+			adminGroup := protected.Group("/admin")
+			adminGroup.Use(authMiddleware.RequireRole("admin"))
+			reportHandler.RegisterAdminRoutes(adminGroup)
 		}
 
 		// Optional authentication routes
@@ -83,13 +104,16 @@ func NewRouter(
 		optional.Use(authMiddleware.OptionalAuthenticate())
 		{
 			// Register user routes with optional authentication
-			userHandler.RegisterPublicRoutes(v1.Group("/users"))
+			userHandler.RegisterPublicRoutes(optional.Group("/users"))
 
 			// Register post routes with optional authentication
-			postHandler.RegisterPublicRoutes(v1.Group("/posts"))
+			publicPostGroup := optional.Group("/posts")
+			postHandler.RegisterPublicRoutes(publicPostGroup)
+			reactionHandler.RegisterPublicRoutes(publicPostGroup)
+			commentHandler.RegisterPublicRoutes(publicPostGroup)
 
 			// Register widget routes with optional authentication
-			widgetHandler.RegisterPublicRoutes(v1.Group("/widgets"))
+			widgetHandler.RegisterPublicRoutes(optional.Group("/widgets"))
 		}
 	}
 
@@ -39,6 +39,84 @@ type Config struct {
 		DefaultTTL time.Duration `mapstructure:"default_ttl"`
 	} `mapstructure:"cache"`
 
+	Scheduler struct {
+		Enabled   bool          `mapstructure:"enabled"`
+		Interval  time.Duration `mapstructure:"interval"`
+		BatchSize int           `mapstructure:"batch_size"`
+	} `mapstructure:"scheduler"`
+
+	Unfurl struct {
+		Enabled      bool          `mapstructure:"enabled"`
+		Interval     time.Duration `mapstructure:"interval"`
+		BatchSize    int           `mapstructure:"batch_size"`
+		Timeout      time.Duration `mapstructure:"timeout"`
+		MaxBodyBytes int64         `mapstructure:"max_body_bytes"`
+		UserAgent    string        `mapstructure:"user_agent"`
+	} `mapstructure:"unfurl"`
+
+	Feed struct {
+		FanOutMaxFollowers int           `mapstructure:"fan_out_max_followers"`
+		InvalidateInterval time.Duration `mapstructure:"invalidate_interval"`
+
+		Top struct {
+			Window          time.Duration      `mapstructure:"window"`
+			MaxCandidates   int                `mapstructure:"max_candidates"`
+			AffinityWindow  time.Duration      `mapstructure:"affinity_window"`
+			HalfLife        time.Duration      `mapstructure:"half_life"`
+			ReactionWeights map[string]float64 `mapstructure:"reaction_weights"`
+			CommentWeight   float64            `mapstructure:"comment_weight"`
+			AffinityWeight  float64            `mapstructure:"affinity_weight"`
+		} `mapstructure:"top"`
+
+		Explore struct {
+			Interval     time.Duration `mapstructure:"interval"`
+			Window       time.Duration `mapstructure:"window"`
+			Size         int           `mapstructure:"size"`
+			MaxPerAuthor int           `mapstructure:"max_per_author"`
+		} `mapstructure:"explore"`
+	} `mapstructure:"feed"`
+
+	Trash struct {
+		PurgeInterval  time.Duration `mapstructure:"purge_interval"`
+		PurgeBatchSize int           `mapstructure:"purge_batch_size"`
+	} `mapstructure:"trash"`
+
+	Media struct {
+		Storage        string        `mapstructure:"storage"`
+		MaxUploadBytes int64         `mapstructure:"max_upload_bytes"`
+		MaxPixels      int           `mapstructure:"max_pixels"`
+		MaxFrames      int           `mapstructure:"max_frames"`
+		ThumbnailSize  int           `mapstructure:"thumbnail_size"`
+		OrphanTTL      time.Duration `mapstructure:"orphan_ttl"`
+		GCInterval     time.Duration `mapstructure:"gc_interval"`
+
+		Local struct {
+			Dir     string `mapstructure:"dir"`
+			BaseURL string `mapstructure:"base_url"`
+		} `mapstructure:"local"`
+
+		S3 struct {
+			Endpoint        string `mapstructure:"endpoint"`
+			Region          string `mapstructure:"region"`
+			Bucket          string `mapstructure:"bucket"`
+			AccessKeyID     string `mapstructure:"access_key_id"`
+			SecretAccessKey string `mapstructure:"secret_access_key"`
+			PathStyle       bool   `mapstructure:"path_style"`
+			PublicURL       string `mapstructure:"public_url"`
+		} `mapstructure:"s3"`
+	} `mapstructure:"media"`
+
+	ContentFilter struct {
+		Enabled           bool          `mapstructure:"enabled"`
+		BlockedWords      []string      `mapstructure:"blocked_words"`
+		FlaggedWords      []string      `mapstructure:"flagged_words"`
+		MaxLinks          int           `mapstructure:"max_links"`
+		MaxLinkRatio      float64       `mapstructure:"max_link_ratio"`
+		RepeatWindow      time.Duration `mapstructure:"repeat_window"`
+		RepeatFlagAfter   int           `mapstructure:"repeat_flag_after"`
+		RepeatRejectAfter int           `mapstructure:"repeat_reject_after"`
+	} `mapstructure:"content_filter"`
+
 	LogLevel string `mapstructure:"log_level"`
 }
 
@@ -64,6 +142,56 @@ func Load() (*Config, error) {
 	viper.SetDefault("cache.type", "memory")
 	viper.SetDefault("cache.default_ttl", time.Minute*5)
 
+	viper.SetDefault("scheduler.enabled", true)
+	viper.SetDefault("scheduler.interval", time.Second*30)
+	viper.SetDefault("scheduler.batch_size", 100)
+
+	viper.SetDefault("unfurl.enabled", true)
+	viper.SetDefault("unfurl.interval", time.Second*10)
+	viper.SetDefault("unfurl.batch_size", 20)
+	viper.SetDefault("unfurl.timeout", time.Second*5)
+	viper.SetDefault("unfurl.max_body_bytes", 512*1024)
+
+	viper.SetDefault("feed.fan_out_max_followers", 10000)
+	viper.SetDefault("feed.invalidate_interval", time.Second*5)
+	viper.SetDefault("feed.top.window", time.Hour*72)
+	viper.SetDefault("feed.top.max_candidates", 500)
+	viper.SetDefault("feed.top.affinity_window", time.Hour*24*30)
+	viper.SetDefault("feed.top.half_life", time.Hour*12)
+	viper.SetDefault("feed.top.reaction_weights", map[string]float64{
+		"like":       1,
+		"celebrate":  1.5,
+		"support":    1.5,
+		"insightful": 2,
+		"curious":    1.5,
+	})
+	viper.SetDefault("feed.top.comment_weight", 3)
+	viper.SetDefault("feed.top.affinity_weight", 0.5)
+	viper.SetDefault("feed.explore.interval", time.Minute*5)
+	viper.SetDefault("feed.explore.window", time.Hour*48)
+	viper.SetDefault("feed.explore.size", 200)
+	viper.SetDefault("feed.explore.max_per_author", 2)
+
+	viper.SetDefault("trash.purge_interval", time.Hour)
+	viper.SetDefault("trash.purge_batch_size", 500)
+
+	viper.SetDefault("media.storage", "local")
+	viper.SetDefault("media.max_upload_bytes", 10*1024*1024)
+	viper.SetDefault("media.max_pixels", 40_000_000)
+	viper.SetDefault("media.max_frames", 500)
+	viper.SetDefault("media.thumbnail_size", 400)
+	viper.SetDefault("media.orphan_ttl", time.Hour*24)
+	viper.SetDefault("media.gc_interval", time.Hour)
+	viper.SetDefault("media.local.dir", "./uploads")
+	viper.SetDefault("media.local.base_url", "http://localhost:8080/uploads")
+
+	viper.SetDefault("content_filter.enabled", true)
+	viper.SetDefault("content_filter.max_links", 5)
+	viper.SetDefault("content_filter.max_link_ratio", 0.5)
+	viper.SetDefault("content_filter.repeat_window", time.Hour)
+	viper.SetDefault("content_filter.repeat_flag_after", 2)
+	viper.SetDefault("content_filter.repeat_reject_after", 4)
+
 	viper.SetDefault("log_level", "info")
 
 	// Read configuration
@@ -1,11 +1,15 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
 	"time"
 
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
@@ -184,6 +188,75 @@ func (m *AuthMiddleware) OptionalAuthenticate() gin.HandlerFunc {
 	}
 }
 
+// RequireRole rejects requests whose token does not carry the given role.
+// It must run after Authenticate, which sets the roles in the context.
+func (m *AuthMiddleware) RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hasRole(c, role) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "insufficient permissions"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AccountChecker looks up the account behind an authenticated request
+type AccountChecker interface {
+	GetUserByID(ctx context.Context, id string) (*model.User, error)
+}
+
+// RequireActive rejects requests from suspended accounts, whose tokens stay
+// valid until they expire. It must run after Authenticate, which sets the
+// user ID in the context.
+func (m *AuthMiddleware) RequireActive(accounts AccountChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("userID")
+
+		user, err := accounts.GetUserByID(c.Request.Context(), userID)
+		if err != nil {
+			var appErr *apperrors.Error
+			if errors.As(err, &appErr) && appErr.Type() == apperrors.ErrTypeNotFound {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "user not found"})
+			} else {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load user"})
+			}
+			c.Abort()
+			return
+		}
+
+		if !user.IsActive {
+			c.JSON(http.StatusForbidden, gin.H{"error": "your account is suspended"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// hasRole reports whether the authenticated user's token carries the given role
+func hasRole(c *gin.Context, role string) bool {
+	roles, ok := c.Get("userRoles")
+	if !ok {
+		return false
+	}
+
+	list, ok := roles.([]interface{})
+	if !ok {
+		return false
+	}
+
+	for _, r := range list {
+		if name, ok := r.(string); ok && name == role {
+			return true
+		}
+	}
+
+	return false
+}
+
 // GenerateToken creates a new JWT token for a user
 func (m *AuthMiddleware) GenerateToken(userID string, roles []string, expiration time.Duration) (string, error) {
 	// Create the token
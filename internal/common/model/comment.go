@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+)
+
+// MaxCommentDepth is the number of nesting levels allowed in a comment thread.
+// Top-level comments have depth 0.
+const MaxCommentDepth = 3
+
+// Comment represents a comment on a post, optionally replying to another comment
+type Comment struct {
+	ID         string     `json:"id"`
+	PostID     string     `json:"postId"`
+	UserID     string     `json:"userId"`
+	ParentID   *string    `json:"parentId,omitempty"`
+	Depth      int        `json:"depth"`
+	Content    string     `json:"content"`
+	ReplyCount int        `json:"replyCount"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  *time.Time `json:"updatedAt,omitempty"`
+
+	// Optional joined fields
+	Author *User `json:"author,omitempty"`
+}
+
+// CreateCommentRequest is used when commenting on a post or replying to a comment
+type CreateCommentRequest struct {
+	Content  string  `json:"content" validate:"required,max=1000"`
+	ParentID *string `json:"parentId,omitempty"`
+}
+
+// UpdateCommentRequest is used when editing a comment
+type UpdateCommentRequest struct {
+	Content string `json:"content" validate:"required,max=1000"`
+}
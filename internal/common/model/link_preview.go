@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+)
+
+// LinkPreviewStatus is the fetch state of a link preview
+type LinkPreviewStatus string
+
+const (
+	LinkPreviewStatusPending  LinkPreviewStatus = "pending"
+	LinkPreviewStatusFetching LinkPreviewStatus = "fetching"
+	LinkPreviewStatusOK       LinkPreviewStatus = "ok"
+	LinkPreviewStatusFailed   LinkPreviewStatus = "failed"
+)
+
+// LinkPreview is the OpenGraph or Twitter Card metadata of an embedded URL.
+// Previews are shared by every post that embeds the same URL.
+type LinkPreview struct {
+	URL         string  `json:"url"`
+	Title       string  `json:"title,omitempty"`
+	Description string  `json:"description,omitempty"`
+	ImageURL    *string `json:"imageUrl,omitempty"`
+	SiteName    string  `json:"siteName,omitempty"`
+	// FinalURL is where URL redirected to, when it differs
+	FinalURL  *string   `json:"finalUrl,omitempty"`
+	FetchedAt time.Time `json:"fetchedAt"`
+}
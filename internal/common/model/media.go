@@ -0,0 +1,27 @@
+package model
+
+import (
+	"time"
+)
+
+// MaxPostMedia is the number of media attachments a post can have
+const MaxPostMedia = 4
+
+// Media is an uploaded image. It belongs to its uploader until a post
+// references it; uploads that are never attached are removed after a while.
+type Media struct {
+	ID           string    `json:"id"`
+	UserID       string    `json:"userId"`
+	PostID       *string   `json:"postId,omitempty"`
+	ContentType  string    `json:"contentType"`
+	Size         int64     `json:"size"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	URL          string    `json:"url"`
+	ThumbnailURL string    `json:"thumbnailUrl"`
+	CreatedAt    time.Time `json:"createdAt"`
+
+	// Storage keys, not exposed to clients
+	StorageKey   string `json:"-"`
+	ThumbnailKey string `json:"-"`
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"time"
+)
+
+const (
+	// MinPollDuration is the shortest time a poll can stay open
+	MinPollDuration = 5 * time.Minute
+	// MaxPollDuration is the longest time a poll can stay open
+	MaxPollDuration = 7 * 24 * time.Hour
+)
+
+// Poll is a question attached to a post. Vote counts are only filled in once
+// the viewer has voted or the poll has closed.
+type Poll struct {
+	PostID   string        `json:"postId"`
+	Options  []*PollOption `json:"options"`
+	ClosesAt time.Time     `json:"closesAt"`
+	Closed   bool          `json:"closed"`
+	// TotalVotes is hidden along with the option counts
+	TotalVotes *int `json:"totalVotes,omitempty"`
+	// ViewerOptionID is the option the viewer voted for
+	ViewerOptionID *string `json:"viewerOptionId,omitempty"`
+}
+
+// PollOption is one of the answers to a poll
+type PollOption struct {
+	ID        string `json:"id"`
+	Position  int    `json:"position"`
+	Text      string `json:"text"`
+	VoteCount *int   `json:"voteCount,omitempty"`
+}
+
+// CreatePollRequest is used to attach a poll to a new post
+type CreatePollRequest struct {
+	Options  []string  `json:"options" validate:"required,min=2,max=4,dive,required,max=80"`
+	ClosesAt time.Time `json:"closesAt" validate:"required"`
+}
+
+// PollVoteRequest is used when voting in a poll
+type PollVoteRequest struct {
+	OptionID string `json:"optionId" validate:"required,uuid"`
+}
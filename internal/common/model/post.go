@@ -8,38 +8,165 @@ import (
 type Visibility string
 
 const (
-	VisibilityPublic  Visibility = "public"
-	VisibilityPrivate Visibility = "private"
+	VisibilityPublic    Visibility = "public"
+	VisibilityFollowers Visibility = "followers"
+	VisibilityPrivate   Visibility = "private"
+)
+
+// PostStatus is the publication state of a post
+type PostStatus string
+
+const (
+	PostStatusPublished PostStatus = "published"
+	PostStatusScheduled PostStatus = "scheduled"
+	PostStatusDraft     PostStatus = "draft"
+)
+
+// FeedMode is the order a home feed is listed in
+type FeedMode string
+
+const (
+	// FeedModeLatest lists the newest posts first
+	FeedModeLatest FeedMode = "latest"
+	// FeedModeTop lists the posts the viewer is most likely to engage with first
+	FeedModeTop FeedMode = "top"
+)
+
+// MaxPinnedPosts is the number of posts a user can pin to their profile
+const MaxPinnedPosts = 3
+
+// TrashRetention is how long deleted posts can be restored before they are purged
+const TrashRetention = 30 * 24 * time.Hour
+
+// PostKind distinguishes original posts from shares of other posts
+type PostKind string
+
+const (
+	PostKindPost   PostKind = "post"
+	PostKindRepost PostKind = "repost"
+	PostKindQuote  PostKind = "quote"
 )
 
 // Post represents a user post
 type Post struct {
-	ID         string     `json:"id"`
-	UserID     string     `json:"userId"`
-	Content    string     `json:"content"`
-	EmbedURLs  []string   `json:"embedUrls,omitempty"`
-	Hashtags   []string   `json:"hashtags,omitempty"`
+	ID        string   `json:"id"`
+	UserID    string   `json:"userId"`
+	Content   string   `json:"content"`
+	EmbedURLs []string `json:"embedUrls,omitempty"`
+	Hashtags  []string `json:"hashtags,omitempty"`
+	// MediaIDs are uploads to attach when the post is created
+	MediaIDs   []string   `json:"-"`
 	Visibility Visibility `json:"visibility"`
+	Status     PostStatus `json:"status"`
+	PublishAt  *time.Time `json:"publishAt,omitempty"`
 	CreatedAt  time.Time  `json:"createdAt"`
 	UpdatedAt  *time.Time `json:"updatedAt,omitempty"`
+	// RevisionCount is the number of times the post has been edited
+	RevisionCount int       `json:"revisionCount"`
+	Mentions      []Mention `json:"mentions,omitempty"`
+
+	// Kind is repost or quote when the post shares OriginalPostID. The ID is
+	// cleared when the original is deleted.
+	Kind           PostKind `json:"kind"`
+	OriginalPostID *string  `json:"originalPostId,omitempty"`
+	// RepostCount counts reposts and quotes of this post
+	RepostCount int `json:"repostCount"`
+	// Pinned posts are listed first on their author's profile, most recently
+	// pinned first
+	Pinned   bool       `json:"pinned"`
+	PinnedAt *time.Time `json:"pinnedAt,omitempty"`
+	// DeletedAt is set while the post is in its author's trash
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
+	// HiddenAt is set when a moderator hid the post from everyone but its author
+	HiddenAt *time.Time `json:"hiddenAt,omitempty"`
 
 	// Optional joined fields
-	Author    *User      `json:"author,omitempty"`
-	Reactions []Reaction `json:"reactions,omitempty"`
+	Author          *User            `json:"author,omitempty"`
+	ReactionSummary *ReactionSummary `json:"reactionSummary,omitempty"`
+	// LinkPreviews holds the previews of EmbedURLs that have been fetched
+	LinkPreviews []*LinkPreview `json:"linkPreviews,omitempty"`
+	Media        []*Media       `json:"media,omitempty"`
+	Search       *SearchMatch   `json:"search,omitempty"`
+	// Poll is set for posts that ask a question
+	Poll *Poll `json:"poll,omitempty"`
+	// OriginalPost is the shared post, when the viewer may see it
+	OriginalPost *Post `json:"originalPost,omitempty"`
+}
+
+// PostRevision is a version of a post as it was before an edit
+type PostRevision struct {
+	PostID    string    `json:"postId"`
+	Revision  int       `json:"revision"`
+	Content   string    `json:"content"`
+	EmbedURLs []string  `json:"embedUrls,omitempty"`
+	Hashtags  []string  `json:"hashtags,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SearchMatch describes how a post matched a full-text search.
+// Snippet is HTML-escaped, with matched terms wrapped in <mark></mark>.
+type SearchMatch struct {
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+// Mention is a resolved @username reference in a post's content.
+// Start and End are character offsets into the content, End exclusive.
+type Mention struct {
+	UserID   string `json:"userId"`
+	Username string `json:"username"`
+	Start    int    `json:"start"`
+	End      int    `json:"end"`
 }
 
 // CreatePostRequest is used when creating a new post
 type CreatePostRequest struct {
 	Content   string   `json:"content" validate:"required,max=500"`
-	EmbedURLs []string `json:"embedUrls,omitempty" validate:"omitempty,dive,url,max=3"`
-	Hashtags  []string `json:"hashtags,omitempty" validate:"omitempty,dive,max=30,max=5"`
+	EmbedURLs []string `json:"embedUrls,omitempty" validate:"omitempty,max=3,dive,url,max=2048"`
+	Hashtags  []string `json:"hashtags,omitempty" validate:"omitempty,max=5,dive,max=30"`
+	// MediaIDs are images uploaded through /media, in display order
+	MediaIDs []string `json:"mediaIds,omitempty" validate:"omitempty,max=4,dive,uuid"`
+	// Poll attaches a poll to the post
+	Poll *CreatePollRequest `json:"poll,omitempty"`
+	// Visibility defaults to public
+	Visibility Visibility `json:"visibility,omitempty" validate:"omitempty,oneof=public followers private"`
+	// PublishAt schedules the post for later. Times in the past publish immediately.
+	PublishAt *time.Time `json:"publishAt,omitempty"`
 }
 
 // UpdatePostRequest is used when updating an existing post
 type UpdatePostRequest struct {
 	Content   string   `json:"content" validate:"required,max=500"`
-	EmbedURLs []string `json:"embedUrls,omitempty" validate:"omitempty,dive,url,max=3"`
-	Hashtags  []string `json:"hashtags,omitempty" validate:"omitempty,dive,max=30,max=5"`
+	EmbedURLs []string `json:"embedUrls,omitempty" validate:"omitempty,max=3,dive,url,max=2048"`
+	Hashtags  []string `json:"hashtags,omitempty" validate:"omitempty,max=5,dive,max=30"`
+	// Visibility is left unchanged when omitted
+	Visibility Visibility `json:"visibility,omitempty" validate:"omitempty,oneof=public followers private"`
+}
+
+// CreateDraftRequest is used when starting a new draft. Drafts are checked
+// against the post limits only when they are published.
+type CreateDraftRequest struct {
+	Content   string   `json:"content" validate:"max=10000"`
+	EmbedURLs []string `json:"embedUrls,omitempty" validate:"omitempty,max=20"`
+	Hashtags  []string `json:"hashtags,omitempty" validate:"omitempty,max=50"`
+}
+
+// UpdateDraftRequest is used when autosaving a draft. Omitted fields are left unchanged.
+type UpdateDraftRequest struct {
+	Content   *string  `json:"content,omitempty" validate:"omitempty,max=10000"`
+	EmbedURLs []string `json:"embedUrls,omitempty" validate:"omitempty,max=20"`
+	Hashtags  []string `json:"hashtags,omitempty" validate:"omitempty,max=50"`
+}
+
+// PublishDraftRequest is used when publishing a draft, optionally scheduling it
+type PublishDraftRequest struct {
+	Visibility Visibility `json:"visibility,omitempty" validate:"omitempty,oneof=public followers private"`
+	PublishAt  *time.Time `json:"publishAt,omitempty"`
+}
+
+// RepostRequest is used when sharing a post. A quote turns the repost into a quote post.
+type RepostRequest struct {
+	Quote string `json:"quote,omitempty" validate:"max=500"`
 }
 
 // FeedRequest is used to get a user's feed
@@ -53,3 +180,10 @@ type FeedResponse struct {
 	Posts []Post `json:"posts"`
 	Total int    `json:"total"`
 }
+
+// HashtagTrend is a hashtag with its recent usage
+type HashtagTrend struct {
+	Tag         string `json:"tag"`
+	PostCount   int    `json:"postCount"`
+	AuthorCount int    `json:"authorCount"`
+}
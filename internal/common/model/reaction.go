@@ -33,3 +33,10 @@ type ReactionRequest struct {
 	PostID string       `json:"postId" validate:"required"`
 	Type   ReactionType `json:"type" validate:"required,oneof=like celebrate support insightful curious"`
 }
+
+// ReactionSummary aggregates the reactions on a post
+type ReactionSummary struct {
+	Counts         map[ReactionType]int `json:"counts"`
+	Total          int                  `json:"total"`
+	ViewerReaction *ReactionType        `json:"viewerReaction,omitempty"`
+}
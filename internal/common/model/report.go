@@ -0,0 +1,96 @@
+package model
+
+import (
+	"time"
+)
+
+// ReportTargetType is the kind of thing a report is about
+type ReportTargetType string
+
+const (
+	ReportTargetPost    ReportTargetType = "post"
+	ReportTargetComment ReportTargetType = "comment"
+	ReportTargetUser    ReportTargetType = "user"
+)
+
+// ReportReason is the category a reporter picks for a report
+type ReportReason string
+
+const (
+	ReportReasonSpam           ReportReason = "spam"
+	ReportReasonHarassment     ReportReason = "harassment"
+	ReportReasonHateSpeech     ReportReason = "hate_speech"
+	ReportReasonViolence       ReportReason = "violence"
+	ReportReasonSexualContent  ReportReason = "sexual_content"
+	ReportReasonMisinformation ReportReason = "misinformation"
+	ReportReasonImpersonation  ReportReason = "impersonation"
+	ReportReasonOther          ReportReason = "other"
+	// ReportReasonContentFilter marks reports filed by the content filters
+	// rather than by a user
+	ReportReasonContentFilter ReportReason = "content_filter"
+)
+
+// ReportStatus is the state of a report in the moderation queue
+type ReportStatus string
+
+const (
+	ReportStatusOpen      ReportStatus = "open"
+	ReportStatusActioned  ReportStatus = "actioned"
+	ReportStatusDismissed ReportStatus = "dismissed"
+)
+
+// Report flags a post, comment or user for review by a moderator
+type Report struct {
+	ID string `json:"id"`
+	// ReporterID is empty for reports filed by the content filters
+	ReporterID *string          `json:"reporterId,omitempty"`
+	TargetType ReportTargetType `json:"targetType"`
+	TargetID   string           `json:"targetId"`
+	// TargetUserID is the author of the reported content, or the reported user
+	TargetUserID string       `json:"targetUserId"`
+	Reason       ReportReason `json:"reason"`
+	Details      *string      `json:"details,omitempty"`
+	Status       ReportStatus `json:"status"`
+	CreatedAt    time.Time    `json:"createdAt"`
+	ResolvedAt   *time.Time   `json:"resolvedAt,omitempty"`
+	ResolvedBy   *string      `json:"resolvedBy,omitempty"`
+}
+
+// CreateReportRequest is used when reporting a post, comment or user
+type CreateReportRequest struct {
+	TargetType ReportTargetType `json:"targetType" validate:"required,oneof=post comment user"`
+	TargetID   string           `json:"targetId" validate:"required,max=256"`
+	Reason     ReportReason     `json:"reason" validate:"required,oneof=spam harassment hate_speech violence sexual_content misinformation impersonation other"`
+	Details    *string          `json:"details,omitempty" validate:"omitempty,max=1000"`
+}
+
+// ModerationActionType is what a moderator did about a report
+type ModerationActionType string
+
+const (
+	// ModerationActionHide hides a post or comment from everyone but its author
+	ModerationActionHide ModerationActionType = "hide"
+	// ModerationActionSuspend deactivates the account of the reported user or author
+	ModerationActionSuspend ModerationActionType = "suspend"
+	// ModerationActionDismiss closes the reports without changing anything
+	ModerationActionDismiss ModerationActionType = "dismiss"
+)
+
+// ModerationAction is an entry in the moderation audit trail
+type ModerationAction struct {
+	ID           string               `json:"id"`
+	ModeratorID  string               `json:"moderatorId"`
+	Action       ModerationActionType `json:"action"`
+	TargetType   ReportTargetType     `json:"targetType"`
+	TargetID     string               `json:"targetId"`
+	TargetUserID string               `json:"targetUserId"`
+	ReportID     *string              `json:"reportId,omitempty"`
+	Note         *string              `json:"note,omitempty"`
+	CreatedAt    time.Time            `json:"createdAt"`
+}
+
+// ModerationActionRequest is used when a moderator resolves a report
+type ModerationActionRequest struct {
+	Action ModerationActionType `json:"action" validate:"required,oneof=hide suspend dismiss"`
+	Note   *string              `json:"note,omitempty" validate:"omitempty,max=1000"`
+}
@@ -29,6 +29,8 @@ type User struct {
 	IsActive     bool         `json:"isActive"`
 	CreatedAt    time.Time    `json:"createdAt"`
 	UpdatedAt    *time.Time   `json:"updatedAt,omitempty"`
+	// FollowedAt is when the follow was made, in follower and following lists
+	FollowedAt *time.Time `json:"followedAt,omitempty"`
 }
 
 // CreateUserRequest is used when creating a new user
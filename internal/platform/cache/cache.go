@@ -1,6 +1,8 @@
 package cache
 
 import (
+	"encoding/json"
+	"reflect"
 	"time"
 )
 
@@ -10,6 +12,9 @@ type Cache interface {
 	Set(key string, value interface{}, ttl time.Duration)
 	Delete(key string)
 	Clear()
+	// Incr atomically adds one to the integer stored at key, starting from
+	// zero, and returns the new value. The key expires after ttl.
+	Incr(key string, ttl time.Duration) (int64, error)
 }
 
 // Factory function to create the right cache implementation
@@ -33,3 +38,31 @@ func NewCache(cacheType string, options ...interface{}) Cache {
 	}
 	return NewInMemoryCache(5 * time.Minute)
 }
+
+// GetInto retrieves a value from the cache into dst, which must be a non-nil pointer.
+// RedisCache returns decoded JSON rather than the original Go type, so values that
+// are not directly assignable are converted through JSON.
+func GetInto(c Cache, key string, dst interface{}) bool {
+	value, found := c.Get(key)
+	if !found {
+		return false
+	}
+
+	target := reflect.ValueOf(dst)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return false
+	}
+
+	source := reflect.ValueOf(value)
+	if source.IsValid() && source.Type().AssignableTo(target.Elem().Type()) {
+		target.Elem().Set(source)
+		return true
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+
+	return json.Unmarshal(data, dst) == nil
+}
@@ -1,6 +1,7 @@
 package cache
 
 import (
+	"fmt"
 	"sync"
 	"time"
 )
@@ -73,6 +74,40 @@ func (c *InMemoryCache) Delete(key string) {
 	c.mu.Unlock()
 }
 
+// Incr adds one to the integer stored at key
+func (c *InMemoryCache) Incr(key string, ttl time.Duration) (int64, error) {
+	if ttl == 0 {
+		ttl = c.ttl
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var count int64
+	if existing, found := c.items[key]; found && (existing.expiration == 0 || existing.expiration >= time.Now().UnixNano()) {
+		switch value := existing.value.(type) {
+		case int64:
+			count = value
+		case int:
+			count = int64(value)
+		default:
+			return 0, fmt.Errorf("increment %s: value is not an integer", key)
+		}
+	}
+	count++
+
+	var expiration int64
+	if ttl > 0 {
+		expiration = time.Now().Add(ttl).UnixNano()
+	}
+	c.items[key] = item{
+		value:      count,
+		expiration: expiration,
+	}
+
+	return count, nil
+}
+
 // Clear removes all values from the cache
 func (c *InMemoryCache) Clear() {
 	c.mu.Lock()
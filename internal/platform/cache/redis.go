@@ -65,6 +65,24 @@ func (c *RedisCache) Delete(key string) {
 	c.client.Del(c.ctx, key)
 }
 
+// Incr adds one to the integer stored at key and resets its expiry, in a
+// single transaction
+func (c *RedisCache) Incr(key string, ttl time.Duration) (int64, error) {
+	var incr *redis.IntCmd
+	_, err := c.client.TxPipelined(c.ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(c.ctx, key)
+		if ttl > 0 {
+			pipe.Expire(c.ctx, key, ttl)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("increment %s: %w", key, err)
+	}
+
+	return incr.Val(), nil
+}
+
 // Clear removes all values with a specific prefix
 // Note: Redis doesn't have a direct "clear all" for a namespace without using KEYS
 // which is not recommended for production
@@ -0,0 +1,76 @@
+package imaging
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+var errMalformedGIF = errors.New("imaging: malformed gif")
+
+// gifFrames walks the blocks of a GIF file without decoding any pixel data and
+// returns the number of frames and the pixels of all frames together
+func gifFrames(data []byte) (frames int, pixels int, err error) {
+	// Header and logical screen descriptor
+	if len(data) < 13 {
+		return 0, 0, errMalformedGIF
+	}
+	pos := 13
+	if flags := data[10]; flags&0x80 != 0 {
+		pos += 3 << ((flags & 0x07) + 1)
+	}
+
+	for pos < len(data) {
+		switch data[pos] {
+		case 0x21: // Extension: label, then data sub-blocks
+			pos, err = skipSubBlocks(data, pos+2)
+			if err != nil {
+				return 0, 0, err
+			}
+
+		case 0x2C: // Image descriptor, optional local color table, then LZW data
+			if pos+10 > len(data) {
+				return 0, 0, errMalformedGIF
+			}
+			width := int(binary.LittleEndian.Uint16(data[pos+5:]))
+			height := int(binary.LittleEndian.Uint16(data[pos+7:]))
+			flags := data[pos+9]
+
+			frames++
+			pixels += width * height
+
+			pos += 10
+			if flags&0x80 != 0 {
+				pos += 3 << ((flags & 0x07) + 1)
+			}
+			// Skip the LZW minimum code size
+			pos, err = skipSubBlocks(data, pos+1)
+			if err != nil {
+				return 0, 0, err
+			}
+
+		case 0x3B: // Trailer
+			return frames, pixels, nil
+
+		default:
+			return 0, 0, errMalformedGIF
+		}
+	}
+
+	return 0, 0, errMalformedGIF
+}
+
+// skipSubBlocks returns the position after the sub-blocks starting at pos,
+// which end with an empty block
+func skipSubBlocks(data []byte, pos int) (int, error) {
+	for {
+		if pos >= len(data) {
+			return 0, errMalformedGIF
+		}
+		size := int(data[pos])
+		pos++
+		if size == 0 {
+			return pos, nil
+		}
+		pos += size
+	}
+}
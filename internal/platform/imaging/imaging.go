@@ -0,0 +1,230 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+var (
+	// ErrUnsupportedFormat is returned for files that are not JPEG, PNG or GIF images
+	ErrUnsupportedFormat = errors.New("imaging: unsupported image format")
+	// ErrTooLarge is returned for images with more pixels than allowed
+	ErrTooLarge = errors.New("imaging: image dimensions are too large")
+	// ErrTooManyFrames is returned for GIFs with more frames than allowed
+	ErrTooManyFrames = errors.New("imaging: image has too many frames")
+)
+
+const jpegQuality = 85
+
+// Options controls how images are processed
+type Options struct {
+	// MaxPixels bounds width * height, checked before the image is decoded.
+	// For GIFs it also bounds the pixels of all frames together.
+	MaxPixels int
+	// MaxFrames bounds the number of frames of a GIF
+	MaxFrames int
+	// ThumbnailSize is the longest side of the generated thumbnail
+	ThumbnailSize int
+}
+
+// Result is a processed image and its thumbnail
+type Result struct {
+	Data        []byte
+	ContentType string
+	// Extension is the file extension matching ContentType, without a dot
+	Extension string
+	Width     int
+	Height    int
+
+	Thumbnail            []byte
+	ThumbnailContentType string
+	ThumbnailExtension   string
+}
+
+// DetectContentType sniffs the content type of an image from its first bytes
+func DetectContentType(data []byte) string {
+	return http.DetectContentType(data)
+}
+
+// Process validates an image and re-encodes it, which drops EXIF and any other
+// metadata the original carried. JPEG orientation is applied to the pixels
+// first so photos keep their intended rotation.
+func Process(data []byte, opts Options) (*Result, error) {
+	if opts.MaxPixels <= 0 {
+		opts.MaxPixels = 40_000_000
+	}
+	if opts.ThumbnailSize <= 0 {
+		opts.ThumbnailSize = 400
+	}
+	if opts.MaxFrames <= 0 {
+		opts.MaxFrames = 500
+	}
+
+	contentType := DetectContentType(data)
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	// Check the dimensions before decoding, so a small file that expands
+	// to a huge bitmap is rejected cheaply
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > opts.MaxPixels {
+		return nil, ErrTooLarge
+	}
+
+	// Every frame of a GIF is decoded into its own bitmap, so the frames are
+	// counted before any of them is
+	if contentType == "image/gif" {
+		frames, pixels, err := gifFrames(data)
+		if err != nil {
+			return nil, ErrUnsupportedFormat
+		}
+		if frames > opts.MaxFrames {
+			return nil, ErrTooManyFrames
+		}
+		if pixels > opts.MaxPixels {
+			return nil, ErrTooLarge
+		}
+	}
+
+	result := &Result{ContentType: contentType}
+	var first image.Image
+	var out bytes.Buffer
+
+	switch contentType {
+	case "image/jpeg":
+		img, err := jpeg.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, ErrUnsupportedFormat
+		}
+		img = orient(img, jpegOrientation(data))
+		if err := jpeg.Encode(&out, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, fmt.Errorf("imaging: encode jpeg: %w", err)
+		}
+		first = img
+		result.Extension = "jpg"
+
+	case "image/png":
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, ErrUnsupportedFormat
+		}
+		if err := png.Encode(&out, img); err != nil {
+			return nil, fmt.Errorf("imaging: encode png: %w", err)
+		}
+		first = img
+		result.Extension = "png"
+
+	case "image/gif":
+		// Re-encoding keeps the frames and timing but drops comments and
+		// application extensions
+		anim, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil || len(anim.Image) == 0 {
+			return nil, ErrUnsupportedFormat
+		}
+		if err := gif.EncodeAll(&out, anim); err != nil {
+			return nil, fmt.Errorf("imaging: encode gif: %w", err)
+		}
+		first = anim.Image[0]
+		result.Extension = "gif"
+	}
+
+	result.Data = out.Bytes()
+	bounds := first.Bounds()
+	result.Width = bounds.Dx()
+	result.Height = bounds.Dy()
+
+	thumb := Thumbnail(first, opts.ThumbnailSize)
+	var thumbOut bytes.Buffer
+
+	// Keep transparency in PNG thumbnails; everything else becomes JPEG
+	if contentType == "image/png" {
+		if err := png.Encode(&thumbOut, thumb); err != nil {
+			return nil, fmt.Errorf("imaging: encode thumbnail: %w", err)
+		}
+		result.ThumbnailContentType = "image/png"
+		result.ThumbnailExtension = "png"
+	} else {
+		if err := jpeg.Encode(&thumbOut, flatten(thumb), &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, fmt.Errorf("imaging: encode thumbnail: %w", err)
+		}
+		result.ThumbnailContentType = "image/jpeg"
+		result.ThumbnailExtension = "jpg"
+	}
+	result.Thumbnail = thumbOut.Bytes()
+
+	return result, nil
+}
+
+// Thumbnail scales an image down so its longest side is at most size,
+// averaging the source pixels each target pixel covers. Smaller images are
+// copied unscaled.
+func Thumbnail(src image.Image, size int) *image.NRGBA {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	tw, th := w, h
+	if w > size || h > size {
+		if w >= h {
+			tw, th = size, max(1, h*size/w)
+		} else {
+			tw, th = max(1, w*size/h), size
+		}
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, tw, th))
+
+	for y := 0; y < th; y++ {
+		y0 := bounds.Min.Y + y*h/th
+		y1 := max(y0+1, bounds.Min.Y+(y+1)*h/th)
+
+		for x := 0; x < tw; x++ {
+			x0 := bounds.Min.X + x*w/tw
+			x1 := max(x0+1, bounds.Min.X+(x+1)*w/tw)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					b += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+
+			// Average in premultiplied space, then convert back
+			pixel := color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			}
+			dst.Set(x, y, pixel)
+		}
+	}
+
+	return dst
+}
+
+// flatten draws an image over a white background, for formats without alpha
+func flatten(src image.Image) image.Image {
+	dst := image.NewRGBA(src.Bounds())
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), src, src.Bounds().Min, draw.Over)
+	return dst
+}
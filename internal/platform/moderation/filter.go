@@ -0,0 +1,132 @@
+package moderation
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// Kind is the kind of user-submitted content being checked
+type Kind string
+
+const (
+	KindPost   Kind = "post"
+	KindBio    Kind = "bio"
+	KindWidget Kind = "widget"
+)
+
+// Content is user-submitted text checked before it is stored
+type Content struct {
+	UserID string
+	Kind   Kind
+	Text   string
+	// Links are URLs submitted alongside the text, such as a post's embeds
+	Links []string
+}
+
+// Action is what should happen to checked content. Actions are ordered by
+// severity, so the larger of two actions is the stricter one.
+type Action int
+
+const (
+	// Allow stores the content
+	Allow Action = iota
+	// Flag stores the content and queues it for review by a moderator
+	Flag
+	// Reject refuses the content
+	Reject
+)
+
+// String returns the name of the action
+func (a Action) String() string {
+	switch a {
+	case Allow:
+		return "allow"
+	case Flag:
+		return "flag"
+	case Reject:
+		return "reject"
+	default:
+		return "unknown"
+	}
+}
+
+// Verdict is the outcome of checking content
+type Verdict struct {
+	Action Action
+	// Reasons explain why the content was flagged or rejected
+	Reasons []string
+}
+
+// ContentFilter checks user-submitted content
+type ContentFilter interface {
+	Check(ctx context.Context, content *Content) (Verdict, error)
+}
+
+// ContentRecorder is implemented by filters that keep track of the content
+// users store, such as how often it was posted
+type ContentRecorder interface {
+	Record(ctx context.Context, content *Content) error
+}
+
+// Chain runs a list of filters as one. The strictest verdict wins, and the
+// reasons of every filter that did not allow the content are kept, so users
+// see all of the problems at once.
+type Chain struct {
+	filters []ContentFilter
+}
+
+// NewChain creates a chain of filters. An empty chain allows everything.
+func NewChain(filters ...ContentFilter) *Chain {
+	return &Chain{
+		filters: filters,
+	}
+}
+
+// Check runs every filter in the chain
+func (c *Chain) Check(ctx context.Context, content *Content) (Verdict, error) {
+	verdict := Verdict{Action: Allow}
+
+	for _, filter := range c.filters {
+		v, err := filter.Check(ctx, content)
+		if err != nil {
+			return Verdict{}, err
+		}
+
+		if v.Action == Allow {
+			continue
+		}
+
+		if v.Action > verdict.Action {
+			verdict.Action = v.Action
+		}
+		verdict.Reasons = append(verdict.Reasons, v.Reasons...)
+	}
+
+	return verdict, nil
+}
+
+// Record passes stored content to every filter in the chain that records it
+func (c *Chain) Record(ctx context.Context, content *Content) error {
+	for _, filter := range c.filters {
+		recorder, ok := filter.(ContentRecorder)
+		if !ok {
+			continue
+		}
+
+		if err := recorder.Record(ctx, content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// normalizeText lowercases text and reduces it to its words separated by
+// single spaces, so that case, punctuation and spacing tricks do not matter
+func normalizeText(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,64 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// LinkDensityFilter catches link spam. Content with more links than allowed
+// is rejected, and content made up mostly of links is flagged.
+type LinkDensityFilter struct {
+	maxLinks int
+	maxRatio float64
+}
+
+// NewLinkDensityFilter creates a LinkDensityFilter. maxLinks caps the links in
+// a piece of content and maxRatio is the largest share of its words that may
+// be links. A limit of zero or less is not enforced.
+func NewLinkDensityFilter(maxLinks int, maxRatio float64) *LinkDensityFilter {
+	return &LinkDensityFilter{
+		maxLinks: maxLinks,
+		maxRatio: maxRatio,
+	}
+}
+
+// Check counts the links in the content
+func (f *LinkDensityFilter) Check(ctx context.Context, content *Content) (Verdict, error) {
+	words := strings.Fields(content.Text)
+
+	links := len(content.Links)
+	for _, word := range words {
+		if isLink(word) {
+			links++
+		}
+	}
+
+	if f.maxLinks > 0 && links > f.maxLinks {
+		return Verdict{
+			Action:  Reject,
+			Reasons: []string{fmt.Sprintf("contains %d links; at most %d are allowed", links, f.maxLinks)},
+		}, nil
+	}
+
+	// A single link with a short comment is an ordinary share
+	if f.maxRatio > 0 && links > 1 {
+		ratio := float64(links) / float64(len(words)+len(content.Links))
+		if ratio > f.maxRatio {
+			return Verdict{
+				Action:  Flag,
+				Reasons: []string{"consists mostly of links"},
+			}, nil
+		}
+	}
+
+	return Verdict{Action: Allow}, nil
+}
+
+// isLink reports whether a word looks like a URL
+func isLink(word string) bool {
+	word = strings.ToLower(strings.Trim(word, "()[]<>\"'"))
+	return strings.HasPrefix(word, "http://") ||
+		strings.HasPrefix(word, "https://") ||
+		strings.HasPrefix(word, "www.")
+}
@@ -0,0 +1,98 @@
+package moderation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/platform/cache"
+)
+
+// RepeatedContentFilter catches users posting the same text over and over.
+// It counts identical posts from a user in the cache as they are recorded
+// after being stored. Once a user has posted the same text flagAfter times
+// further copies are flagged, and after rejectAfter times they are rejected.
+// The count expires after window passes without a new copy.
+//
+// Only posts are checked, since profile fields are resubmitted unchanged
+// whenever a profile is saved.
+type RepeatedContentFilter struct {
+	cache       cache.Cache
+	window      time.Duration
+	flagAfter   int
+	rejectAfter int
+}
+
+// NewRepeatedContentFilter creates a RepeatedContentFilter. A threshold of
+// zero or less is not enforced.
+func NewRepeatedContentFilter(c cache.Cache, window time.Duration, flagAfter, rejectAfter int) *RepeatedContentFilter {
+	if window <= 0 {
+		window = time.Hour
+	}
+
+	return &RepeatedContentFilter{
+		cache:       c,
+		window:      window,
+		flagAfter:   flagAfter,
+		rejectAfter: rejectAfter,
+	}
+}
+
+// Check compares the content with the earlier copies stored by the same user
+func (f *RepeatedContentFilter) Check(ctx context.Context, content *Content) (Verdict, error) {
+	key, ok := f.key(content)
+	if !ok {
+		return Verdict{Action: Allow}, nil
+	}
+
+	var count int
+	cache.GetInto(f.cache, key, &count)
+
+	if f.rejectAfter > 0 && count >= f.rejectAfter {
+		return Verdict{
+			Action:  Reject,
+			Reasons: []string{fmt.Sprintf("the same content was posted %d times recently", count)},
+		}, nil
+	}
+
+	if f.flagAfter > 0 && count >= f.flagAfter {
+		return Verdict{
+			Action:  Flag,
+			Reasons: []string{fmt.Sprintf("the same content was posted %d times recently", count)},
+		}, nil
+	}
+
+	return Verdict{Action: Allow}, nil
+}
+
+// Record counts a copy of the content once it is stored
+func (f *RepeatedContentFilter) Record(ctx context.Context, content *Content) error {
+	key, ok := f.key(content)
+	if !ok {
+		return nil
+	}
+
+	if _, err := f.cache.Incr(key, f.window); err != nil {
+		return fmt.Errorf("record repeated content: %w", err)
+	}
+
+	return nil
+}
+
+// key returns the cache key counting copies of the content, or false for
+// content that is not counted
+func (f *RepeatedContentFilter) key(content *Content) (string, bool) {
+	if content.Kind != KindPost {
+		return "", false
+	}
+
+	text := normalizeText(content.Text)
+	if text == "" {
+		return "", false
+	}
+
+	sum := sha256.Sum256([]byte(text))
+	return fmt.Sprintf("content_repeat:%s:%s", content.UserID, hex.EncodeToString(sum[:16])), true
+}
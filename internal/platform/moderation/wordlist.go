@@ -0,0 +1,62 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// WordListFilter rejects content containing blocked terms and flags content
+// containing flagged terms. Terms may be single words or phrases. Matching
+// ignores case and punctuation, and only matches whole words.
+type WordListFilter struct {
+	blocked []string
+	flagged []string
+}
+
+// NewWordListFilter creates a WordListFilter
+func NewWordListFilter(blocked, flagged []string) *WordListFilter {
+	return &WordListFilter{
+		blocked: normalizeTerms(blocked),
+		flagged: normalizeTerms(flagged),
+	}
+}
+
+// Check looks for listed terms in the content
+func (f *WordListFilter) Check(ctx context.Context, content *Content) (Verdict, error) {
+	// Pad with spaces so every term can be matched as " term "
+	text := " " + normalizeText(content.Text) + " "
+
+	if reasons := matchTerms(text, f.blocked, "contains blocked term %q"); len(reasons) > 0 {
+		return Verdict{Action: Reject, Reasons: reasons}, nil
+	}
+
+	if reasons := matchTerms(text, f.flagged, "contains flagged term %q"); len(reasons) > 0 {
+		return Verdict{Action: Flag, Reasons: reasons}, nil
+	}
+
+	return Verdict{Action: Allow}, nil
+}
+
+// matchTerms returns a reason for each term found in the padded text
+func matchTerms(text string, terms []string, format string) []string {
+	var reasons []string
+	for _, term := range terms {
+		if strings.Contains(text, " "+term+" ") {
+			reasons = append(reasons, fmt.Sprintf(format, term))
+		}
+	}
+	return reasons
+}
+
+// normalizeTerms normalizes terms the same way as the text they are matched
+// against, dropping terms left empty
+func normalizeTerms(terms []string) []string {
+	var normalized []string
+	for _, term := range terms {
+		if term = normalizeText(term); term != "" {
+			normalized = append(normalized, term)
+		}
+	}
+	return normalized
+}
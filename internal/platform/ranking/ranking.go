@@ -0,0 +1,134 @@
+package ranking
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Candidate is a post considered for a ranked feed, with the signals it is
+// scored on
+type Candidate struct {
+	PostID    string
+	AuthorID  string
+	CreatedAt time.Time
+	// Reactions counts the reactions on the post by type
+	Reactions map[string]int
+	// Comments counts the comments on the post, replies included
+	Comments int
+	// Affinity counts the viewer's recent reactions and comments on the
+	// author's posts
+	Affinity int
+}
+
+// Strategy scores candidates for a viewer. Higher scores rank first.
+type Strategy interface {
+	Score(c *Candidate, now time.Time) float64
+}
+
+// Scored is a candidate together with its score
+type Scored struct {
+	*Candidate
+	Score float64
+}
+
+// Rank scores the candidates with the strategy and orders them best first.
+// Ties go to the newer post and then to the higher ID, so the order is the
+// same on every run.
+func Rank(strategy Strategy, candidates []*Candidate, now time.Time) []Scored {
+	ranked := make([]Scored, 0, len(candidates))
+	for _, c := range candidates {
+		ranked = append(ranked, Scored{Candidate: c, Score: strategy.Score(c, now)})
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return Before(ranked[i].Score, ranked[i].CreatedAt, ranked[i].PostID, ranked[j].Score, ranked[j].CreatedAt, ranked[j].PostID)
+	})
+
+	return ranked
+}
+
+// Before reports whether a post with the first score, creation time and ID
+// ranks ahead of one with the second
+func Before(scoreA float64, createdA time.Time, idA string, scoreB float64, createdB time.Time, idB string) bool {
+	if scoreA != scoreB {
+		return scoreA > scoreB
+	}
+	if !createdA.Equal(createdB) {
+		return createdA.After(createdB)
+	}
+	return idA > idB
+}
+
+// Weights tune the weighted strategy
+type Weights struct {
+	// HalfLife is the age at which a post's score has halved
+	HalfLife time.Duration
+	// Reactions weighs each reaction by its type. Types without a weight
+	// count as DefaultReaction.
+	Reactions       map[string]float64
+	DefaultReaction float64
+	// Comment weighs each comment
+	Comment float64
+	// Affinity weighs how often the viewer interacts with the author
+	Affinity float64
+}
+
+// DefaultWeights returns weights that favour thoughtful reactions and
+// conversations, and halve a post's score every 12 hours
+func DefaultWeights() Weights {
+	return Weights{
+		HalfLife: 12 * time.Hour,
+		Reactions: map[string]float64{
+			"like":       1,
+			"celebrate":  1.5,
+			"support":    1.5,
+			"insightful": 2,
+			"curious":    1.5,
+		},
+		DefaultReaction: 1,
+		Comment:         3,
+		Affinity:        0.5,
+	}
+}
+
+// WeightedStrategy scores a post by its engagement, boosted by the viewer's
+// affinity for the author and decayed by the post's age:
+//
+//	(1 + reactions + comments) * (1 + affinity * ln(1 + interactions)) * 0.5^(age / half-life)
+type WeightedStrategy struct {
+	weights Weights
+}
+
+// NewWeightedStrategy creates a new WeightedStrategy. A missing half-life
+// falls back to the default.
+func NewWeightedStrategy(weights Weights) *WeightedStrategy {
+	if weights.HalfLife <= 0 {
+		weights.HalfLife = DefaultWeights().HalfLife
+	}
+
+	return &WeightedStrategy{weights: weights}
+}
+
+// Score implements Strategy
+func (s *WeightedStrategy) Score(c *Candidate, now time.Time) float64 {
+	engagement := 1.0
+	for reactionType, count := range c.Reactions {
+		weight, ok := s.weights.Reactions[reactionType]
+		if !ok {
+			weight = s.weights.DefaultReaction
+		}
+		engagement += weight * float64(count)
+	}
+	engagement += s.weights.Comment * float64(c.Comments)
+
+	affinity := 1 + s.weights.Affinity*math.Log1p(float64(c.Affinity))
+
+	age := now.Sub(c.CreatedAt)
+	if age < 0 {
+		age = 0
+	}
+	decay := math.Pow(0.5, age.Hours()/s.weights.HalfLife.Hours())
+
+	return engagement * affinity * decay
+}
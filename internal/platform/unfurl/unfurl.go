@@ -0,0 +1,310 @@
+package unfurl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+var (
+	// ErrBlockedAddress is returned when a URL resolves to an address the
+	// unfurler may not connect to, such as a private or loopback address
+	ErrBlockedAddress = errors.New("unfurl: address is not allowed")
+	// ErrUnsupportedURL is returned for URLs that are not absolute http(s) URLs
+	ErrUnsupportedURL = errors.New("unfurl: unsupported URL")
+	// ErrNotHTML is returned when the response is not an HTML document
+	ErrNotHTML = errors.New("unfurl: response is not HTML")
+)
+
+const (
+	maxRedirects      = 5
+	maxTitleLength    = 300
+	maxDescriptionLen = 1000
+)
+
+// Config holds unfurler configuration
+type Config struct {
+	// Timeout bounds the whole fetch, including redirects
+	Timeout time.Duration
+	// MaxBodyBytes caps how much of a response is read
+	MaxBodyBytes int64
+	UserAgent    string
+	// AllowPrivate disables the private address check. Only for tests.
+	AllowPrivate bool
+}
+
+// Metadata is the preview information found in a page
+type Metadata struct {
+	// URL is the final URL after redirects
+	URL         string
+	Title       string
+	Description string
+	ImageURL    string
+	SiteName    string
+}
+
+// Unfurler fetches pages and extracts their OpenGraph and Twitter Card metadata
+type Unfurler struct {
+	client       *http.Client
+	maxBodyBytes int64
+	userAgent    string
+}
+
+// New creates a new Unfurler
+func New(cfg Config) *Unfurler {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+	if cfg.MaxBodyBytes <= 0 {
+		cfg.MaxBodyBytes = 512 * 1024
+	}
+	if cfg.UserAgent == "" {
+		cfg.UserAgent = "PerfolioBot/1.0 (+https://perfolio.com)"
+	}
+
+	dialer := &net.Dialer{
+		Timeout: cfg.Timeout,
+	}
+	if !cfg.AllowPrivate {
+		// Checking the address at connect time rather than after resolving the
+		// host name also covers redirects and DNS rebinding
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+
+			ip := net.ParseIP(host)
+			if ip == nil || !isPublicIP(ip) {
+				return ErrBlockedAddress
+			}
+
+			return nil
+		}
+	}
+
+	transport := &http.Transport{
+		Proxy:                 nil, // Never route through an environment proxy
+		DialContext:           dialer.DialContext,
+		TLSHandshakeTimeout:   cfg.Timeout,
+		ResponseHeaderTimeout: cfg.Timeout,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       30 * time.Second,
+	}
+
+	client := &http.Client{
+		Transport: transport,
+		Timeout:   cfg.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxRedirects {
+				return fmt.Errorf("unfurl: stopped after %d redirects", maxRedirects)
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return ErrUnsupportedURL
+			}
+			return nil
+		},
+	}
+
+	return &Unfurler{
+		client:       client,
+		maxBodyBytes: cfg.MaxBodyBytes,
+		userAgent:    cfg.UserAgent,
+	}
+}
+
+// Fetch downloads a page and extracts its preview metadata
+func (u *Unfurler) Fetch(ctx context.Context, rawURL string) (*Metadata, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return nil, ErrUnsupportedURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("unfurl: create request: %w", err)
+	}
+	req.Header.Set("User-Agent", u.userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := u.client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrBlockedAddress) {
+			return nil, ErrBlockedAddress
+		}
+		return nil, fmt.Errorf("unfurl: fetch: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("unfurl: unexpected status %d", resp.StatusCode)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return nil, ErrNotHTML
+	}
+
+	meta := parse(io.LimitReader(resp.Body, u.maxBodyBytes), resp.Request.URL)
+	meta.URL = resp.Request.URL.String()
+
+	return meta, nil
+}
+
+// parse reads the document head, preferring OpenGraph over Twitter Card tags
+// and falling back to the <title> element and description meta tag
+func parse(r io.Reader, base *url.URL) *Metadata {
+	tags := make(map[string]string)
+	var title string
+
+	z := html.NewTokenizer(r)
+	inTitle := false
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			// End of input or of the size cap; use what was found so far
+			return build(tags, title, base)
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			switch atom.Lookup(name) {
+			case atom.Body:
+				return build(tags, title, base)
+			case atom.Title:
+				inTitle = tt == html.StartTagToken
+			case atom.Meta:
+				if !hasAttr {
+					continue
+				}
+
+				var key, content string
+				for {
+					attrName, value, more := z.TagAttr()
+					switch strings.ToLower(string(attrName)) {
+					case "property", "name":
+						key = strings.ToLower(strings.TrimSpace(string(value)))
+					case "content":
+						content = strings.TrimSpace(string(value))
+					}
+					if !more {
+						break
+					}
+				}
+
+				// The first occurrence of a tag wins
+				if key != "" && content != "" {
+					if _, ok := tags[key]; !ok {
+						tags[key] = content
+					}
+				}
+			}
+		case html.TextToken:
+			if inTitle && title == "" {
+				title = strings.TrimSpace(string(z.Text()))
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch atom.Lookup(name) {
+			case atom.Title:
+				inTitle = false
+			case atom.Head:
+				return build(tags, title, base)
+			}
+		}
+	}
+}
+
+// build picks the preview fields from the collected tags
+func build(tags map[string]string, title string, base *url.URL) *Metadata {
+	first := func(keys ...string) string {
+		for _, key := range keys {
+			if value := tags[key]; value != "" {
+				return value
+			}
+		}
+		return ""
+	}
+
+	meta := &Metadata{
+		Title:       truncate(first("og:title", "twitter:title"), maxTitleLength),
+		Description: truncate(first("og:description", "twitter:description", "description"), maxDescriptionLen),
+		SiteName:    truncate(first("og:site_name", "application-name"), maxTitleLength),
+	}
+
+	if meta.Title == "" {
+		meta.Title = truncate(title, maxTitleLength)
+	}
+
+	if image := first("og:image:secure_url", "og:image", "twitter:image", "twitter:image:src"); image != "" {
+		if ref, err := url.Parse(image); err == nil {
+			resolved := base.ResolveReference(ref)
+			if resolved.Scheme == "http" || resolved.Scheme == "https" {
+				meta.ImageURL = resolved.String()
+			}
+		}
+	}
+
+	return meta
+}
+
+// truncate shortens s to at most n runes
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
+
+// blockedNetworks lists ranges that are not reachable on the public internet
+// but are not covered by the net.IP classification methods
+var blockedNetworks = func() []*net.IPNet {
+	cidrs := []string{
+		"0.0.0.0/8",     // "This" network
+		"100.64.0.0/10", // Carrier-grade NAT
+		"192.0.0.0/24",  // IETF protocol assignments
+		"198.18.0.0/15", // Benchmarking
+		"240.0.0.0/4",   // Reserved
+		"64:ff9b::/96",  // NAT64, which can map to private IPv4 addresses
+		"2001:db8::/32", // Documentation
+	}
+
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}()
+
+// isPublicIP reports whether ip is a globally routable unicast address
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
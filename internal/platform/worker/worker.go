@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/pkg/logger"
+)
+
+// Task is a unit of background work run on every tick
+type Task func(ctx context.Context) error
+
+// Worker runs a task periodically in its own goroutine
+type Worker struct {
+	name     string
+	interval time.Duration
+	task     Task
+	logger   logger.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New creates a new Worker
+func New(name string, interval time.Duration, task Task, logger logger.Logger) *Worker {
+	return &Worker{
+		name:     name,
+		interval: interval,
+		task:     task,
+		logger:   logger,
+	}
+}
+
+// Start runs the task immediately and then on every interval until Stop is called.
+// Calling Start on a running worker has no effect.
+func (w *Worker) Start() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.cancel != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.done = make(chan struct{})
+
+	go w.run(ctx, w.done)
+
+	w.logger.Info().Str("worker", w.name).Dur("interval", w.interval).Msg("Worker started")
+}
+
+// Stop cancels the running task and waits for it to return or for ctx to expire
+func (w *Worker) Stop(ctx context.Context) error {
+	w.mu.Lock()
+	cancel, done := w.cancel, w.done
+	w.cancel, w.done = nil, nil
+	w.mu.Unlock()
+
+	if cancel == nil {
+		return nil
+	}
+
+	cancel()
+
+	select {
+	case <-done:
+		w.logger.Info().Str("worker", w.name).Msg("Worker stopped")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *Worker) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.task(ctx); err != nil && ctx.Err() == nil {
+			w.logger.Error().Err(err).Str("worker", w.name).Msg("Worker task failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -185,6 +185,12 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	// Suspended accounts cannot sign in
+	if !user.IsActive {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account suspended"})
+		return
+	}
+
 	// Generate token
 	accessToken, err := h.authMiddleware.GenerateToken(user.ID, []string{"user"}, h.tokenExpiry)
 	if err != nil {
@@ -259,6 +265,11 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
+	if !user.IsActive {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Account suspended"})
+		return
+	}
+
 	// Generate new access token
 	accessToken, err := h.authMiddleware.GenerateToken(userID, []string{"user"}, h.tokenExpiry)
 	if err != nil {
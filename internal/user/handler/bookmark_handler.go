@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/PeterM45/perfolio-api/internal/user/interfaces"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/PeterM45/perfolio-api/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// BookmarkHandler handles HTTP requests for bookmarks
+type BookmarkHandler struct {
+	service interfaces.BookmarkService
+	logger  logger.Logger
+}
+
+// NewBookmarkHandler creates a new BookmarkHandler
+func NewBookmarkHandler(service interfaces.BookmarkService, logger logger.Logger) *BookmarkHandler {
+	return &BookmarkHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterProtectedRoutes registers post routes that require authentication
+func (h *BookmarkHandler) RegisterProtectedRoutes(router *gin.RouterGroup) {
+	router.POST("/:id/bookmark", h.AddBookmark)
+	router.DELETE("/:id/bookmark", h.RemoveBookmark)
+}
+
+// RegisterProtectedUserRoutes registers user routes that require authentication
+func (h *BookmarkHandler) RegisterProtectedUserRoutes(router *gin.RouterGroup) {
+	router.GET("/me/bookmarks", h.GetBookmarks)
+}
+
+// AddBookmark handles POST /posts/:id/bookmark
+func (h *BookmarkHandler) AddBookmark(c *gin.Context) {
+	postID := c.Param("id")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	h.logger.Debug().
+		Str("post_id", postID).
+		Str("user_id", userID.(string)).
+		Msg("Adding bookmark")
+
+	if err := h.service.AddBookmark(c, postID, userID.(string)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true})
+}
+
+// RemoveBookmark handles DELETE /posts/:id/bookmark
+func (h *BookmarkHandler) RemoveBookmark(c *gin.Context) {
+	postID := c.Param("id")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	h.logger.Debug().
+		Str("post_id", postID).
+		Str("user_id", userID.(string)).
+		Msg("Removing bookmark")
+
+	if err := h.service.RemoveBookmark(c, postID, userID.(string)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// GetBookmarks handles GET /users/me/bookmarks
+func (h *BookmarkHandler) GetBookmarks(c *gin.Context) {
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	h.logger.Debug().
+		Str("user_id", userID.(string)).
+		Int("limit", limit).
+		Int("offset", offset).
+		Msg("Getting bookmarks")
+
+	posts, err := h.service.GetBookmarks(c, userID.(string), limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"posts": posts})
+}
+
+// handleError handles errors and returns appropriate HTTP responses
+func (h *BookmarkHandler) handleError(c *gin.Context, err error) {
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) {
+		switch appErr.Type() {
+		case apperrors.ErrTypeNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Error()})
+		case apperrors.ErrTypeBadRequest:
+			c.JSON(http.StatusBadRequest, badRequestBody(appErr))
+		case apperrors.ErrTypeUnauthorized:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": appErr.Error()})
+		case apperrors.ErrTypeForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": appErr.Error()})
+		case apperrors.ErrTypeConflict:
+			c.JSON(http.StatusConflict, gin.H{"error": appErr.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	// If not an AppError, treat as internal server error
+	h.logger.Error().Err(err).Msg("Internal server error")
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+}
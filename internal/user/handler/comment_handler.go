@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/user/interfaces"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/PeterM45/perfolio-api/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// CommentHandler handles HTTP requests for post comments
+type CommentHandler struct {
+	service interfaces.CommentService
+	logger  logger.Logger
+}
+
+// NewCommentHandler creates a new CommentHandler
+func NewCommentHandler(service interfaces.CommentService, logger logger.Logger) *CommentHandler {
+	return &CommentHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterProtectedRoutes registers routes that require authentication
+func (h *CommentHandler) RegisterProtectedRoutes(router *gin.RouterGroup) {
+	router.POST("/:id/comments", h.CreateComment)
+	router.PUT("/:id/comments/:commentId", h.UpdateComment)
+	router.DELETE("/:id/comments/:commentId", h.DeleteComment)
+}
+
+// RegisterPublicRoutes registers routes that don't require authentication
+func (h *CommentHandler) RegisterPublicRoutes(router *gin.RouterGroup) {
+	router.GET("/:id/comments", h.GetPostComments)
+	router.GET("/:id/comments/:commentId/replies", h.GetCommentReplies)
+}
+
+// CreateComment handles POST /posts/:id/comments
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	postID := c.Param("id")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req model.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Debug().
+		Str("post_id", postID).
+		Str("user_id", userID.(string)).
+		Msg("Creating comment")
+
+	comment, err := h.service.CreateComment(c, postID, userID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// UpdateComment handles PUT /posts/:id/comments/:commentId
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	postID := c.Param("id")
+	commentID := c.Param("commentId")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req model.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Debug().
+		Str("post_id", postID).
+		Str("comment_id", commentID).
+		Str("user_id", userID.(string)).
+		Msg("Updating comment")
+
+	comment, err := h.service.UpdateComment(c, postID, commentID, userID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, comment)
+}
+
+// DeleteComment handles DELETE /posts/:id/comments/:commentId
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	postID := c.Param("id")
+	commentID := c.Param("commentId")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	h.logger.Debug().
+		Str("post_id", postID).
+		Str("comment_id", commentID).
+		Str("user_id", userID.(string)).
+		Msg("Deleting comment")
+
+	err := h.service.DeleteComment(c, postID, commentID, userID.(string))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// GetPostComments handles GET /posts/:id/comments
+func (h *CommentHandler) GetPostComments(c *gin.Context) {
+	postID := c.Param("id")
+	viewerID := c.GetString("userID")
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	cursor := c.Query("cursor")
+
+	h.logger.Debug().
+		Str("post_id", postID).
+		Int("limit", limit).
+		Str("cursor", cursor).
+		Msg("Getting post comments")
+
+	comments, nextCursor, err := h.service.GetPostComments(c, postID, viewerID, cursor, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"comments": comments, "nextCursor": nextCursor})
+}
+
+// GetCommentReplies handles GET /posts/:id/comments/:commentId/replies
+func (h *CommentHandler) GetCommentReplies(c *gin.Context) {
+	postID := c.Param("id")
+	commentID := c.Param("commentId")
+	viewerID := c.GetString("userID")
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	cursor := c.Query("cursor")
+
+	h.logger.Debug().
+		Str("post_id", postID).
+		Str("comment_id", commentID).
+		Int("limit", limit).
+		Str("cursor", cursor).
+		Msg("Getting comment replies")
+
+	replies, nextCursor, err := h.service.GetCommentReplies(c, postID, commentID, viewerID, cursor, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"comments": replies, "nextCursor": nextCursor})
+}
+
+// handleError handles errors and returns appropriate HTTP responses
+func (h *CommentHandler) handleError(c *gin.Context, err error) {
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) {
+		switch appErr.Type() {
+		case apperrors.ErrTypeNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Error()})
+		case apperrors.ErrTypeBadRequest:
+			c.JSON(http.StatusBadRequest, badRequestBody(appErr))
+		case apperrors.ErrTypeUnauthorized:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": appErr.Error()})
+		case apperrors.ErrTypeForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": appErr.Error()})
+		case apperrors.ErrTypeConflict:
+			c.JSON(http.StatusConflict, gin.H{"error": appErr.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	// If not an AppError, treat as internal server error
+	h.logger.Error().Err(err).Msg("Internal server error")
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+}
@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/gin-gonic/gin"
+)
+
+// badRequestBody builds the response body for a bad request error, listing
+// its details when it has any
+func badRequestBody(appErr *apperrors.Error) gin.H {
+	body := gin.H{"error": appErr.Error()}
+	if details := appErr.Details(); len(details) > 0 {
+		body["details"] = details
+	}
+	return body
+}
@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/PeterM45/perfolio-api/internal/user/interfaces"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/PeterM45/perfolio-api/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// multipartOverhead is the room allowed for multipart headers and boundaries
+// on top of the file itself
+const multipartOverhead = 64 << 10
+
+// MediaHandler handles HTTP requests for media uploads
+type MediaHandler struct {
+	service interfaces.MediaService
+	logger  logger.Logger
+}
+
+// NewMediaHandler creates a new MediaHandler
+func NewMediaHandler(service interfaces.MediaService, logger logger.Logger) *MediaHandler {
+	return &MediaHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterProtectedRoutes registers routes that require authentication
+func (h *MediaHandler) RegisterProtectedRoutes(router *gin.RouterGroup) {
+	router.POST("", h.UploadMedia)
+	router.DELETE("/:id", h.DeleteMedia)
+}
+
+// UploadMedia handles POST /media with the image in the "file" form field
+func (h *MediaHandler) UploadMedia(c *gin.Context) {
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	maxBytes := h.service.MaxUploadBytes()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBytes+multipartOverhead)
+
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": "a file is required"})
+		return
+	}
+	defer file.Close()
+
+	// Read one byte past the limit so oversized files are detected
+	data, err := io.ReadAll(io.LimitReader(file, maxBytes+1))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "could not read file"})
+		return
+	}
+
+	if int64(len(data)) > maxBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": "file is too large"})
+		return
+	}
+
+	h.logger.Debug().
+		Str("user_id", userID.(string)).
+		Int("size", len(data)).
+		Msg("Uploading media")
+
+	media, err := h.service.UploadMedia(c, userID.(string), data)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, media)
+}
+
+// DeleteMedia handles DELETE /media/:id
+func (h *MediaHandler) DeleteMedia(c *gin.Context) {
+	id := c.Param("id")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	h.logger.Debug().
+		Str("media_id", id).
+		Str("user_id", userID.(string)).
+		Msg("Deleting media")
+
+	if err := h.service.DeleteMedia(c, id, userID.(string)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// handleError handles errors and returns appropriate HTTP responses
+func (h *MediaHandler) handleError(c *gin.Context, err error) {
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) {
+		switch appErr.Type() {
+		case apperrors.ErrTypeNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Error()})
+		case apperrors.ErrTypeBadRequest:
+			c.JSON(http.StatusBadRequest, badRequestBody(appErr))
+		case apperrors.ErrTypeUnauthorized:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": appErr.Error()})
+		case apperrors.ErrTypeForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": appErr.Error()})
+		case apperrors.ErrTypeConflict:
+			c.JSON(http.StatusConflict, gin.H{"error": appErr.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	// If not an AppError, treat as internal server error
+	h.logger.Error().Err(err).Msg("Internal server error")
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+}
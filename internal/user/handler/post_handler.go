@@ -2,8 +2,10 @@ package handler
 
 import (
 	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/PeterM45/perfolio-api/internal/common/model"
 	"github.com/PeterM45/perfolio-api/internal/user/interfaces"
@@ -48,21 +50,41 @@ func (h *PostHandler) RegisterProtectedRoutes(router *gin.RouterGroup) {
 	router.PUT("/:id", h.UpdatePost)
 	router.DELETE("/:id", h.DeletePost)
 	router.GET("/feed", h.GetFeed)
+	router.GET("/mentions", h.GetMentions)
+	router.GET("/scheduled", h.GetScheduledPosts)
+	router.GET("/trash", h.GetTrash)
+	router.POST("/:id/restore", h.RestorePost)
+	router.POST("/drafts", h.CreateDraft)
+	router.GET("/drafts", h.GetDrafts)
+	router.PATCH("/drafts/:id", h.UpdateDraft)
+	router.POST("/drafts/:id/publish", h.PublishDraft)
+	router.POST("/:id/revisions/:revision/restore", h.RestorePostRevision)
+	router.POST("/:id/pin", h.PinPost)
+	router.DELETE("/:id/pin", h.UnpinPost)
+	router.POST("/:id/repost", h.Repost)
+	router.DELETE("/:id/repost", h.Unrepost)
+	router.POST("/:id/poll/votes", h.VotePoll)
 }
 
 // RegisterPublicRoutes registers routes that don't require authentication
 func (h *PostHandler) RegisterPublicRoutes(router *gin.RouterGroup) {
 	router.GET("/:id", h.GetPost)
 	router.GET("/user/:userId", h.GetUserPosts)
+	router.GET("/hashtag/:tag", h.GetPostsByHashtag)
+	router.GET("/hashtags/trending", h.GetTrendingHashtags)
+	router.GET("/search", h.SearchPosts)
+	router.GET("/explore", h.GetExplore)
+	router.GET("/:id/revisions", h.GetPostRevisions)
 }
 
 // GetPost handles GET /posts/:id
 func (h *PostHandler) GetPost(c *gin.Context) {
 	id := c.Param("id")
+	viewerID := c.GetString("userID")
 
 	h.logger.Debug().Str("post_id", id).Msg("Getting post by ID")
 
-	post, err := h.service.GetPostByID(c, id)
+	post, err := h.service.GetPostByID(c, id, viewerID)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -132,6 +154,61 @@ func (h *PostHandler) UpdatePost(c *gin.Context) {
 	c.JSON(http.StatusOK, post)
 }
 
+// GetPostRevisions handles GET /posts/:id/revisions
+func (h *PostHandler) GetPostRevisions(c *gin.Context) {
+	id := c.Param("id")
+	viewerID := c.GetString("userID")
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	h.logger.Debug().
+		Str("post_id", id).
+		Int("limit", limit).
+		Int("offset", offset).
+		Msg("Getting post revisions")
+
+	revisions, err := h.service.GetPostRevisions(c, id, viewerID, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revisions": revisions})
+}
+
+// RestorePostRevision handles POST /posts/:id/revisions/:revision/restore
+func (h *PostHandler) RestorePostRevision(c *gin.Context) {
+	id := c.Param("id")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid revision"})
+		return
+	}
+
+	h.logger.Debug().
+		Str("post_id", id).
+		Str("user_id", userID.(string)).
+		Int("revision", revision).
+		Msg("Restoring post revision")
+
+	post, err := h.service.RestorePostRevision(c, id, revision, userID.(string))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, post)
+}
+
 // DeletePost handles DELETE /posts/:id
 func (h *PostHandler) DeletePost(c *gin.Context) {
 	id := c.Param("id")
@@ -157,26 +234,219 @@ func (h *PostHandler) DeletePost(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true})
 }
 
+// GetTrash handles GET /posts/trash
+func (h *PostHandler) GetTrash(c *gin.Context) {
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	h.logger.Debug().
+		Str("user_id", userID.(string)).
+		Int("limit", limit).
+		Int("offset", offset).
+		Msg("Getting deleted posts")
+
+	posts, err := h.service.GetTrash(c, userID.(string), limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"posts": posts})
+}
+
+// RestorePost handles POST /posts/:id/restore
+func (h *PostHandler) RestorePost(c *gin.Context) {
+	id := c.Param("id")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	h.logger.Debug().
+		Str("post_id", id).
+		Str("user_id", userID.(string)).
+		Msg("Restoring deleted post")
+
+	post, err := h.service.RestorePost(c, id, userID.(string))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, post)
+}
+
+// PinPost handles POST /posts/:id/pin
+func (h *PostHandler) PinPost(c *gin.Context) {
+	id := c.Param("id")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	h.logger.Debug().
+		Str("post_id", id).
+		Str("user_id", userID.(string)).
+		Msg("Pinning post")
+
+	post, err := h.service.PinPost(c, id, userID.(string))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, post)
+}
+
+// UnpinPost handles DELETE /posts/:id/pin
+func (h *PostHandler) UnpinPost(c *gin.Context) {
+	id := c.Param("id")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	h.logger.Debug().
+		Str("post_id", id).
+		Str("user_id", userID.(string)).
+		Msg("Unpinning post")
+
+	if err := h.service.UnpinPost(c, id, userID.(string)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// Repost handles POST /posts/:id/repost
+func (h *PostHandler) Repost(c *gin.Context) {
+	id := c.Param("id")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	// The body is optional; it is only needed to quote the post
+	var req model.RepostRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Debug().
+		Str("post_id", id).
+		Str("user_id", userID.(string)).
+		Bool("quote", req.Quote != "").
+		Msg("Reposting post")
+
+	post, err := h.service.Repost(c, id, userID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, post)
+}
+
+// Unrepost handles DELETE /posts/:id/repost
+func (h *PostHandler) Unrepost(c *gin.Context) {
+	id := c.Param("id")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	h.logger.Debug().
+		Str("post_id", id).
+		Str("user_id", userID.(string)).
+		Msg("Removing repost")
+
+	if err := h.service.Unrepost(c, id, userID.(string)); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// VotePoll handles POST /posts/:id/poll/votes
+func (h *PostHandler) VotePoll(c *gin.Context) {
+	id := c.Param("id")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req model.PollVoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Debug().
+		Str("post_id", id).
+		Str("user_id", userID.(string)).
+		Str("option_id", req.OptionID).
+		Msg("Voting in poll")
+
+	poll, err := h.service.VotePoll(c, id, userID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, poll)
+}
+
 // GetUserPosts handles GET /posts/user/:userId
 func (h *PostHandler) GetUserPosts(c *gin.Context) {
 	userID := c.Param("userId")
+	viewerID := c.GetString("userID")
 
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	cursor := c.Query("cursor")
 
 	h.logger.Debug().
 		Str("user_id", userID).
 		Int("limit", limit).
 		Int("offset", offset).
+		Str("cursor", cursor).
 		Msg("Getting user posts")
 
-	posts, err := h.service.GetUserPosts(c, userID, limit, offset)
+	posts, nextCursor, err := h.service.GetUserPosts(c, userID, viewerID, cursor, limit, offset)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"posts": posts})
+	c.JSON(http.StatusOK, gin.H{"posts": posts, "nextCursor": nextCursor})
 }
 
 // GetFeed handles GET /posts/feed
@@ -190,14 +460,94 @@ func (h *PostHandler) GetFeed(c *gin.Context) {
 
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	cursor := c.Query("cursor")
+	mode := model.FeedMode(c.Query("mode"))
 
 	h.logger.Debug().
 		Str("user_id", userID.(string)).
 		Int("limit", limit).
 		Int("offset", offset).
+		Str("cursor", cursor).
+		Str("mode", string(mode)).
 		Msg("Getting feed")
 
-	posts, err := h.service.GetFeed(c, userID.(string), limit, offset)
+	posts, nextCursor, err := h.service.GetFeed(c, userID.(string), mode, cursor, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"posts": posts, "nextCursor": nextCursor})
+}
+
+// GetExplore handles GET /posts/explore
+func (h *PostHandler) GetExplore(c *gin.Context) {
+	viewerID := c.GetString("userID")
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	cursor := c.Query("cursor")
+
+	h.logger.Debug().
+		Str("viewer_id", viewerID).
+		Int("limit", limit).
+		Str("cursor", cursor).
+		Msg("Getting explore feed")
+
+	posts, nextCursor, err := h.service.GetExplore(c, viewerID, cursor, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"posts": posts, "nextCursor": nextCursor})
+}
+
+// GetMentions handles GET /posts/mentions
+func (h *PostHandler) GetMentions(c *gin.Context) {
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	h.logger.Debug().
+		Str("user_id", userID.(string)).
+		Int("limit", limit).
+		Int("offset", offset).
+		Msg("Getting posts mentioning user")
+
+	posts, err := h.service.GetMentions(c, userID.(string), limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"posts": posts})
+}
+
+// GetScheduledPosts handles GET /posts/scheduled
+func (h *PostHandler) GetScheduledPosts(c *gin.Context) {
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	h.logger.Debug().
+		Str("user_id", userID.(string)).
+		Int("limit", limit).
+		Int("offset", offset).
+		Msg("Getting scheduled posts")
+
+	posts, err := h.service.GetScheduledPosts(c, userID.(string), limit, offset)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -206,6 +556,193 @@ func (h *PostHandler) GetFeed(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"posts": posts})
 }
 
+// CreateDraft handles POST /posts/drafts
+func (h *PostHandler) CreateDraft(c *gin.Context) {
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req model.CreateDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Debug().
+		Str("user_id", userID.(string)).
+		Msg("Creating draft")
+
+	post, err := h.service.CreateDraft(c, userID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, post)
+}
+
+// UpdateDraft handles PATCH /posts/drafts/:id
+func (h *PostHandler) UpdateDraft(c *gin.Context) {
+	id := c.Param("id")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req model.UpdateDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Debug().
+		Str("post_id", id).
+		Str("user_id", userID.(string)).
+		Msg("Saving draft")
+
+	post, err := h.service.UpdateDraft(c, id, userID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, post)
+}
+
+// GetDrafts handles GET /posts/drafts
+func (h *PostHandler) GetDrafts(c *gin.Context) {
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	h.logger.Debug().
+		Str("user_id", userID.(string)).
+		Int("limit", limit).
+		Int("offset", offset).
+		Msg("Getting drafts")
+
+	posts, err := h.service.GetDrafts(c, userID.(string), limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"posts": posts})
+}
+
+// PublishDraft handles POST /posts/drafts/:id/publish
+func (h *PostHandler) PublishDraft(c *gin.Context) {
+	id := c.Param("id")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	// The body is optional; it is only needed to schedule the post
+	var req model.PublishDraftRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Debug().
+		Str("post_id", id).
+		Str("user_id", userID.(string)).
+		Msg("Publishing draft")
+
+	post, err := h.service.PublishDraft(c, id, userID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, post)
+}
+
+// GetPostsByHashtag handles GET /posts/hashtag/:tag
+func (h *PostHandler) GetPostsByHashtag(c *gin.Context) {
+	tag := c.Param("tag")
+	viewerID := c.GetString("userID")
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	cursor := c.Query("cursor")
+
+	h.logger.Debug().
+		Str("tag", tag).
+		Int("limit", limit).
+		Str("cursor", cursor).
+		Msg("Getting posts by hashtag")
+
+	posts, nextCursor, err := h.service.GetPostsByHashtag(c, tag, viewerID, cursor, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"posts": posts, "nextCursor": nextCursor})
+}
+
+// SearchPosts handles GET /posts/search
+func (h *PostHandler) SearchPosts(c *gin.Context) {
+	query := c.Query("q")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "search query is required"})
+		return
+	}
+
+	viewerID := c.GetString("userID")
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	cursor := c.Query("cursor")
+
+	h.logger.Debug().
+		Str("query", query).
+		Int("limit", limit).
+		Str("cursor", cursor).
+		Msg("Searching posts")
+
+	posts, nextCursor, err := h.service.SearchPosts(c, query, viewerID, cursor, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"posts": posts, "nextCursor": nextCursor})
+}
+
+// GetTrendingHashtags handles GET /posts/hashtags/trending
+func (h *PostHandler) GetTrendingHashtags(c *gin.Context) {
+	hours, _ := strconv.Atoi(c.DefaultQuery("hours", "24"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	h.logger.Debug().
+		Int("hours", hours).
+		Int("limit", limit).
+		Msg("Getting trending hashtags")
+
+	trends, err := h.service.GetTrendingHashtags(c, time.Duration(hours)*time.Hour, limit)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"hashtags": trends})
+}
+
 // handleError handles errors and returns appropriate HTTP responses
 func (h *PostHandler) handleError(c *gin.Context, err error) {
 	var appErr *apperrors.Error
@@ -214,7 +751,7 @@ func (h *PostHandler) handleError(c *gin.Context, err error) {
 		case apperrors.ErrTypeNotFound:
 			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Error()})
 		case apperrors.ErrTypeBadRequest:
-			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Error()})
+			c.JSON(http.StatusBadRequest, badRequestBody(appErr))
 		case apperrors.ErrTypeUnauthorized:
 			c.JSON(http.StatusUnauthorized, gin.H{"error": appErr.Error()})
 		case apperrors.ErrTypeForbidden:
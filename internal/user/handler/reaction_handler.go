@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/user/interfaces"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/PeterM45/perfolio-api/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// ReactionHandler handles HTTP requests for post reactions
+type ReactionHandler struct {
+	service interfaces.ReactionService
+	logger  logger.Logger
+}
+
+// NewReactionHandler creates a new ReactionHandler
+func NewReactionHandler(service interfaces.ReactionService, logger logger.Logger) *ReactionHandler {
+	return &ReactionHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterProtectedRoutes registers routes that require authentication
+func (h *ReactionHandler) RegisterProtectedRoutes(router *gin.RouterGroup) {
+	router.POST("/:id/reactions", h.AddReaction)
+	router.PUT("/:id/reactions", h.UpdateReaction)
+	router.DELETE("/:id/reactions", h.RemoveReaction)
+}
+
+// RegisterPublicRoutes registers routes that don't require authentication
+func (h *ReactionHandler) RegisterPublicRoutes(router *gin.RouterGroup) {
+	router.GET("/:id/reactions", h.GetPostReactions)
+}
+
+// AddReaction handles POST /posts/:id/reactions
+func (h *ReactionHandler) AddReaction(c *gin.Context) {
+	postID := c.Param("id")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req model.ReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.PostID = postID
+
+	h.logger.Debug().
+		Str("post_id", postID).
+		Str("user_id", userID.(string)).
+		Str("type", string(req.Type)).
+		Msg("Adding reaction")
+
+	reaction, err := h.service.AddReaction(c, userID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, reaction)
+}
+
+// UpdateReaction handles PUT /posts/:id/reactions
+func (h *ReactionHandler) UpdateReaction(c *gin.Context) {
+	postID := c.Param("id")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req model.ReactionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.PostID = postID
+
+	h.logger.Debug().
+		Str("post_id", postID).
+		Str("user_id", userID.(string)).
+		Str("type", string(req.Type)).
+		Msg("Updating reaction")
+
+	reaction, err := h.service.UpdateReaction(c, userID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, reaction)
+}
+
+// RemoveReaction handles DELETE /posts/:id/reactions
+func (h *ReactionHandler) RemoveReaction(c *gin.Context) {
+	postID := c.Param("id")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	h.logger.Debug().
+		Str("post_id", postID).
+		Str("user_id", userID.(string)).
+		Msg("Removing reaction")
+
+	err := h.service.RemoveReaction(c, postID, userID.(string))
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// GetPostReactions handles GET /posts/:id/reactions
+func (h *ReactionHandler) GetPostReactions(c *gin.Context) {
+	postID := c.Param("id")
+	viewerID := c.GetString("userID")
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	h.logger.Debug().
+		Str("post_id", postID).
+		Int("limit", limit).
+		Int("offset", offset).
+		Msg("Getting post reactions")
+
+	reactions, err := h.service.GetPostReactions(c, postID, viewerID, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reactions": reactions})
+}
+
+// handleError handles errors and returns appropriate HTTP responses
+func (h *ReactionHandler) handleError(c *gin.Context, err error) {
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) {
+		switch appErr.Type() {
+		case apperrors.ErrTypeNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Error()})
+		case apperrors.ErrTypeBadRequest:
+			c.JSON(http.StatusBadRequest, badRequestBody(appErr))
+		case apperrors.ErrTypeUnauthorized:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": appErr.Error()})
+		case apperrors.ErrTypeForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": appErr.Error()})
+		case apperrors.ErrTypeConflict:
+			c.JSON(http.StatusConflict, gin.H{"error": appErr.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	// If not an AppError, treat as internal server error
+	h.logger.Error().Err(err).Msg("Internal server error")
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+}
@@ -0,0 +1,184 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/user/interfaces"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/PeterM45/perfolio-api/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// ReportHandler handles HTTP requests for reports and moderation
+type ReportHandler struct {
+	service interfaces.ReportService
+	logger  logger.Logger
+}
+
+// NewReportHandler creates a new ReportHandler
+func NewReportHandler(service interfaces.ReportService, logger logger.Logger) *ReportHandler {
+	return &ReportHandler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// RegisterProtectedRoutes registers routes that require authentication
+func (h *ReportHandler) RegisterProtectedRoutes(router *gin.RouterGroup) {
+	router.POST("", h.CreateReport)
+}
+
+// RegisterAdminRoutes registers moderation routes. The router group must
+// only admit admins.
+func (h *ReportHandler) RegisterAdminRoutes(router *gin.RouterGroup) {
+	router.GET("/reports", h.GetReportQueue)
+	router.GET("/reports/:id", h.GetReport)
+	router.POST("/reports/:id/actions", h.ResolveReport)
+	router.GET("/audit", h.GetModerationActions)
+}
+
+// CreateReport handles POST /reports
+func (h *ReportHandler) CreateReport(c *gin.Context) {
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req model.CreateReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Debug().
+		Str("user_id", userID.(string)).
+		Str("target_type", string(req.TargetType)).
+		Str("target_id", req.TargetID).
+		Msg("Creating report")
+
+	report, err := h.service.CreateReport(c, userID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// GetReportQueue handles GET /admin/reports
+func (h *ReportHandler) GetReportQueue(c *gin.Context) {
+	status := model.ReportStatus(c.Query("status"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	h.logger.Debug().
+		Str("status", string(status)).
+		Int("limit", limit).
+		Int("offset", offset).
+		Msg("Getting report queue")
+
+	reports, err := h.service.GetReportQueue(c, status, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reports": reports})
+}
+
+// GetReport handles GET /admin/reports/:id
+func (h *ReportHandler) GetReport(c *gin.Context) {
+	id := c.Param("id")
+
+	h.logger.Debug().Str("report_id", id).Msg("Getting report")
+
+	report, err := h.service.GetReport(c, id)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// ResolveReport handles POST /admin/reports/:id/actions
+func (h *ReportHandler) ResolveReport(c *gin.Context) {
+	id := c.Param("id")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	var req model.ModerationActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.logger.Debug().
+		Str("report_id", id).
+		Str("moderator_id", userID.(string)).
+		Str("action", string(req.Action)).
+		Msg("Resolving report")
+
+	action, err := h.service.ResolveReport(c, id, userID.(string), &req)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, action)
+}
+
+// GetModerationActions handles GET /admin/audit
+func (h *ReportHandler) GetModerationActions(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	h.logger.Debug().
+		Int("limit", limit).
+		Int("offset", offset).
+		Msg("Getting moderation audit trail")
+
+	actions, err := h.service.GetModerationActions(c, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"actions": actions})
+}
+
+// handleError handles errors and returns appropriate HTTP responses
+func (h *ReportHandler) handleError(c *gin.Context, err error) {
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) {
+		switch appErr.Type() {
+		case apperrors.ErrTypeNotFound:
+			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Error()})
+		case apperrors.ErrTypeBadRequest:
+			c.JSON(http.StatusBadRequest, badRequestBody(appErr))
+		case apperrors.ErrTypeUnauthorized:
+			c.JSON(http.StatusUnauthorized, gin.H{"error": appErr.Error()})
+		case apperrors.ErrTypeForbidden:
+			c.JSON(http.StatusForbidden, gin.H{"error": appErr.Error()})
+		case apperrors.ErrTypeConflict:
+			c.JSON(http.StatusConflict, gin.H{"error": appErr.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		}
+		return
+	}
+
+	// If not an AppError, treat as internal server error
+	h.logger.Error().Err(err).Msg("Internal server error")
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+}
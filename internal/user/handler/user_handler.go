@@ -55,6 +55,12 @@ func (h *UserHandler) RegisterProtectedRoutes(router *gin.RouterGroup) {
 	router.POST("/", h.CreateUser)
 	router.PUT("/:id", h.UpdateUser)
 	router.POST("/:id/follow", h.ToggleFollow)
+	router.POST("/:id/block", h.BlockUser)
+	router.DELETE("/:id/block", h.UnblockUser)
+	router.POST("/:id/mute", h.MuteUser)
+	router.DELETE("/:id/mute", h.UnmuteUser)
+	router.GET("/me/blocks", h.GetBlockedUsers)
+	router.GET("/me/mutes", h.GetMutedUsers)
 
 	// Admin-only routes could be added here
 }
@@ -75,9 +81,11 @@ func (h *UserHandler) RegisterPublicRoutes(router *gin.RouterGroup) {
 func (h *UserHandler) GetUserByID(c *gin.Context) {
 	id := c.Param("id")
 
+	viewerID := c.GetString("userID")
+
 	h.logger.Debug().Str("user_id", id).Msg("Getting user by ID")
 
-	user, err := h.service.GetUserByID(c, id)
+	user, err := h.service.GetProfile(c, id, viewerID)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -90,9 +98,11 @@ func (h *UserHandler) GetUserByID(c *gin.Context) {
 func (h *UserHandler) GetUserByUsername(c *gin.Context) {
 	username := c.Param("username")
 
+	viewerID := c.GetString("userID")
+
 	h.logger.Debug().Str("username", username).Msg("Getting user by username")
 
-	user, err := h.service.GetUserByUsername(c, username)
+	user, err := h.service.GetProfileByUsername(c, username, viewerID)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -161,10 +171,11 @@ func (h *UserHandler) SearchUsers(c *gin.Context) {
 	}
 
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	viewerID := c.GetString("userID")
 
 	h.logger.Debug().Str("query", query).Int("limit", limit).Msg("Searching users")
 
-	users, err := h.service.SearchUsers(c, query, limit)
+	users, err := h.service.SearchUsers(c, query, viewerID, limit)
 	if err != nil {
 		h.handleError(c, err)
 		return
@@ -243,20 +254,23 @@ func (h *UserHandler) GetFollowers(c *gin.Context) {
 
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	cursor := c.Query("cursor")
+	viewerID := c.GetString("userID")
 
 	h.logger.Debug().
 		Str("user_id", userID).
 		Int("limit", limit).
 		Int("offset", offset).
+		Str("cursor", cursor).
 		Msg("Getting followers")
 
-	followers, err := h.service.GetFollowers(c, userID, limit, offset)
+	followers, nextCursor, err := h.service.GetFollowers(c, userID, viewerID, cursor, limit, offset)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"users": followers})
+	c.JSON(http.StatusOK, gin.H{"users": followers, "nextCursor": nextCursor})
 }
 
 // GetFollowing handles GET /users/:id/following
@@ -265,20 +279,173 @@ func (h *UserHandler) GetFollowing(c *gin.Context) {
 
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	cursor := c.Query("cursor")
+	viewerID := c.GetString("userID")
 
 	h.logger.Debug().
 		Str("user_id", userID).
 		Int("limit", limit).
 		Int("offset", offset).
+		Str("cursor", cursor).
 		Msg("Getting following")
 
-	following, err := h.service.GetFollowing(c, userID, limit, offset)
+	following, nextCursor, err := h.service.GetFollowing(c, userID, viewerID, cursor, limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"users": following, "nextCursor": nextCursor})
+}
+
+// BlockUser handles POST /users/:id/block
+func (h *UserHandler) BlockUser(c *gin.Context) {
+	targetID := c.Param("id")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	h.logger.Debug().
+		Str("user_id", userID.(string)).
+		Str("target_id", targetID).
+		Msg("Blocking user")
+
+	if err := h.service.BlockUser(c, userID.(string), targetID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true})
+}
+
+// UnblockUser handles DELETE /users/:id/block
+func (h *UserHandler) UnblockUser(c *gin.Context) {
+	targetID := c.Param("id")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	h.logger.Debug().
+		Str("user_id", userID.(string)).
+		Str("target_id", targetID).
+		Msg("Unblocking user")
+
+	if err := h.service.UnblockUser(c, userID.(string), targetID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// GetBlockedUsers handles GET /users/me/blocks
+func (h *UserHandler) GetBlockedUsers(c *gin.Context) {
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	h.logger.Debug().
+		Str("user_id", userID.(string)).
+		Int("limit", limit).
+		Int("offset", offset).
+		Msg("Getting blocked users")
+
+	users, err := h.service.GetBlockedUsers(c, userID.(string), limit, offset)
 	if err != nil {
 		h.handleError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"users": following})
+	c.JSON(http.StatusOK, gin.H{"users": users})
+}
+
+// MuteUser handles POST /users/:id/mute
+func (h *UserHandler) MuteUser(c *gin.Context) {
+	targetID := c.Param("id")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	h.logger.Debug().
+		Str("user_id", userID.(string)).
+		Str("target_id", targetID).
+		Msg("Muting user")
+
+	if err := h.service.MuteUser(c, userID.(string), targetID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"success": true})
+}
+
+// UnmuteUser handles DELETE /users/:id/mute
+func (h *UserHandler) UnmuteUser(c *gin.Context) {
+	targetID := c.Param("id")
+
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	h.logger.Debug().
+		Str("user_id", userID.(string)).
+		Str("target_id", targetID).
+		Msg("Unmuting user")
+
+	if err := h.service.UnmuteUser(c, userID.(string), targetID); err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// GetMutedUsers handles GET /users/me/mutes
+func (h *UserHandler) GetMutedUsers(c *gin.Context) {
+	// Get authenticated user
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	h.logger.Debug().
+		Str("user_id", userID.(string)).
+		Int("limit", limit).
+		Int("offset", offset).
+		Msg("Getting muted users")
+
+	users, err := h.service.GetMutedUsers(c, userID.(string), limit, offset)
+	if err != nil {
+		h.handleError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"users": users})
 }
 
 // handleError handles errors and returns appropriate HTTP responses
@@ -289,7 +456,7 @@ func (h *UserHandler) handleError(c *gin.Context, err error) {
 		case apperrors.ErrTypeNotFound:
 			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Error()})
 		case apperrors.ErrTypeBadRequest:
-			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Error()})
+			c.JSON(http.StatusBadRequest, badRequestBody(appErr))
 		case apperrors.ErrTypeUnauthorized:
 			c.JSON(http.StatusUnauthorized, gin.H{"error": appErr.Error()})
 		case apperrors.ErrTypeForbidden:
@@ -220,7 +220,7 @@ func (h *WidgetHandler) handleError(c *gin.Context, err error) {
 		case apperrors.ErrTypeNotFound:
 			c.JSON(http.StatusNotFound, gin.H{"error": appErr.Error()})
 		case apperrors.ErrTypeBadRequest:
-			c.JSON(http.StatusBadRequest, gin.H{"error": appErr.Error()})
+			c.JSON(http.StatusBadRequest, badRequestBody(appErr))
 		case apperrors.ErrTypeUnauthorized:
 			c.JSON(http.StatusUnauthorized, gin.H{"error": appErr.Error()})
 		case apperrors.ErrTypeForbidden:
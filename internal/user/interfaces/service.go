@@ -2,6 +2,7 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"github.com/PeterM45/perfolio-api/internal/common/model"
 )
@@ -10,28 +11,63 @@ import (
 type UserService interface {
 	GetUserByID(ctx context.Context, id string) (*model.User, error)
 	GetUserByUsername(ctx context.Context, username string) (*model.User, error)
+	GetProfile(ctx context.Context, id string, viewerID string) (*model.User, error)
+	GetProfileByUsername(ctx context.Context, username string, viewerID string) (*model.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*model.User, error)
 	CreateUser(ctx context.Context, req *model.CreateUserRequest) (*model.User, error)
 	UpdateUser(ctx context.Context, id string, req *model.UpdateUserRequest) (*model.User, error)
 	ChangePassword(ctx context.Context, id string, req *model.ChangePasswordRequest) error
 	VerifyPassword(ctx context.Context, id string, password string) (bool, error)
-	SearchUsers(ctx context.Context, query string, limit int) ([]*model.User, error)
+	SearchUsers(ctx context.Context, query string, viewerID string, limit int) ([]*model.User, error)
 
 	ToggleFollow(ctx context.Context, req *model.FollowRequest, followerID string) error
 	IsFollowing(ctx context.Context, followerID, followingID string) (bool, error)
 	GetProfileStats(ctx context.Context, userID string) (*model.ProfileStatsResponse, error)
-	GetFollowers(ctx context.Context, userID string, limit, offset int) ([]*model.User, error)
-	GetFollowing(ctx context.Context, userID string, limit, offset int) ([]*model.User, error)
+	GetFollowers(ctx context.Context, userID string, viewerID string, cursor string, limit, offset int) ([]*model.User, string, error)
+	GetFollowing(ctx context.Context, userID string, viewerID string, cursor string, limit, offset int) ([]*model.User, string, error)
+
+	BlockUser(ctx context.Context, blockerID, blockedID string) error
+	UnblockUser(ctx context.Context, blockerID, blockedID string) error
+	IsBlocked(ctx context.Context, userID, otherID string) (bool, error)
+	GetBlockedUsers(ctx context.Context, userID string, limit, offset int) ([]*model.User, error)
+	MuteUser(ctx context.Context, muterID, mutedID string) error
+	UnmuteUser(ctx context.Context, muterID, mutedID string) error
+	GetMutedUsers(ctx context.Context, userID string, limit, offset int) ([]*model.User, error)
 }
 
 // PostService defines methods for post business logic
 type PostService interface {
 	CreatePost(ctx context.Context, userID string, req *model.CreatePostRequest) (*model.Post, error)
-	GetPostByID(ctx context.Context, id string) (*model.Post, error)
+	GetPostByID(ctx context.Context, id string, viewerID string) (*model.Post, error)
 	UpdatePost(ctx context.Context, id string, userID string, req *model.UpdatePostRequest) (*model.Post, error)
 	DeletePost(ctx context.Context, id string, userID string) error
-	GetUserPosts(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error)
-	GetFeed(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error)
+	GetUserPosts(ctx context.Context, userID string, viewerID string, cursor string, limit, offset int) ([]*model.Post, string, error)
+	GetFeed(ctx context.Context, userID string, mode model.FeedMode, cursor string, limit, offset int) ([]*model.Post, string, error)
+	GetExplore(ctx context.Context, viewerID string, cursor string, limit int) ([]*model.Post, string, error)
+	BuildExplore(ctx context.Context) (int, error)
+	GetPostRevisions(ctx context.Context, id string, viewerID string, limit, offset int) ([]*model.PostRevision, error)
+	RestorePostRevision(ctx context.Context, id string, revision int, userID string) (*model.Post, error)
+	Repost(ctx context.Context, originalID string, userID string, req *model.RepostRequest) (*model.Post, error)
+	Unrepost(ctx context.Context, originalID string, userID string) error
+	VotePoll(ctx context.Context, postID string, userID string, req *model.PollVoteRequest) (*model.Poll, error)
+	CreateDraft(ctx context.Context, userID string, req *model.CreateDraftRequest) (*model.Post, error)
+	UpdateDraft(ctx context.Context, id string, userID string, req *model.UpdateDraftRequest) (*model.Post, error)
+	GetDrafts(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error)
+	PublishDraft(ctx context.Context, id string, userID string, req *model.PublishDraftRequest) (*model.Post, error)
+	PinPost(ctx context.Context, id string, userID string) (*model.Post, error)
+	UnpinPost(ctx context.Context, id string, userID string) error
+	GetPostsByIDs(ctx context.Context, ids []string, viewerID string) ([]*model.Post, error)
+	InvalidatePost(ctx context.Context, id string, authorID string)
+	InvalidateFollowerTimelines(ctx context.Context) (int, error)
+	GetScheduledPosts(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error)
+	PublishScheduledPosts(ctx context.Context, limit int) (int, error)
+	GetTrash(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error)
+	RestorePost(ctx context.Context, id string, userID string) (*model.Post, error)
+	PurgeDeletedPosts(ctx context.Context, limit int) (int, error)
+	GetMentions(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error)
+	GetPostsByHashtag(ctx context.Context, tag string, viewerID string, cursor string, limit int) ([]*model.Post, string, error)
+	SearchPosts(ctx context.Context, query string, viewerID string, cursor string, limit int) ([]*model.Post, string, error)
+	GetTrendingHashtags(ctx context.Context, window time.Duration, limit int) ([]*model.HashtagTrend, error)
 }
 
 // WidgetService defines methods for widget business logic
@@ -44,3 +80,49 @@ type WidgetService interface {
 	BatchUpdateWidgets(ctx context.Context, userID string, req *model.BatchUpdateWidgetsRequest) error
 	GetWidgetTypes(ctx context.Context) (map[string]model.WidgetType, error) // Add this line
 }
+
+// ReactionService defines methods for reaction business logic
+type ReactionService interface {
+	AddReaction(ctx context.Context, userID string, req *model.ReactionRequest) (*model.Reaction, error)
+	UpdateReaction(ctx context.Context, userID string, req *model.ReactionRequest) (*model.Reaction, error)
+	RemoveReaction(ctx context.Context, postID string, userID string) error
+	GetPostReactions(ctx context.Context, postID string, viewerID string, limit, offset int) ([]*model.Reaction, error)
+}
+
+// CommentService defines methods for comment business logic
+type CommentService interface {
+	CreateComment(ctx context.Context, postID string, userID string, req *model.CreateCommentRequest) (*model.Comment, error)
+	UpdateComment(ctx context.Context, postID string, commentID string, userID string, req *model.UpdateCommentRequest) (*model.Comment, error)
+	DeleteComment(ctx context.Context, postID string, commentID string, userID string) error
+	GetPostComments(ctx context.Context, postID string, viewerID string, cursor string, limit int) ([]*model.Comment, string, error)
+	GetCommentReplies(ctx context.Context, postID string, commentID string, viewerID string, cursor string, limit int) ([]*model.Comment, string, error)
+}
+
+// BookmarkService defines methods for bookmark business logic
+type BookmarkService interface {
+	AddBookmark(ctx context.Context, postID string, userID string) error
+	RemoveBookmark(ctx context.Context, postID string, userID string) error
+	GetBookmarks(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error)
+}
+
+// LinkPreviewService defines methods for unfurling embedded URLs
+type LinkPreviewService interface {
+	ProcessPending(ctx context.Context, limit int) (int, error)
+}
+
+// MediaService defines methods for media upload business logic
+type MediaService interface {
+	MaxUploadBytes() int64
+	UploadMedia(ctx context.Context, userID string, data []byte) (*model.Media, error)
+	DeleteMedia(ctx context.Context, id string, userID string) error
+	CollectOrphans(ctx context.Context, limit int) (int, error)
+}
+
+// ReportService defines methods for reporting content and moderating reports
+type ReportService interface {
+	CreateReport(ctx context.Context, reporterID string, req *model.CreateReportRequest) (*model.Report, error)
+	GetReport(ctx context.Context, id string) (*model.Report, error)
+	GetReportQueue(ctx context.Context, status model.ReportStatus, limit, offset int) ([]*model.Report, error)
+	ResolveReport(ctx context.Context, id string, moderatorID string, req *model.ModerationActionRequest) (*model.ModerationAction, error)
+	GetModerationActions(ctx context.Context, limit, offset int) ([]*model.ModerationAction, error)
+}
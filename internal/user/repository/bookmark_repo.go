@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/platform/database"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+)
+
+// BookmarkRepository defines methods to interact with bookmark data
+type BookmarkRepository interface {
+	Create(ctx context.Context, userID, postID string) error
+	Delete(ctx context.Context, userID, postID string) error
+	GetPostIDs(ctx context.Context, userID string, limit, offset int) ([]string, error)
+}
+
+type bookmarkRepository struct {
+	db *database.DB
+}
+
+// NewBookmarkRepository creates a new BookmarkRepository
+func NewBookmarkRepository(db *database.DB) BookmarkRepository {
+	return &bookmarkRepository{
+		db: db,
+	}
+}
+
+// Create bookmarks a post, failing if the user already bookmarked it
+func (r *bookmarkRepository) Create(ctx context.Context, userID, postID string) error {
+	query := `
+		INSERT INTO bookmark (user_id, post_id, created_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, post_id) DO NOTHING
+		RETURNING post_id
+	`
+
+	var id string
+	err := r.db.QueryRowContext(ctx, query, userID, postID, time.Now().UTC()).Scan(&id)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.Conflict("you have already bookmarked this post")
+		}
+		return fmt.Errorf("create bookmark: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes a bookmark
+func (r *bookmarkRepository) Delete(ctx context.Context, userID, postID string) error {
+	query := `DELETE FROM bookmark WHERE user_id = $1 AND post_id = $2 RETURNING post_id`
+
+	var id string
+	err := r.db.QueryRowContext(ctx, query, userID, postID).Scan(&id)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.NotFound(fmt.Sprintf("bookmark: %s", postID))
+		}
+		return fmt.Errorf("delete bookmark: %w", err)
+	}
+
+	return nil
+}
+
+// GetPostIDs fetches the IDs of a user's bookmarked posts, most recently saved
+// first. Posts the user can no longer see or that are in the trash are
+// skipped; purged posts take their bookmarks with them.
+func (r *bookmarkRepository) GetPostIDs(ctx context.Context, userID string, limit, offset int) ([]string, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	query := `
+		SELECT
+			b.post_id
+		FROM
+			bookmark b
+		JOIN
+			post p ON b.post_id = p.id
+		WHERE
+			b.user_id = $1 AND
+			p.deleted_at IS NULL AND
+			p.status = 'published' AND
+			` + visibleToViewer("$1") + `
+		ORDER BY
+			b.created_at DESC, b.post_id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("query bookmarks: %w", err)
+	}
+	defer rows.Close()
+
+	var postIDs []string
+
+	for rows.Next() {
+		var postID string
+		if err := rows.Scan(&postID); err != nil {
+			return nil, fmt.Errorf("scan bookmark row: %w", err)
+		}
+
+		postIDs = append(postIDs, postID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return postIDs, nil
+}
@@ -0,0 +1,267 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/platform/database"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/PeterM45/perfolio-api/pkg/pagination"
+	"github.com/google/uuid"
+)
+
+// CommentRepository defines methods to interact with comment data
+type CommentRepository interface {
+	Create(ctx context.Context, comment *model.Comment) error
+	GetByID(ctx context.Context, id string) (*model.Comment, error)
+	Update(ctx context.Context, comment *model.Comment) error
+	Delete(ctx context.Context, id string) error
+	GetThread(ctx context.Context, postID string, parentID *string, viewerID string, cursor *pagination.Cursor, limit int) ([]*model.Comment, error)
+}
+
+type commentRepository struct {
+	db *database.DB
+}
+
+// NewCommentRepository creates a new CommentRepository
+func NewCommentRepository(db *database.DB) CommentRepository {
+	return &commentRepository{
+		db: db,
+	}
+}
+
+// Create adds a new comment
+func (r *commentRepository) Create(ctx context.Context, comment *model.Comment) error {
+	// Generate ID if not provided
+	if comment.ID == "" {
+		comment.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO comment (
+			id, post_id, user_id, parent_id, depth, content, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7
+		)
+	`
+
+	now := time.Now().UTC()
+
+	var parentID sql.NullString
+	if comment.ParentID != nil {
+		parentID = sql.NullString{String: *comment.ParentID, Valid: true}
+	}
+
+	_, err := r.db.ExecContext(ctx, query,
+		comment.ID,
+		comment.PostID,
+		comment.UserID,
+		parentID,
+		comment.Depth,
+		comment.Content,
+		now,
+	)
+
+	if err != nil {
+		return fmt.Errorf("create comment: %w", err)
+	}
+
+	comment.CreatedAt = now
+
+	return nil
+}
+
+// GetByID fetches a comment by ID. Comments hidden by a moderator are reported missing.
+func (r *commentRepository) GetByID(ctx context.Context, id string) (*model.Comment, error) {
+	query := `
+		SELECT
+			c.id, c.post_id, c.user_id, c.parent_id, c.depth, c.content,
+			c.created_at, c.updated_at,
+			(SELECT COUNT(*) FROM comment rc WHERE rc.parent_id = c.id AND rc.hidden_at IS NULL),
+			u.username, u.image_url
+		FROM
+			comment c
+		JOIN
+			users u ON c.user_id = u.id
+		WHERE
+			c.id = $1 AND
+			c.hidden_at IS NULL
+	`
+
+	comment, err := scanComment(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NotFound(fmt.Sprintf("comment: %s", id))
+		}
+		return nil, fmt.Errorf("query comment: %w", err)
+	}
+
+	return comment, nil
+}
+
+// Update updates the content of a comment
+func (r *commentRepository) Update(ctx context.Context, comment *model.Comment) error {
+	query := `
+		UPDATE comment
+		SET
+			content = $1,
+			updated_at = $2
+		WHERE
+			id = $3
+		RETURNING id
+	`
+
+	now := time.Now().UTC()
+
+	var id string
+	err := r.db.QueryRowContext(ctx, query, comment.Content, now, comment.ID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.NotFound(fmt.Sprintf("comment: %s", comment.ID))
+		}
+		return fmt.Errorf("update comment: %w", err)
+	}
+
+	comment.UpdatedAt = &now
+
+	return nil
+}
+
+// Delete removes a comment along with its replies
+func (r *commentRepository) Delete(ctx context.Context, id string) error {
+	query := `DELETE FROM comment WHERE id = $1 RETURNING id`
+
+	var deletedID string
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&deletedID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.NotFound(fmt.Sprintf("comment: %s", id))
+		}
+		return fmt.Errorf("delete comment: %w", err)
+	}
+
+	return nil
+}
+
+// GetThread fetches one level of a comment thread in chronological order.
+// A nil parentID returns the top-level comments of the post. Comments hidden
+// by a moderator and comments by users in a block with the viewer are left
+// out.
+func (r *commentRepository) GetThread(ctx context.Context, postID string, parentID *string, viewerID string, cursor *pagination.Cursor, limit int) ([]*model.Comment, error) {
+	if limit <= 0 {
+		limit = 20 // Default limit
+	}
+
+	query := `
+		SELECT
+			c.id, c.post_id, c.user_id, c.parent_id, c.depth, c.content,
+			c.created_at, c.updated_at,
+			(SELECT COUNT(*) FROM comment rc WHERE rc.parent_id = c.id AND rc.hidden_at IS NULL),
+			u.username, u.image_url
+		FROM
+			comment c
+		JOIN
+			users u ON c.user_id = u.id
+		WHERE
+			c.post_id = $1 AND
+			c.hidden_at IS NULL AND
+			` + notBlocked("$2", "c.user_id")
+	params := []interface{}{postID, viewerID}
+
+	if parentID != nil {
+		params = append(params, *parentID)
+		query += fmt.Sprintf(" AND c.parent_id = $%d", len(params))
+	} else {
+		query += " AND c.parent_id IS NULL"
+	}
+
+	if cursor != nil {
+		params = append(params, cursor.CreatedAt, cursor.ID)
+		query += fmt.Sprintf(" AND (c.created_at, c.id) > ($%d, $%d)", len(params)-1, len(params))
+	}
+
+	params = append(params, limit)
+	query += fmt.Sprintf(" ORDER BY c.created_at ASC, c.id ASC LIMIT $%d", len(params))
+
+	rows, err := r.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("query comments: %w", err)
+	}
+	defer rows.Close()
+
+	var comments []*model.Comment
+
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan comment row: %w", err)
+		}
+
+		comments = append(comments, comment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return comments, nil
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanComment scans a comment row joined with its author
+func scanComment(row rowScanner) (*model.Comment, error) {
+	var comment model.Comment
+	var parentID sql.NullString
+	var updatedAt sql.NullTime
+	var username string
+	var imageURL sql.NullString
+
+	err := row.Scan(
+		&comment.ID,
+		&comment.PostID,
+		&comment.UserID,
+		&parentID,
+		&comment.Depth,
+		&comment.Content,
+		&comment.CreatedAt,
+		&updatedAt,
+		&comment.ReplyCount,
+		&username,
+		&imageURL,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if parentID.Valid {
+		comment.ParentID = &parentID.String
+	}
+
+	if updatedAt.Valid {
+		comment.UpdatedAt = &updatedAt.Time
+	}
+
+	// Set up basic author information
+	author := &model.User{
+		ID:       comment.UserID,
+		Username: username,
+	}
+
+	if imageURL.Valid {
+		author.ImageURL = &imageURL.String
+	}
+
+	comment.Author = author
+
+	return &comment, nil
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/platform/database"
+)
+
+const (
+	// linkPreviewRefreshAfter is how long a fetched preview is reused before
+	// a new post embedding the URL queues it again
+	linkPreviewRefreshAfter = 7 * 24 * time.Hour
+	// linkPreviewMaxAttempts is how many times a URL is claimed before it is
+	// given up on, so a URL that crashes or hangs the fetcher is not retried forever
+	linkPreviewMaxAttempts = 3
+)
+
+// LinkPreviewRepository defines methods to work through the link preview queue
+type LinkPreviewRepository interface {
+	ClaimPending(ctx context.Context, staleBefore time.Time, limit int) ([]string, error)
+	SaveResult(ctx context.Context, preview *model.LinkPreview) error
+	MarkFailed(ctx context.Context, url string, reason string) error
+}
+
+type linkPreviewRepository struct {
+	db *database.DB
+}
+
+// NewLinkPreviewRepository creates a new LinkPreviewRepository
+func NewLinkPreviewRepository(db *database.DB) LinkPreviewRepository {
+	return &linkPreviewRepository{
+		db: db,
+	}
+}
+
+// ClaimPending marks up to limit queued URLs as being fetched and returns them.
+// Claims older than staleBefore are taken over, since the instance holding them
+// most likely stopped. Rows locked by another instance are skipped, so several
+// instances can work through the queue at once.
+func (r *linkPreviewRepository) ClaimPending(ctx context.Context, staleBefore time.Time, limit int) ([]string, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	query := `
+		UPDATE link_preview
+		SET 
+			status = CASE WHEN attempts >= $4 THEN 'failed' ELSE 'fetching' END,
+			error = CASE WHEN attempts >= $4 THEN 'too many attempts' ELSE error END,
+			fetched_at = CASE WHEN attempts >= $4 THEN $1 ELSE fetched_at END,
+			attempts = attempts + 1,
+			claimed_at = $1
+		WHERE url IN (
+			SELECT url
+			FROM link_preview
+			WHERE 
+				status = 'pending' OR
+				(status = 'fetching' AND claimed_at < $2)
+			ORDER BY created_at
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING url, status
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, time.Now().UTC(), staleBefore, limit, linkPreviewMaxAttempts)
+	if err != nil {
+		return nil, fmt.Errorf("claim link previews: %w", err)
+	}
+	defer rows.Close()
+
+	var urls []string
+
+	for rows.Next() {
+		var url, status string
+		if err := rows.Scan(&url, &status); err != nil {
+			return nil, fmt.Errorf("scan link preview row: %w", err)
+		}
+
+		if model.LinkPreviewStatus(status) == model.LinkPreviewStatusFetching {
+			urls = append(urls, url)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return urls, nil
+}
+
+// SaveResult stores a fetched preview
+func (r *linkPreviewRepository) SaveResult(ctx context.Context, preview *model.LinkPreview) error {
+	query := `
+		UPDATE link_preview
+		SET 
+			status = 'ok',
+			title = $1,
+			description = $2,
+			image_url = $3,
+			site_name = $4,
+			final_url = $5,
+			error = NULL,
+			fetched_at = $6
+		WHERE 
+			url = $7
+	`
+
+	if preview.FetchedAt.IsZero() {
+		preview.FetchedAt = time.Now().UTC()
+	}
+
+	_, err := r.db.ExecContext(ctx, query,
+		preview.Title,
+		preview.Description,
+		preview.ImageURL,
+		preview.SiteName,
+		preview.FinalURL,
+		preview.FetchedAt,
+		preview.URL,
+	)
+
+	if err != nil {
+		return fmt.Errorf("save link preview: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailed records that a URL could not be unfurled. It is not retried until
+// the preview is due for a refresh.
+func (r *linkPreviewRepository) MarkFailed(ctx context.Context, url string, reason string) error {
+	query := `
+		UPDATE link_preview
+		SET 
+			status = 'failed',
+			error = $1,
+			fetched_at = $2
+		WHERE 
+			url = $3
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, reason, time.Now().UTC(), url); err != nil {
+		return fmt.Errorf("mark link preview failed: %w", err)
+	}
+
+	return nil
+}
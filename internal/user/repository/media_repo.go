@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/platform/database"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/google/uuid"
+)
+
+// MediaRepository defines methods to interact with uploaded media
+type MediaRepository interface {
+	Create(ctx context.Context, media *model.Media) error
+	GetByID(ctx context.Context, id string) (*model.Media, error)
+	DeleteUnattached(ctx context.Context, id string) error
+	DeleteOrphaned(ctx context.Context, before time.Time, limit int) ([]*model.Media, error)
+}
+
+// mediaColumns is the select list read by scanMedia
+const mediaColumns = `
+			m.id, m.user_id, m.post_id, m.content_type, m.size_bytes, m.width, m.height,
+			m.storage_key, m.thumbnail_key, m.url, m.thumbnail_url, m.created_at`
+
+type mediaRepository struct {
+	db *database.DB
+}
+
+// NewMediaRepository creates a new MediaRepository
+func NewMediaRepository(db *database.DB) MediaRepository {
+	return &mediaRepository{
+		db: db,
+	}
+}
+
+// Create records an uploaded file
+func (r *mediaRepository) Create(ctx context.Context, media *model.Media) error {
+	// Generate ID if not provided
+	if media.ID == "" {
+		media.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO media (
+			id, user_id, content_type, size_bytes, width, height,
+			storage_key, thumbnail_key, url, thumbnail_url, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+		)
+	`
+
+	media.CreatedAt = time.Now().UTC()
+
+	_, err := r.db.ExecContext(ctx, query,
+		media.ID,
+		media.UserID,
+		media.ContentType,
+		media.Size,
+		media.Width,
+		media.Height,
+		media.StorageKey,
+		media.ThumbnailKey,
+		media.URL,
+		media.ThumbnailURL,
+		media.CreatedAt,
+	)
+
+	if err != nil {
+		return fmt.Errorf("create media: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID fetches an upload by ID
+func (r *mediaRepository) GetByID(ctx context.Context, id string) (*model.Media, error) {
+	query := `SELECT ` + mediaColumns + ` FROM media m WHERE m.id = $1`
+
+	media, err := scanMedia(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NotFound(fmt.Sprintf("media: %s", id))
+		}
+		return nil, fmt.Errorf("query media: %w", err)
+	}
+
+	return media, nil
+}
+
+// DeleteUnattached removes an upload that no post references yet
+func (r *mediaRepository) DeleteUnattached(ctx context.Context, id string) error {
+	query := `DELETE FROM media WHERE id = $1 AND post_id IS NULL RETURNING id`
+
+	var deletedID string
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&deletedID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.NotFound(fmt.Sprintf("media: %s", id))
+		}
+		return fmt.Errorf("delete media: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteOrphaned removes up to limit uploads created before the given time
+// that no post references, and returns them so their files can be removed.
+// Attaching media to a post only claims rows that are still unattached, so
+// an upload is either attached or collected, never both.
+func (r *mediaRepository) DeleteOrphaned(ctx context.Context, before time.Time, limit int) ([]*model.Media, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	query := `
+		DELETE FROM media m
+		WHERE m.id IN (
+			SELECT id
+			FROM media
+			WHERE 
+				post_id IS NULL AND
+				created_at < $1
+			ORDER BY created_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + mediaColumns
+
+	rows, err := r.db.QueryContext(ctx, query, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("delete orphaned media: %w", err)
+	}
+	defer rows.Close()
+
+	return scanMediaRows(rows)
+}
+
+// scanMediaRows scans every row of a media query
+func scanMediaRows(rows *sql.Rows) ([]*model.Media, error) {
+	var media []*model.Media
+
+	for rows.Next() {
+		item, err := scanMedia(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan media row: %w", err)
+		}
+
+		media = append(media, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return media, nil
+}
+
+// scanMedia scans a media row
+func scanMedia(row rowScanner) (*model.Media, error) {
+	var media model.Media
+	var postID sql.NullString
+
+	err := row.Scan(
+		&media.ID,
+		&media.UserID,
+		&postID,
+		&media.ContentType,
+		&media.Size,
+		&media.Width,
+		&media.Height,
+		&media.StorageKey,
+		&media.ThumbnailKey,
+		&media.URL,
+		&media.ThumbnailURL,
+		&media.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if postID.Valid {
+		media.PostID = &postID.String
+	}
+
+	return &media, nil
+}
@@ -6,11 +6,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"html"
+	"strings"
 	"time"
 
 	"github.com/PeterM45/perfolio-api/internal/common/model"
 	"github.com/PeterM45/perfolio-api/internal/platform/database"
 	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/PeterM45/perfolio-api/pkg/pagination"
 	"github.com/google/uuid"
 )
 
@@ -19,9 +22,66 @@ type PostRepository interface {
 	Create(ctx context.Context, post *model.Post) error
 	GetByID(ctx context.Context, id string) (*model.Post, error)
 	Update(ctx context.Context, post *model.Post) error
-	Delete(ctx context.Context, id string) error
-	GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error)
-	GetFeed(ctx context.Context, userIDs []string, limit, offset int) ([]*model.Post, error)
+	Delete(ctx context.Context, id string) ([]*model.Post, error)
+	Restore(ctx context.Context, id string, userID string, deletedAfter time.Time) error
+	GetDeletedByUserID(ctx context.Context, userID string, deletedAfter time.Time, limit, offset int) ([]*model.Post, error)
+	PurgeDeleted(ctx context.Context, deletedBefore time.Time, limit int) ([]*model.Post, error)
+	Pin(ctx context.Context, id string, userID string, maxPinned int) error
+	Unpin(ctx context.Context, id string) error
+	GetRepost(ctx context.Context, originalID string, userID string) (*model.Post, error)
+	GetVisibleByIDs(ctx context.Context, ids []string, viewerID string) ([]*model.Post, error)
+	GetRevisions(ctx context.Context, postID string, limit, offset int) ([]*model.PostRevision, error)
+	GetRevision(ctx context.Context, postID string, revision int) (*model.PostRevision, error)
+	GetByUserID(ctx context.Context, userID string, viewerID string, cursor *pagination.Cursor, limit, offset int) ([]*model.Post, error)
+	GetFeed(ctx context.Context, userID string, cursor *pagination.Cursor, limit, offset int) ([]*model.Post, error)
+	FanOut(ctx context.Context, postID string, maxFollowers int) (bool, error)
+	GetFanoutOnReadAuthors(ctx context.Context, userID string) ([]string, error)
+	HasFanoutOnReadPosts(ctx context.Context, userID string) (bool, error)
+	GetMentioning(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error)
+	GetByHashtag(ctx context.Context, tag string, viewerID string, cursor *pagination.Cursor, limit int) ([]*model.Post, error)
+	GetTrendingHashtags(ctx context.Context, since time.Time, limit int) ([]*model.HashtagTrend, error)
+	GetExploreCandidates(ctx context.Context, since time.Time, limit int) ([]*model.Post, error)
+	GetExcludedAuthors(ctx context.Context, viewerID string, authorIDs []string) (map[string]bool, error)
+	Search(ctx context.Context, query string, viewerID string, cursor *pagination.Cursor, limit int) ([]*model.Post, error)
+	GetDraftsByUserID(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error)
+	UpdateDraft(ctx context.Context, post *model.Post) error
+	PublishDraft(ctx context.Context, post *model.Post) error
+	GetScheduledByUserID(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error)
+	PublishDue(ctx context.Context, now time.Time, limit int) ([]*model.Post, error)
+	GetReactionSummaries(ctx context.Context, postIDs []string, viewerID string) (map[string]*model.ReactionSummary, error)
+	GetCommentCounts(ctx context.Context, postIDs []string) (map[string]int, error)
+	GetAffinities(ctx context.Context, viewerID string, authorIDs []string, since time.Time) (map[string]int, error)
+	GetLinkPreviews(ctx context.Context, urls []string) (map[string]*model.LinkPreview, error)
+	GetMedia(ctx context.Context, postIDs []string) (map[string][]*model.Media, error)
+	GetPolls(ctx context.Context, postIDs []string, viewerID string) (map[string]*model.Poll, error)
+	VotePoll(ctx context.Context, postID string, optionID string, userID string) error
+}
+
+// postColumns is the select list read by scanPost. Array columns are
+// converted to JSON so they scan into plain byte slices.
+const postColumns = `
+			p.id, p.user_id, p.content, array_to_json(p.embed_urls), array_to_json(p.hashtags),
+			p.visibility, p.status, p.publish_at, p.created_at, p.updated_at, p.revision_count,
+			p.kind, p.original_post_id,
+			(SELECT COUNT(*) FROM post rp WHERE rp.original_post_id = p.id AND rp.status = 'published' AND rp.deleted_at IS NULL),
+			p.pinned_at, p.deleted_at, p.hidden_at,
+			u.username, u.image_url`
+
+// visibleToViewer returns a condition on post p that holds when the viewer bound
+// to param may see it based on its visibility: public posts, the viewer's own
+// posts and followers-only posts of users the viewer follows. Posts hidden by a
+// moderator are only visible to their author, and posts by users in a block
+// with the viewer are not visible at all. It mirrors postService.canView, which
+// callers must keep in sync.
+func visibleToViewer(param string) string {
+	return `(p.user_id = ` + param + ` OR (p.hidden_at IS NULL AND ` + notBlocked(param, "p.user_id") + ` AND (
+				p.visibility = 'public' OR (
+					p.visibility = 'followers' AND EXISTS (
+						SELECT 1 FROM follows f
+						WHERE f.follower_id = ` + param + ` AND f.following_id = p.user_id
+					)
+				)
+			)))`
 }
 
 type postRepository struct {
@@ -35,43 +95,48 @@ func NewPostRepository(db *database.DB) PostRepository {
 	}
 }
 
-// Create adds a new post
+// Create adds a new post along with its mentions. Reposting the same post
+// twice is reported as a conflict.
 func (r *postRepository) Create(ctx context.Context, post *model.Post) error {
 	// Generate ID if not provided
 	if post.ID == "" {
 		post.ID = uuid.New().String()
 	}
 
+	if post.Kind == "" {
+		post.Kind = model.PostKindPost
+	}
+
 	query := `
 		INSERT INTO post (
 			id, user_id, content, embed_urls, hashtags, 
-			visibility, created_at, updated_at
+			visibility, status, publish_at, kind, original_post_id, created_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6, $7, $7
+			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 		)
+		ON CONFLICT (user_id, original_post_id) WHERE kind = 'repost' DO NOTHING
 	`
 
 	now := time.Now().UTC()
 	post.CreatedAt = now
 
-	// Convert string arrays to PostgreSQL arrays
-	embedURLsJSON, err := json.Marshal(post.EmbedURLs)
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("marshal embed URLs: %w", err)
+		return fmt.Errorf("begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	hashtagsJSON, err := json.Marshal(post.Hashtags)
-	if err != nil {
-		return fmt.Errorf("marshal hashtags: %w", err)
-	}
-
-	_, err = r.db.ExecContext(ctx, query,
+	result, err := tx.ExecContext(ctx, query,
 		post.ID,
 		post.UserID,
 		post.Content,
-		embedURLsJSON,
-		hashtagsJSON,
+		post.EmbedURLs,
+		post.Hashtags,
 		post.Visibility,
+		post.Status,
+		post.PublishAt,
+		post.Kind,
+		post.OriginalPostID,
 		now,
 	)
 
@@ -79,44 +144,52 @@ func (r *postRepository) Create(ctx context.Context, post *model.Post) error {
 		return fmt.Errorf("create post: %w", err)
 	}
 
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("create post: %w", err)
+	}
+
+	if inserted == 0 {
+		return apperrors.Conflict("post already reposted")
+	}
+
+	if err := attachMedia(ctx, tx, post); err != nil {
+		return err
+	}
+
+	if err := insertPoll(ctx, tx, post.ID, post.Poll); err != nil {
+		return err
+	}
+
+	if err := insertMentions(ctx, tx, post.ID, post.Mentions); err != nil {
+		return err
+	}
+
+	if err := enqueueLinkPreviews(ctx, tx, post.EmbedURLs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
 	return nil
 }
 
 // GetByID fetches a post by ID
 func (r *postRepository) GetByID(ctx context.Context, id string) (*model.Post, error) {
 	query := `
-		SELECT 
-			p.id, p.user_id, p.content, p.embed_urls, p.hashtags, 
-			p.visibility, p.created_at, p.updated_at,
-			u.username, u.image_url
+		SELECT ` + postColumns + `
 		FROM 
 			post p
 		JOIN 
-			"user" u ON p.user_id = u.id
+			users u ON p.user_id = u.id
 		WHERE 
-			p.id = $1
+			p.id = $1 AND
+			p.deleted_at IS NULL
 	`
 
-	var post model.Post
-	var embedURLsJSON, hashtagsJSON []byte
-	var updatedAt sql.NullTime
-	var visibilityStr string
-	var username string
-	var imageURL sql.NullString
-
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
-		&post.ID,
-		&post.UserID,
-		&post.Content,
-		&embedURLsJSON,
-		&hashtagsJSON,
-		&visibilityStr,
-		&post.CreatedAt,
-		&updatedAt,
-		&username,
-		&imageURL,
-	)
-
+	post, err := scanPost(r.db.QueryRowContext(ctx, query, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, apperrors.NotFound(fmt.Sprintf("post: %s", id))
@@ -124,299 +197,1829 @@ func (r *postRepository) GetByID(ctx context.Context, id string) (*model.Post, e
 		return nil, fmt.Errorf("query post: %w", err)
 	}
 
-	// Parse JSON arrays
-	if len(embedURLsJSON) > 0 {
-		if err := json.Unmarshal(embedURLsJSON, &post.EmbedURLs); err != nil {
-			return nil, fmt.Errorf("unmarshal embed URLs: %w", err)
-		}
-	}
-
-	if len(hashtagsJSON) > 0 {
-		if err := json.Unmarshal(hashtagsJSON, &post.Hashtags); err != nil {
-			return nil, fmt.Errorf("unmarshal hashtags: %w", err)
-		}
-	}
-
-	if updatedAt.Valid {
-		post.UpdatedAt = &updatedAt.Time
+	if err := r.attachMentions(ctx, []*model.Post{post}); err != nil {
+		return nil, err
 	}
 
-	post.Visibility = model.Visibility(visibilityStr)
+	return post, nil
+}
 
-	// Set up basic author information
-	author := &model.User{
-		ID:       post.UserID,
-		Username: username,
+// Update updates a post and replaces its mentions. The previous content,
+// embeds and hashtags are kept as a new revision.
+func (r *postRepository) Update(ctx context.Context, post *model.Post) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	if imageURL.Valid {
-		author.ImageURL = &imageURL.String
+	// Lock the row so concurrent edits get consecutive revision numbers
+	var revisionCount int
+	err = tx.QueryRowContext(ctx, `SELECT revision_count FROM post WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, post.ID).Scan(&revisionCount)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.NotFound(fmt.Sprintf("post: %s", post.ID))
+		}
+		return fmt.Errorf("lock post: %w", err)
 	}
 
-	post.Author = author
+	revisionQuery := `
+		INSERT INTO post_revision (
+			post_id, revision, content, embed_urls, hashtags, created_at
+		)
+		SELECT 
+			id, $2, content, embed_urls, hashtags, COALESCE(updated_at, created_at)
+		FROM 
+			post
+		WHERE 
+			id = $1
+	`
 
-	return &post, nil
-}
+	if _, err := tx.ExecContext(ctx, revisionQuery, post.ID, revisionCount+1); err != nil {
+		return fmt.Errorf("create post revision: %w", err)
+	}
 
-// Update updates a post
-func (r *postRepository) Update(ctx context.Context, post *model.Post) error {
 	query := `
 		UPDATE post
 		SET 
 			content = $1, 
 			embed_urls = $2, 
 			hashtags = $3, 
-			updated_at = $4
+			visibility = $4,
+			updated_at = $5,
+			revision_count = $6
 		WHERE 
-			id = $5
-		RETURNING id
+			id = $7
 	`
 
 	now := time.Now().UTC()
-	postUpdate := now
-	post.UpdatedAt = &postUpdate
 
-	// Convert arrays to JSON
-	embedURLsJSON, err := json.Marshal(post.EmbedURLs)
+	_, err = tx.ExecContext(ctx, query,
+		post.Content,
+		post.EmbedURLs,
+		post.Hashtags,
+		post.Visibility,
+		now,
+		revisionCount+1,
+		post.ID,
+	)
+
+	if err != nil {
+		return fmt.Errorf("update post: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM post_mention WHERE post_id = $1`, post.ID); err != nil {
+		return fmt.Errorf("clear post mentions: %w", err)
+	}
+
+	if err := insertMentions(ctx, tx, post.ID, post.Mentions); err != nil {
+		return err
+	}
+
+	if err := enqueueLinkPreviews(ctx, tx, post.EmbedURLs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	post.UpdatedAt = &now
+	post.RevisionCount = revisionCount + 1
+
+	return nil
+}
+
+// Delete moves a post to the trash and removes its plain reposts, which are
+// returned. A plain repost itself is removed outright. Quote posts are kept
+// and stop showing the original while it is in the trash.
+func (r *postRepository) Delete(ctx context.Context, id string) ([]*model.Post, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("marshal embed URLs: %w", err)
+		return nil, fmt.Errorf("begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 
-	hashtagsJSON, err := json.Marshal(post.Hashtags)
+	rows, err := tx.QueryContext(ctx, `DELETE FROM post WHERE original_post_id = $1 AND kind = 'repost' RETURNING id, user_id`, id)
 	if err != nil {
-		return fmt.Errorf("marshal hashtags: %w", err)
+		return nil, fmt.Errorf("delete reposts: %w", err)
 	}
 
-	var id string
-	err = r.db.QueryRowContext(ctx, query,
-		post.Content,
-		embedURLsJSON,
-		hashtagsJSON,
-		now,
-		post.ID,
-	).Scan(&id)
+	var reposts []*model.Post
+	for rows.Next() {
+		repost := &model.Post{Kind: model.PostKindRepost, OriginalPostID: &id}
+		if err := rows.Scan(&repost.ID, &repost.UserID); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan repost row: %w", err)
+		}
+		reposts = append(reposts, repost)
+	}
+	rows.Close()
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	// Trashed posts lose their pin, so they don't count against the limit
+	query := `
+		UPDATE post
+		SET 
+			deleted_at = $2,
+			pinned_at = NULL
+		WHERE 
+			id = $1 AND
+			kind <> 'repost' AND
+			deleted_at IS NULL
+		RETURNING id
+	`
+
+	var deletedID string
+	err = tx.QueryRowContext(ctx, query, id, time.Now().UTC()).Scan(&deletedID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		err = tx.QueryRowContext(ctx, `DELETE FROM post WHERE id = $1 AND kind = 'repost' RETURNING id`, id).Scan(&deletedID)
+	}
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return apperrors.NotFound(fmt.Sprintf("post: %s", post.ID))
+			return nil, apperrors.NotFound(fmt.Sprintf("post: %s", id))
 		}
-		return fmt.Errorf("update post: %w", err)
+		return nil, fmt.Errorf("delete post: %w", err)
 	}
 
-	return nil
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return reposts, nil
 }
 
-// Delete removes a post
-func (r *postRepository) Delete(ctx context.Context, id string) error {
-	query := `DELETE FROM post WHERE id = $1 RETURNING id`
+// Restore takes one of a user's posts out of the trash, provided it was
+// deleted after the given time
+func (r *postRepository) Restore(ctx context.Context, id string, userID string, deletedAfter time.Time) error {
+	query := `
+		UPDATE post
+		SET 
+			deleted_at = NULL
+		WHERE 
+			id = $1 AND
+			user_id = $2 AND
+			deleted_at >= $3
+		RETURNING id
+	`
 
-	var deletedID string
-	err := r.db.QueryRowContext(ctx, query, id).Scan(&deletedID)
+	var restoredID string
+	err := r.db.QueryRowContext(ctx, query, id, userID, deletedAfter).Scan(&restoredID)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return apperrors.NotFound(fmt.Sprintf("post: %s", id))
+			return apperrors.NotFound(fmt.Sprintf("deleted post: %s", id))
 		}
-		return fmt.Errorf("delete post: %w", err)
+		return fmt.Errorf("restore post: %w", err)
 	}
 
 	return nil
 }
 
-// GetByUserID fetches posts by user ID
-func (r *postRepository) GetByUserID(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error) {
+// GetDeletedByUserID fetches a user's posts deleted after the given time,
+// most recently deleted first
+func (r *postRepository) GetDeletedByUserID(ctx context.Context, userID string, deletedAfter time.Time, limit, offset int) ([]*model.Post, error) {
 	if limit <= 0 {
 		limit = 10 // Default limit
 	}
 
 	query := `
-		SELECT 
-			p.id, p.user_id, p.content, p.embed_urls, p.hashtags, 
-			p.visibility, p.created_at, p.updated_at,
-			u.username, u.image_url
+		SELECT ` + postColumns + `
 		FROM 
 			post p
 		JOIN 
-			"user" u ON p.user_id = u.id
+			users u ON p.user_id = u.id
 		WHERE 
-			p.user_id = $1
+			p.user_id = $1 AND
+			p.deleted_at >= $2
 		ORDER BY 
-			p.created_at DESC
-		LIMIT $2 OFFSET $3
+			p.deleted_at DESC, p.id DESC
+		LIMIT $3 OFFSET $4
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	return r.queryPosts(ctx, query, userID, deletedAfter, limit, offset)
+}
+
+// PurgeDeleted permanently removes up to limit posts deleted before the given
+// time, along with their reactions, comments and other dependent rows. It
+// returns the purged posts.
+func (r *postRepository) PurgeDeleted(ctx context.Context, deletedBefore time.Time, limit int) ([]*model.Post, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	query := `
+		DELETE FROM post
+		WHERE id IN (
+			SELECT id
+			FROM post
+			WHERE deleted_at < $1
+			ORDER BY deleted_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, user_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, deletedBefore, limit)
 	if err != nil {
-		return nil, fmt.Errorf("query user posts: %w", err)
+		return nil, fmt.Errorf("purge deleted posts: %w", err)
 	}
 	defer rows.Close()
 
 	var posts []*model.Post
-
 	for rows.Next() {
 		var post model.Post
-		var embedURLsJSON, hashtagsJSON []byte
-		var updatedAt sql.NullTime
-		var visibilityStr string
-		var username string
-		var imageURL sql.NullString
-
-		err := rows.Scan(
-			&post.ID,
-			&post.UserID,
-			&post.Content,
-			&embedURLsJSON,
-			&hashtagsJSON,
-			&visibilityStr,
-			&post.CreatedAt,
-			&updatedAt,
-			&username,
-			&imageURL,
-		)
-
-		if err != nil {
+		if err := rows.Scan(&post.ID, &post.UserID); err != nil {
 			return nil, fmt.Errorf("scan post row: %w", err)
 		}
+		posts = append(posts, &post)
+	}
 
-		// Parse JSON arrays
-		if len(embedURLsJSON) > 0 {
-			if err := json.Unmarshal(embedURLsJSON, &post.EmbedURLs); err != nil {
-				return nil, fmt.Errorf("unmarshal embed URLs: %w", err)
-			}
-		}
-
-		if len(hashtagsJSON) > 0 {
-			if err := json.Unmarshal(hashtagsJSON, &post.Hashtags); err != nil {
-				return nil, fmt.Errorf("unmarshal hashtags: %w", err)
-			}
-		}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
 
-		if updatedAt.Valid {
-			post.UpdatedAt = &updatedAt.Time
-		}
+	return posts, nil
+}
 
-		post.Visibility = model.Visibility(visibilityStr)
+// Pin pins a user's post, failing when maxPinned posts are already pinned.
+// Pinning a pinned post has no effect.
+func (r *postRepository) Pin(ctx context.Context, id string, userID string, maxPinned int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
 
-		// Set up basic author information
-		author := &model.User{
-			ID:       post.UserID,
-			Username: username,
+	// Lock the user so concurrent pins cannot exceed the limit
+	var lockedID string
+	err = tx.QueryRowContext(ctx, `SELECT id FROM users WHERE id = $1 FOR UPDATE`, userID).Scan(&lockedID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.NotFound(fmt.Sprintf("user: %s", userID))
 		}
+		return fmt.Errorf("lock user: %w", err)
+	}
 
-		if imageURL.Valid {
-			author.ImageURL = &imageURL.String
+	var pinned bool
+	err = tx.QueryRowContext(ctx, `SELECT pinned_at IS NOT NULL FROM post WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL`, id, userID).Scan(&pinned)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.NotFound(fmt.Sprintf("post: %s", id))
 		}
+		return fmt.Errorf("query post: %w", err)
+	}
 
-		post.Author = author
+	if pinned {
+		return nil
+	}
 
-		posts = append(posts, &post)
+	var count int
+	err = tx.QueryRowContext(ctx, `SELECT COUNT(*) FROM post WHERE user_id = $1 AND pinned_at IS NOT NULL`, userID).Scan(&count)
+	if err != nil {
+		return fmt.Errorf("count pinned posts: %w", err)
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows iteration: %w", err)
+	if count >= maxPinned {
+		return apperrors.Conflict(fmt.Sprintf("you can pin at most %d posts", maxPinned))
 	}
 
-	return posts, nil
-}
+	if _, err := tx.ExecContext(ctx, `UPDATE post SET pinned_at = $1 WHERE id = $2`, time.Now().UTC(), id); err != nil {
+		return fmt.Errorf("pin post: %w", err)
+	}
 
-// GetFeed fetches posts for a feed
-func (r *postRepository) GetFeed(ctx context.Context, userIDs []string, limit, offset int) ([]*model.Post, error) {
-	if limit <= 0 {
-		limit = 10 // Default limit
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
 	}
 
-	// If no userIDs provided, return empty slice
-	if len(userIDs) == 0 {
-		return []*model.Post{}, nil
+	return nil
+}
+
+// Unpin unpins a post. Unpinning a post that is not pinned has no effect.
+func (r *postRepository) Unpin(ctx context.Context, id string) error {
+	query := `UPDATE post SET pinned_at = NULL WHERE id = $1 RETURNING id`
+
+	var unpinnedID string
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&unpinnedID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.NotFound(fmt.Sprintf("post: %s", id))
+		}
+		return fmt.Errorf("unpin post: %w", err)
 	}
 
+	return nil
+}
+
+// GetRepost fetches a user's plain repost of a post
+func (r *postRepository) GetRepost(ctx context.Context, originalID string, userID string) (*model.Post, error) {
 	query := `
-		SELECT 
-			p.id, p.user_id, p.content, p.embed_urls, p.hashtags, 
-			p.visibility, p.created_at, p.updated_at,
-			u.username, u.image_url
+		SELECT ` + postColumns + `
 		FROM 
 			post p
 		JOIN 
-			"user" u ON p.user_id = u.id
+			users u ON p.user_id = u.id
 		WHERE 
-			p.user_id = ANY($1) AND
-			p.visibility = 'public'
-		ORDER BY 
-			p.created_at DESC
-		LIMIT $2 OFFSET $3
+			p.original_post_id = $1 AND
+			p.user_id = $2 AND
+			p.kind = 'repost'
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, userIDs, limit, offset)
+	post, err := scanPost(r.db.QueryRowContext(ctx, query, originalID, userID))
 	if err != nil {
-		return nil, fmt.Errorf("query feed posts: %w", err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NotFound(fmt.Sprintf("repost: %s", originalID))
+		}
+		return nil, fmt.Errorf("query repost: %w", err)
 	}
-	defer rows.Close()
 
-	var posts []*model.Post
+	return post, nil
+}
 
-	for rows.Next() {
-		var post model.Post
-		var embedURLsJSON, hashtagsJSON []byte
-		var updatedAt sql.NullTime
-		var visibilityStr string
-		var username string
-		var imageURL sql.NullString
-
-		err := rows.Scan(
-			&post.ID,
-			&post.UserID,
-			&post.Content,
-			&embedURLsJSON,
-			&hashtagsJSON,
-			&visibilityStr,
-			&post.CreatedAt,
-			&updatedAt,
-			&username,
-			&imageURL,
-		)
+// GetVisibleByIDs fetches the published posts among ids that the viewer may see.
+// Missing and hidden posts are left out.
+func (r *postRepository) GetVisibleByIDs(ctx context.Context, ids []string, viewerID string) ([]*model.Post, error) {
+	if len(ids) == 0 {
+		return []*model.Post{}, nil
+	}
 
-		if err != nil {
-			return nil, fmt.Errorf("scan feed post row: %w", err)
-		}
+	query := `
+		SELECT ` + postColumns + `
+		FROM 
+			post p
+		JOIN 
+			users u ON p.user_id = u.id
+		WHERE 
+			p.id = ANY($1) AND
+			p.deleted_at IS NULL AND
+			p.status = 'published' AND
+			` + visibleToViewer("$2") + `
+	`
 
-		// Parse JSON arrays
-		if len(embedURLsJSON) > 0 {
-			if err := json.Unmarshal(embedURLsJSON, &post.EmbedURLs); err != nil {
-				return nil, fmt.Errorf("unmarshal embed URLs: %w", err)
-			}
-		}
+	posts, err := r.queryPosts(ctx, query, ids, viewerID)
+	if err != nil {
+		return nil, fmt.Errorf("query posts by IDs: %w", err)
+	}
 
-		if len(hashtagsJSON) > 0 {
-			if err := json.Unmarshal(hashtagsJSON, &post.Hashtags); err != nil {
-				return nil, fmt.Errorf("unmarshal hashtags: %w", err)
-			}
-		}
+	return posts, nil
+}
 
-		if updatedAt.Valid {
-			post.UpdatedAt = &updatedAt.Time
-		}
+// GetRevisions fetches the revisions of a post, newest first
+func (r *postRepository) GetRevisions(ctx context.Context, postID string, limit, offset int) ([]*model.PostRevision, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
 
-		post.Visibility = model.Visibility(visibilityStr)
+	query := `
+		SELECT
+			post_id, revision, content, array_to_json(embed_urls), array_to_json(hashtags), created_at
+		FROM
+			post_revision
+		WHERE
+			post_id = $1
+		ORDER BY
+			revision DESC
+		LIMIT $2 OFFSET $3
+	`
 
-		// Set up basic author information
-		author := &model.User{
-			ID:       post.UserID,
-			Username: username,
-		}
+	rows, err := r.db.QueryContext(ctx, query, postID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("query post revisions: %w", err)
+	}
+	defer rows.Close()
 
-		if imageURL.Valid {
-			author.ImageURL = &imageURL.String
-		}
+	var revisions []*model.PostRevision
 
-		post.Author = author
+	for rows.Next() {
+		revision, err := scanRevision(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan post revision row: %w", err)
+		}
 
-		posts = append(posts, &post)
+		revisions = append(revisions, revision)
 	}
 
 	if err := rows.Err(); err != nil {
 		return nil, fmt.Errorf("rows iteration: %w", err)
 	}
 
-	return posts, nil
+	return revisions, nil
+}
+
+// GetRevision fetches a single revision of a post
+func (r *postRepository) GetRevision(ctx context.Context, postID string, revision int) (*model.PostRevision, error) {
+	query := `
+		SELECT
+			post_id, revision, content, array_to_json(embed_urls), array_to_json(hashtags), created_at
+		FROM
+			post_revision
+		WHERE
+			post_id = $1 AND revision = $2
+	`
+
+	rev, err := scanRevision(r.db.QueryRowContext(ctx, query, postID, revision))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NotFound(fmt.Sprintf("post revision: %s/%d", postID, revision))
+		}
+		return nil, fmt.Errorf("query post revision: %w", err)
+	}
+
+	return rev, nil
+}
+
+// GetByUserID fetches the published posts of a user that the viewer may see.
+// Pinned posts come first, most recently pinned first. Pages continue after
+// the cursor when one is given, and skip offset rows otherwise.
+func (r *postRepository) GetByUserID(ctx context.Context, userID string, viewerID string, cursor *pagination.Cursor, limit, offset int) ([]*model.Post, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	query := `
+		SELECT ` + postColumns + `
+		FROM 
+			post p
+		JOIN 
+			users u ON p.user_id = u.id
+		WHERE 
+			p.user_id = $1 AND
+			p.deleted_at IS NULL AND
+			p.status = 'published' AND
+			` + visibleToViewer("$2")
+	params := []interface{}{userID, viewerID}
+
+	switch {
+	case cursor != nil && cursor.PinnedAt != nil:
+		// The rest of the pinned posts, then every unpinned post
+		params = append(params, *cursor.PinnedAt, cursor.CreatedAt, cursor.ID)
+		query += fmt.Sprintf(" AND (p.pinned_at IS NULL OR (p.pinned_at, p.created_at, p.id) < ($%d, $%d, $%d))", len(params)-2, len(params)-1, len(params))
+	case cursor != nil:
+		params = append(params, cursor.CreatedAt, cursor.ID)
+		query += fmt.Sprintf(" AND p.pinned_at IS NULL AND (p.created_at, p.id) < ($%d, $%d)", len(params)-1, len(params))
+	}
+
+	params = append(params, limit)
+	query += fmt.Sprintf(" ORDER BY p.pinned_at DESC NULLS LAST, p.created_at DESC, p.id DESC LIMIT $%d", len(params))
+
+	if cursor == nil {
+		params = append(params, offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(params))
+	}
+
+	posts, err := r.queryPosts(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("query user posts: %w", err)
+	}
+
+	return posts, nil
+}
+
+// GetFeed fetches the home timeline of a user, newest first: the posts fanned
+// out to their timeline, plus posts by followed authors that are read from
+// the author instead. Pages continue after the cursor when one is given, and
+// skip offset rows otherwise.
+func (r *postRepository) GetFeed(ctx context.Context, userID string, cursor *pagination.Cursor, limit, offset int) ([]*model.Post, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	params := []interface{}{userID}
+	timelineAfter, postAfter := "", ""
+	if cursor != nil {
+		params = append(params, cursor.CreatedAt, cursor.ID)
+		timelineAfter = fmt.Sprintf(" AND (t.created_at, t.post_id) < ($%d, $%d)", len(params)-1, len(params))
+		postAfter = fmt.Sprintf(" AND (p.created_at, p.id) < ($%d, $%d)", len(params)-1, len(params))
+	}
+
+	// Each source is filtered and limited on its own so a page only reads as
+	// many rows from either as the page can use
+	sourceLimit := limit
+	if cursor == nil {
+		sourceLimit += offset
+	}
+	params = append(params, sourceLimit)
+	limitParam := fmt.Sprintf("$%d", len(params))
+
+	filters := `
+				p.deleted_at IS NULL AND
+				p.status = 'published' AND
+				NOT EXISTS (SELECT 1 FROM mutes m WHERE m.muter_id = $1 AND m.muted_id = p.user_id) AND
+				` + visibleToViewer("$1")
+
+	query := `
+		SELECT ` + postColumns + `
+		FROM 
+			post p
+		JOIN 
+			users u ON p.user_id = u.id
+		WHERE 
+			p.id IN (
+				(SELECT p.id FROM timeline t
+				JOIN post p ON p.id = t.post_id
+				WHERE t.user_id = $1` + timelineAfter + ` AND` + filters + `
+				ORDER BY t.created_at DESC, t.post_id DESC LIMIT ` + limitParam + `)
+				UNION ALL
+				(SELECT p.id FROM post p
+				JOIN follows fo ON fo.following_id = p.user_id
+				WHERE fo.follower_id = $1 AND p.fanout_on_read` + postAfter + ` AND` + filters + `
+				ORDER BY p.created_at DESC, p.id DESC LIMIT ` + limitParam + `)
+			)`
+
+	params = append(params, limit)
+	query += fmt.Sprintf(" ORDER BY p.created_at DESC, p.id DESC LIMIT $%d", len(params))
+
+	if cursor == nil {
+		params = append(params, offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(params))
+	}
+
+	posts, err := r.queryPosts(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("query feed posts: %w", err)
+	}
+
+	return posts, nil
+}
+
+// GetDraftsByUserID fetches a user's drafts, most recently saved first
+func (r *postRepository) GetDraftsByUserID(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	query := `
+		SELECT ` + postColumns + `
+		FROM 
+			post p
+		JOIN 
+			users u ON p.user_id = u.id
+		WHERE 
+			p.user_id = $1 AND
+			p.deleted_at IS NULL AND
+			p.status = 'draft'
+		ORDER BY 
+			COALESCE(p.updated_at, p.created_at) DESC, p.id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	posts, err := r.queryPosts(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("query drafts: %w", err)
+	}
+
+	return posts, nil
+}
+
+// UpdateDraft saves the content of a draft. Unlike Update, no revision is kept.
+func (r *postRepository) UpdateDraft(ctx context.Context, post *model.Post) error {
+	query := `
+		UPDATE post
+		SET 
+			content = $1, 
+			embed_urls = $2, 
+			hashtags = $3, 
+			updated_at = $4
+		WHERE 
+			id = $5 AND
+			status = 'draft' AND
+			deleted_at IS NULL
+		RETURNING id
+	`
+
+	now := time.Now().UTC()
+
+	var id string
+	err := r.db.QueryRowContext(ctx, query,
+		post.Content,
+		post.EmbedURLs,
+		post.Hashtags,
+		now,
+		post.ID,
+	).Scan(&id)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.NotFound(fmt.Sprintf("draft: %s", post.ID))
+		}
+		return fmt.Errorf("update draft: %w", err)
+	}
+
+	post.UpdatedAt = &now
+
+	return nil
+}
+
+// PublishDraft turns a draft into a published or scheduled post along with
+// its mentions. The post is dated from the moment it is published.
+func (r *postRepository) PublishDraft(ctx context.Context, post *model.Post) error {
+	query := `
+		UPDATE post
+		SET 
+			content = $1, 
+			embed_urls = $2, 
+			hashtags = $3, 
+			visibility = $4,
+			status = $5,
+			publish_at = $6,
+			created_at = $7,
+			updated_at = NULL
+		WHERE 
+			id = $8 AND
+			status = 'draft' AND
+			deleted_at IS NULL
+		RETURNING id
+	`
+
+	now := time.Now().UTC()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var id string
+	err = tx.QueryRowContext(ctx, query,
+		post.Content,
+		post.EmbedURLs,
+		post.Hashtags,
+		post.Visibility,
+		post.Status,
+		post.PublishAt,
+		now,
+		post.ID,
+	).Scan(&id)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.NotFound(fmt.Sprintf("draft: %s", post.ID))
+		}
+		return fmt.Errorf("publish draft: %w", err)
+	}
+
+	if err := insertMentions(ctx, tx, post.ID, post.Mentions); err != nil {
+		return err
+	}
+
+	if err := enqueueLinkPreviews(ctx, tx, post.EmbedURLs); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	post.CreatedAt = now
+	post.UpdatedAt = nil
+
+	return nil
+}
+
+// GetScheduledByUserID fetches a user's scheduled posts, soonest first
+func (r *postRepository) GetScheduledByUserID(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	query := `
+		SELECT ` + postColumns + `
+		FROM 
+			post p
+		JOIN 
+			users u ON p.user_id = u.id
+		WHERE 
+			p.user_id = $1 AND
+			p.deleted_at IS NULL AND
+			p.status = 'scheduled'
+		ORDER BY 
+			p.publish_at ASC, p.id ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	posts, err := r.queryPosts(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("query scheduled posts: %w", err)
+	}
+
+	return posts, nil
+}
+
+// PublishDue publishes up to limit scheduled posts whose publish time has
+// passed and returns them. Rows locked by another instance are skipped, so
+// several publishers can run concurrently without publishing a post twice.
+// Published posts take the time they went live as their creation time so
+// they show up at the top of timelines.
+func (r *postRepository) PublishDue(ctx context.Context, now time.Time, limit int) ([]*model.Post, error) {
+	query := `
+		UPDATE post
+		SET 
+			status = 'published',
+			created_at = $1
+		WHERE id IN (
+			SELECT id FROM post
+			WHERE status = 'scheduled' AND publish_at <= $1 AND deleted_at IS NULL
+			ORDER BY publish_at
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, user_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("publish due posts: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []*model.Post
+
+	for rows.Next() {
+		post := &model.Post{Status: model.PostStatusPublished, CreatedAt: now}
+
+		if err := rows.Scan(&post.ID, &post.UserID); err != nil {
+			return nil, fmt.Errorf("scan published post row: %w", err)
+		}
+
+		posts = append(posts, post)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return posts, nil
+}
+
+// FanOut puts a published post on the timelines of its author and their
+// followers. Posts by authors with more than maxFollowers followers are only
+// put on the author's timeline and marked to be read from the author instead,
+// so publishing stays cheap for large accounts. It reports whether the post
+// was fanned out to the followers.
+func (r *postRepository) FanOut(ctx context.Context, postID string, maxFollowers int) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var followers int
+	err = tx.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM follows f
+		JOIN post p ON p.user_id = f.following_id
+		WHERE p.id = $1
+	`, postID).Scan(&followers)
+	if err != nil {
+		return false, fmt.Errorf("count followers: %w", err)
+	}
+
+	fanOut := followers <= maxFollowers
+
+	query := `
+		INSERT INTO timeline (user_id, post_id, author_id, created_at)
+		SELECT p.user_id, p.id, p.user_id, p.created_at
+		FROM post p
+		WHERE p.id = $1
+	`
+	if fanOut {
+		query += `
+		UNION ALL
+		SELECT f.follower_id, p.id, p.user_id, p.created_at
+		FROM post p
+		JOIN follows f ON f.following_id = p.user_id
+		WHERE p.id = $1
+		`
+	}
+	query += ` ON CONFLICT DO NOTHING`
+
+	if _, err := tx.ExecContext(ctx, query, postID); err != nil {
+		return false, fmt.Errorf("fan out post: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `UPDATE post SET fanout_on_read = $1 WHERE id = $2`, !fanOut, postID); err != nil {
+		return false, fmt.Errorf("mark post fan-out: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return fanOut, nil
+}
+
+// GetFanoutOnReadAuthors returns the accounts a user follows that have posts
+// read from the author instead of fanned out, ordered by ID
+func (r *postRepository) GetFanoutOnReadAuthors(ctx context.Context, userID string) ([]string, error) {
+	query := `
+		SELECT f.following_id
+		FROM follows f
+		WHERE f.follower_id = $1 AND EXISTS (
+			SELECT 1 FROM post p WHERE p.user_id = f.following_id AND p.fanout_on_read
+		)
+		ORDER BY f.following_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query fan-out-on-read authors: %w", err)
+	}
+	defer rows.Close()
+
+	var authorIDs []string
+	for rows.Next() {
+		var authorID string
+		if err := rows.Scan(&authorID); err != nil {
+			return nil, fmt.Errorf("scan author row: %w", err)
+		}
+		authorIDs = append(authorIDs, authorID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return authorIDs, nil
+}
+
+// HasFanoutOnReadPosts reports whether any post of a user is read from them
+// instead of fanned out
+func (r *postRepository) HasFanoutOnReadPosts(ctx context.Context, userID string) (bool, error) {
+	query := `SELECT EXISTS (SELECT 1 FROM post WHERE user_id = $1 AND fanout_on_read)`
+
+	var exists bool
+	if err := r.db.QueryRowContext(ctx, query, userID).Scan(&exists); err != nil {
+		return false, fmt.Errorf("check fan-out-on-read posts: %w", err)
+	}
+
+	return exists, nil
+}
+
+// GetMentioning fetches posts that mention a user, newest first
+func (r *postRepository) GetMentioning(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	query := `
+		SELECT ` + postColumns + `
+		FROM 
+			post p
+		JOIN 
+			users u ON p.user_id = u.id
+		WHERE 
+			EXISTS (
+				SELECT 1 FROM post_mention m
+				WHERE m.post_id = p.id AND m.user_id = $1
+			) AND
+			` + visibleToViewer("$1") + ` AND
+			p.deleted_at IS NULL AND
+			p.status = 'published'
+		ORDER BY 
+			p.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	posts, err := r.queryPosts(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("query mentioning posts: %w", err)
+	}
+
+	return posts, nil
+}
+
+// GetReactionSummaries aggregates reaction counts for a set of posts in a single query.
+// The viewer's own reaction is included when viewerID is set.
+func (r *postRepository) GetReactionSummaries(ctx context.Context, postIDs []string, viewerID string) (map[string]*model.ReactionSummary, error) {
+	summaries := make(map[string]*model.ReactionSummary, len(postIDs))
+	for _, id := range postIDs {
+		summaries[id] = &model.ReactionSummary{
+			Counts: map[model.ReactionType]int{},
+		}
+	}
+
+	if len(postIDs) == 0 {
+		return summaries, nil
+	}
+
+	query := `
+		SELECT
+			post_id, type, COUNT(*), BOOL_OR(user_id = $2)
+		FROM
+			reaction
+		WHERE
+			post_id = ANY($1)
+		GROUP BY
+			post_id, type
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, postIDs, viewerID)
+	if err != nil {
+		return nil, fmt.Errorf("query reaction summaries: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID, typeStr string
+		var count int
+		var viewerReacted bool
+
+		if err := rows.Scan(&postID, &typeStr, &count, &viewerReacted); err != nil {
+			return nil, fmt.Errorf("scan reaction summary row: %w", err)
+		}
+
+		summary, ok := summaries[postID]
+		if !ok {
+			continue
+		}
+
+		reactionType := model.ReactionType(typeStr)
+		summary.Counts[reactionType] = count
+		summary.Total += count
+
+		if viewerReacted {
+			summary.ViewerReaction = &reactionType
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return summaries, nil
+}
+
+// GetCommentCounts counts the visible comments on a set of posts, replies
+// included. Posts without comments are left out.
+func (r *postRepository) GetCommentCounts(ctx context.Context, postIDs []string) (map[string]int, error) {
+	counts := make(map[string]int, len(postIDs))
+
+	if len(postIDs) == 0 {
+		return counts, nil
+	}
+
+	query := `
+		SELECT
+			post_id, COUNT(*)
+		FROM
+			comment
+		WHERE
+			post_id = ANY($1) AND
+			hidden_at IS NULL
+		GROUP BY
+			post_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("query comment counts: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID string
+		var count int
+
+		if err := rows.Scan(&postID, &count); err != nil {
+			return nil, fmt.Errorf("scan comment count row: %w", err)
+		}
+
+		counts[postID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return counts, nil
+}
+
+// GetAffinities counts the reactions and comments the viewer left on posts by
+// each of a set of authors since the given time, keyed by author. Authors
+// the viewer has not interacted with are left out.
+func (r *postRepository) GetAffinities(ctx context.Context, viewerID string, authorIDs []string, since time.Time) (map[string]int, error) {
+	affinities := make(map[string]int, len(authorIDs))
+
+	if viewerID == "" || len(authorIDs) == 0 {
+		return affinities, nil
+	}
+
+	query := `
+		SELECT
+			p.user_id, COUNT(*)
+		FROM (
+			SELECT post_id FROM reaction WHERE user_id = $1 AND created_at >= $3
+			UNION ALL
+			SELECT post_id FROM comment WHERE user_id = $1 AND created_at >= $3
+		) i
+		JOIN
+			post p ON p.id = i.post_id
+		WHERE
+			p.user_id = ANY($2)
+		GROUP BY
+			p.user_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, viewerID, authorIDs, since)
+	if err != nil {
+		return nil, fmt.Errorf("query affinities: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var authorID string
+		var count int
+
+		if err := rows.Scan(&authorID, &count); err != nil {
+			return nil, fmt.Errorf("scan affinity row: %w", err)
+		}
+
+		affinities[authorID] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return affinities, nil
+}
+
+// GetLinkPreviews fetches the previews that have been unfurled for a set of
+// URLs, keyed by URL. URLs without a usable preview are left out.
+func (r *postRepository) GetLinkPreviews(ctx context.Context, urls []string) (map[string]*model.LinkPreview, error) {
+	previews := make(map[string]*model.LinkPreview, len(urls))
+
+	if len(urls) == 0 {
+		return previews, nil
+	}
+
+	query := `
+		SELECT
+			url, COALESCE(title, ''), COALESCE(description, ''), image_url,
+			COALESCE(site_name, ''), final_url, fetched_at
+		FROM
+			link_preview
+		WHERE
+			url = ANY($1) AND
+			status = 'ok'
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, urls)
+	if err != nil {
+		return nil, fmt.Errorf("query link previews: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var preview model.LinkPreview
+		var imageURL, finalURL sql.NullString
+
+		if err := rows.Scan(
+			&preview.URL,
+			&preview.Title,
+			&preview.Description,
+			&imageURL,
+			&preview.SiteName,
+			&finalURL,
+			&preview.FetchedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan link preview row: %w", err)
+		}
+
+		if imageURL.Valid {
+			preview.ImageURL = &imageURL.String
+		}
+
+		if finalURL.Valid {
+			preview.FinalURL = &finalURL.String
+		}
+
+		previews[preview.URL] = &preview
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return previews, nil
+}
+
+// GetMedia fetches the media attached to a set of posts, keyed by post ID
+func (r *postRepository) GetMedia(ctx context.Context, postIDs []string) (map[string][]*model.Media, error) {
+	media := make(map[string][]*model.Media, len(postIDs))
+
+	if len(postIDs) == 0 {
+		return media, nil
+	}
+
+	query := `
+		SELECT ` + mediaColumns + `
+		FROM
+			media m
+		WHERE
+			m.post_id = ANY($1)
+		ORDER BY
+			m.post_id, m.position
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, postIDs)
+	if err != nil {
+		return nil, fmt.Errorf("query post media: %w", err)
+	}
+	defer rows.Close()
+
+	items, err := scanMediaRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range items {
+		media[*item.PostID] = append(media[*item.PostID], item)
+	}
+
+	return media, nil
+}
+
+// GetPolls fetches the polls of a set of posts with their full results, keyed
+// by post ID. The viewer's vote is included when viewerID is set.
+func (r *postRepository) GetPolls(ctx context.Context, postIDs []string, viewerID string) (map[string]*model.Poll, error) {
+	polls := make(map[string]*model.Poll)
+
+	if len(postIDs) == 0 {
+		return polls, nil
+	}
+
+	query := `
+		SELECT
+			o.post_id, pl.closes_at, o.id, o.position, o.text,
+			(SELECT COUNT(*) FROM poll_vote v WHERE v.option_id = o.id),
+			EXISTS (SELECT 1 FROM poll_vote v WHERE v.option_id = o.id AND v.user_id = $2)
+		FROM
+			poll pl
+		JOIN
+			poll_option o ON o.post_id = pl.post_id
+		WHERE
+			pl.post_id = ANY($1)
+		ORDER BY
+			o.post_id, o.position
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, postIDs, viewerID)
+	if err != nil {
+		return nil, fmt.Errorf("query polls: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID string
+		var closesAt time.Time
+		var option model.PollOption
+		var count int
+		var voted bool
+
+		if err := rows.Scan(&postID, &closesAt, &option.ID, &option.Position, &option.Text, &count, &voted); err != nil {
+			return nil, fmt.Errorf("scan poll row: %w", err)
+		}
+
+		poll, ok := polls[postID]
+		if !ok {
+			total := 0
+			poll = &model.Poll{
+				PostID:     postID,
+				ClosesAt:   closesAt,
+				TotalVotes: &total,
+			}
+			polls[postID] = poll
+		}
+
+		option.VoteCount = &count
+		*poll.TotalVotes += count
+		if voted {
+			poll.ViewerOptionID = &option.ID
+		}
+
+		poll.Options = append(poll.Options, &option)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return polls, nil
+}
+
+// VotePoll records a user's vote for an option of an open poll. The primary
+// key on poll_vote allows a single vote per user, so concurrent requests
+// cannot vote twice; a second vote is reported as a conflict.
+func (r *postRepository) VotePoll(ctx context.Context, postID string, optionID string, userID string) error {
+	query := `
+		INSERT INTO poll_vote (post_id, user_id, option_id, created_at)
+		SELECT
+			o.post_id, $3, o.id, $4
+		FROM
+			poll_option o
+		JOIN
+			poll pl ON pl.post_id = o.post_id
+		WHERE
+			o.id = $2 AND
+			o.post_id = $1 AND
+			pl.closes_at > $4
+		ON CONFLICT (post_id, user_id) DO NOTHING
+	`
+
+	result, err := r.db.ExecContext(ctx, query, postID, optionID, userID, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("create poll vote: %w", err)
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("create poll vote: %w", err)
+	}
+
+	if inserted > 0 {
+		return nil
+	}
+
+	// Nothing was inserted: the user has voted already, or the option or
+	// the open poll is gone
+	var voted bool
+	err = r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM poll_vote WHERE post_id = $1 AND user_id = $2)`, postID, userID).Scan(&voted)
+	if err != nil {
+		return fmt.Errorf("query poll vote: %w", err)
+	}
+
+	if voted {
+		return apperrors.Conflict("you have already voted in this poll")
+	}
+
+	return apperrors.BadRequest("poll is closed or the option does not exist")
+}
+
+// GetByHashtag fetches public posts tagged with a hashtag, newest first,
+// leaving out posts by users in a block with the viewer
+func (r *postRepository) GetByHashtag(ctx context.Context, tag string, viewerID string, cursor *pagination.Cursor, limit int) ([]*model.Post, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	// The containment operator lets the GIN index on hashtags serve the lookup
+	query := `
+		SELECT ` + postColumns + `
+		FROM 
+			post p
+		JOIN 
+			users u ON p.user_id = u.id
+		WHERE 
+			p.hashtags @> ARRAY[$1]::TEXT[] AND
+			p.visibility = 'public' AND
+			p.hidden_at IS NULL AND
+			p.deleted_at IS NULL AND
+			p.status = 'published' AND
+			` + notBlocked("$2", "p.user_id")
+	params := []interface{}{tag, viewerID}
+
+	if cursor != nil {
+		params = append(params, cursor.CreatedAt, cursor.ID)
+		query += fmt.Sprintf(" AND (p.created_at, p.id) < ($%d, $%d)", len(params)-1, len(params))
+	}
+
+	params = append(params, limit)
+	query += fmt.Sprintf(" ORDER BY p.created_at DESC, p.id DESC LIMIT $%d", len(params))
+
+	posts, err := r.queryPosts(ctx, query, params...)
+	if err != nil {
+		return nil, fmt.Errorf("query hashtag posts: %w", err)
+	}
+
+	return posts, nil
+}
+
+// GetExploreCandidates fetches up to limit public posts published since the
+// given time by active accounts, newest first. Plain reposts are left out,
+// since the posts they share are candidates on their own.
+func (r *postRepository) GetExploreCandidates(ctx context.Context, since time.Time, limit int) ([]*model.Post, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	query := `
+		SELECT ` + postColumns + `
+		FROM 
+			post p
+		JOIN 
+			users u ON p.user_id = u.id
+		WHERE 
+			p.created_at >= $1 AND
+			p.kind <> 'repost' AND
+			p.visibility = 'public' AND
+			p.hidden_at IS NULL AND
+			p.deleted_at IS NULL AND
+			p.status = 'published' AND
+			u.is_active
+		ORDER BY 
+			p.created_at DESC, p.id DESC
+		LIMIT $2
+	`
+
+	posts, err := r.queryPosts(ctx, query, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query explore candidates: %w", err)
+	}
+
+	return posts, nil
+}
+
+// GetExcludedAuthors returns which of a set of authors the viewer follows or
+// is in a block with
+func (r *postRepository) GetExcludedAuthors(ctx context.Context, viewerID string, authorIDs []string) (map[string]bool, error) {
+	excluded := make(map[string]bool)
+
+	if viewerID == "" || len(authorIDs) == 0 {
+		return excluded, nil
+	}
+
+	query := `
+		SELECT following_id FROM follows WHERE follower_id = $1 AND following_id = ANY($2)
+		UNION
+		SELECT blocked_id FROM blocks WHERE blocker_id = $1 AND blocked_id = ANY($2)
+		UNION
+		SELECT blocker_id FROM blocks WHERE blocked_id = $1 AND blocker_id = ANY($2)
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, viewerID, authorIDs)
+	if err != nil {
+		return nil, fmt.Errorf("query excluded authors: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var authorID string
+		if err := rows.Scan(&authorID); err != nil {
+			return nil, fmt.Errorf("scan excluded author row: %w", err)
+		}
+		excluded[authorID] = true
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return excluded, nil
+}
+
+// GetTrendingHashtags ranks hashtags used in public posts since the given time.
+// Tags are ranked by the number of distinct authors first, so a single account
+// repeating a tag cannot push it to the top on its own.
+func (r *postRepository) GetTrendingHashtags(ctx context.Context, since time.Time, limit int) ([]*model.HashtagTrend, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	query := `
+		SELECT
+			tag, COUNT(*) AS post_count, COUNT(DISTINCT p.user_id) AS author_count
+		FROM
+			post p,
+			unnest(p.hashtags) AS tag
+		WHERE
+			p.created_at >= $1 AND
+			p.visibility = 'public' AND
+			p.hidden_at IS NULL AND
+			p.deleted_at IS NULL AND
+			p.status = 'published'
+		GROUP BY
+			tag
+		ORDER BY
+			author_count DESC, post_count DESC, tag ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, since, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query trending hashtags: %w", err)
+	}
+	defer rows.Close()
+
+	var trends []*model.HashtagTrend
+
+	for rows.Next() {
+		var trend model.HashtagTrend
+
+		if err := rows.Scan(&trend.Tag, &trend.PostCount, &trend.AuthorCount); err != nil {
+			return nil, fmt.Errorf("scan trending hashtag row: %w", err)
+		}
+
+		trends = append(trends, &trend)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return trends, nil
+}
+
+// Snippet highlights are marked with private use characters so the snippet
+// can be HTML-escaped before the <mark> tags are put in
+const (
+	snippetStartSel = "\uE000"
+	snippetStopSel  = "\uE001"
+)
+
+var snippetMarkup = strings.NewReplacer(snippetStartSel, "<mark>", snippetStopSel, "</mark>")
+
+// highlightSnippet escapes a ts_headline snippet and turns its selectors into
+// <mark> tags
+func highlightSnippet(snippet string) string {
+	return snippetMarkup.Replace(html.EscapeString(snippet))
+}
+
+// Search runs a full-text search over the published posts the viewer may see,
+// best matches first
+func (r *postRepository) Search(ctx context.Context, query string, viewerID string, cursor *pagination.Cursor, limit int) ([]*model.Post, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	// Rank is cast to float8 so the value handed out in cursors compares
+	// exactly against the one computed here
+	sqlQuery := `
+		SELECT ` + postColumns + `,
+			ts_rank(p.search_vector, q)::FLOAT8 AS rank,
+			ts_headline('english', replace(replace(p.content, $3, ''), $4, ''), q, $5)
+		FROM 
+			post p
+		JOIN 
+			users u ON p.user_id = u.id,
+			websearch_to_tsquery('english', $1) q
+		WHERE 
+			p.search_vector @@ q AND
+			` + visibleToViewer("$2") + ` AND
+			p.deleted_at IS NULL AND
+			p.status = 'published'`
+	params := []interface{}{query, viewerID, snippetStartSel, snippetStopSel,
+		"StartSel=" + snippetStartSel + ", StopSel=" + snippetStopSel + ", MaxFragments=2, MaxWords=30, MinWords=10"}
+
+	if cursor != nil {
+		params = append(params, cursor.Rank, cursor.CreatedAt, cursor.ID)
+		sqlQuery += fmt.Sprintf(" AND (ts_rank(p.search_vector, q)::FLOAT8, p.created_at, p.id) < ($%d, $%d, $%d)",
+			len(params)-2, len(params)-1, len(params))
+	}
+
+	params = append(params, limit)
+	sqlQuery += fmt.Sprintf(" ORDER BY rank DESC, p.created_at DESC, p.id DESC LIMIT $%d", len(params))
+
+	rows, err := r.db.QueryContext(ctx, sqlQuery, params...)
+	if err != nil {
+		return nil, fmt.Errorf("search posts: %w", err)
+	}
+	defer rows.Close()
+
+	var posts []*model.Post
+
+	for rows.Next() {
+		var match model.SearchMatch
+
+		post, err := scanPost(rows, &match.Rank, &match.Snippet)
+		if err != nil {
+			return nil, fmt.Errorf("scan post row: %w", err)
+		}
+
+		match.Snippet = highlightSnippet(match.Snippet)
+		post.Search = &match
+		posts = append(posts, post)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	if err := r.attachMentions(ctx, posts); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// queryPosts runs a post list query and attaches the mentions of every returned post
+func (r *postRepository) queryPosts(ctx context.Context, query string, args ...interface{}) ([]*model.Post, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var posts []*model.Post
+
+	for rows.Next() {
+		post, err := scanPost(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan post row: %w", err)
+		}
+
+		posts = append(posts, post)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	if err := r.attachMentions(ctx, posts); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
+
+// attachMentions loads the mentions of a set of posts in a single query
+func (r *postRepository) attachMentions(ctx context.Context, posts []*model.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	postIDs := make([]string, 0, len(posts))
+	byID := make(map[string]*model.Post, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+		byID[post.ID] = post
+	}
+
+	query := `
+		SELECT post_id, user_id, username, start_offset, end_offset
+		FROM post_mention
+		WHERE post_id = ANY($1)
+		ORDER BY post_id, start_offset
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, postIDs)
+	if err != nil {
+		return fmt.Errorf("query post mentions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var postID string
+		var mention model.Mention
+
+		if err := rows.Scan(&postID, &mention.UserID, &mention.Username, &mention.Start, &mention.End); err != nil {
+			return fmt.Errorf("scan post mention row: %w", err)
+		}
+
+		if post, ok := byID[postID]; ok {
+			post.Mentions = append(post.Mentions, mention)
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return nil
+}
+
+// attachMedia claims the uploads listed in post.MediaIDs for the post inside
+// a transaction, keeping their order. Only the author's own uploads that are
+// not attached to another post can be claimed.
+func attachMedia(ctx context.Context, tx *sql.Tx, post *model.Post) error {
+	if len(post.MediaIDs) == 0 {
+		return nil
+	}
+
+	query := `
+		UPDATE media
+		SET 
+			post_id = $1,
+			position = array_position($2::TEXT[], id)
+		WHERE 
+			id = ANY($2) AND
+			user_id = $3 AND
+			post_id IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, query, post.ID, post.MediaIDs, post.UserID)
+	if err != nil {
+		return fmt.Errorf("attach media: %w", err)
+	}
+
+	attached, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("attach media: %w", err)
+	}
+
+	if int(attached) != len(post.MediaIDs) {
+		return apperrors.BadRequest("media not found or already attached to a post")
+	}
+
+	return nil
+}
+
+// enqueueLinkPreviews queues the embedded URLs of a post for unfurling inside
+// a transaction. URLs fetched recently are left alone; older previews are
+// queued again so they get refreshed.
+func enqueueLinkPreviews(ctx context.Context, tx *sql.Tx, urls []string) error {
+	if len(urls) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO link_preview (url, status, created_at)
+		SELECT DISTINCT u, 'pending', $2::TIMESTAMPTZ FROM UNNEST($1::TEXT[]) AS u
+		ON CONFLICT (url) DO UPDATE
+		SET 
+			status = 'pending',
+			attempts = 0
+		WHERE 
+			link_preview.status IN ('ok', 'failed') AND
+			link_preview.fetched_at < $3
+	`
+
+	now := time.Now().UTC()
+	if _, err := tx.ExecContext(ctx, query, urls, now, now.Add(-linkPreviewRefreshAfter)); err != nil {
+		return fmt.Errorf("enqueue link previews: %w", err)
+	}
+
+	return nil
+}
+
+// insertPoll stores the poll of a new post inside a transaction
+func insertPoll(ctx context.Context, tx *sql.Tx, postID string, poll *model.Poll) error {
+	if poll == nil {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	if _, err := tx.ExecContext(ctx, `INSERT INTO poll (post_id, closes_at, created_at) VALUES ($1, $2, $3)`, postID, poll.ClosesAt, now); err != nil {
+		return fmt.Errorf("create poll: %w", err)
+	}
+
+	for _, option := range poll.Options {
+		if option.ID == "" {
+			option.ID = uuid.New().String()
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO poll_option (id, post_id, position, text)
+			VALUES ($1, $2, $3, $4)
+		`, option.ID, postID, option.Position, option.Text)
+		if err != nil {
+			return fmt.Errorf("create poll option: %w", err)
+		}
+	}
+
+	poll.PostID = postID
+
+	return nil
+}
+
+// insertMentions stores the mentions of a post inside a transaction
+func insertMentions(ctx context.Context, tx *sql.Tx, postID string, mentions []model.Mention) error {
+	for _, mention := range mentions {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO post_mention (post_id, user_id, username, start_offset, end_offset)
+			VALUES ($1, $2, $3, $4, $5)
+		`, postID, mention.UserID, mention.Username, mention.Start, mention.End)
+		if err != nil {
+			return fmt.Errorf("create post mention: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// scanPost scans a post row joined with its author's username and image.
+// Any extra destinations receive columns selected after postColumns.
+func scanPost(row rowScanner, extra ...interface{}) (*model.Post, error) {
+	var post model.Post
+	var embedURLsJSON, hashtagsJSON []byte
+	var updatedAt sql.NullTime
+	var visibilityStr, statusStr, kindStr string
+	var publishAt sql.NullTime
+	var originalPostID sql.NullString
+	var pinnedAt, deletedAt, hiddenAt sql.NullTime
+	var username string
+	var imageURL sql.NullString
+
+	dest := []interface{}{
+		&post.ID,
+		&post.UserID,
+		&post.Content,
+		&embedURLsJSON,
+		&hashtagsJSON,
+		&visibilityStr,
+		&statusStr,
+		&publishAt,
+		&post.CreatedAt,
+		&updatedAt,
+		&post.RevisionCount,
+		&kindStr,
+		&originalPostID,
+		&post.RepostCount,
+		&pinnedAt,
+		&deletedAt,
+		&hiddenAt,
+		&username,
+		&imageURL,
+	}
+
+	err := row.Scan(append(dest, extra...)...)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Parse array columns selected as JSON
+	if len(embedURLsJSON) > 0 {
+		if err := json.Unmarshal(embedURLsJSON, &post.EmbedURLs); err != nil {
+			return nil, fmt.Errorf("unmarshal embed URLs: %w", err)
+		}
+	}
+
+	if len(hashtagsJSON) > 0 {
+		if err := json.Unmarshal(hashtagsJSON, &post.Hashtags); err != nil {
+			return nil, fmt.Errorf("unmarshal hashtags: %w", err)
+		}
+	}
+
+	if updatedAt.Valid {
+		post.UpdatedAt = &updatedAt.Time
+	}
+
+	if publishAt.Valid {
+		post.PublishAt = &publishAt.Time
+	}
+
+	if originalPostID.Valid {
+		post.OriginalPostID = &originalPostID.String
+	}
+
+	if pinnedAt.Valid {
+		post.Pinned = true
+		post.PinnedAt = &pinnedAt.Time
+	}
+
+	if deletedAt.Valid {
+		post.DeletedAt = &deletedAt.Time
+	}
+
+	if hiddenAt.Valid {
+		post.HiddenAt = &hiddenAt.Time
+	}
+
+	post.Visibility = model.Visibility(visibilityStr)
+	post.Status = model.PostStatus(statusStr)
+	post.Kind = model.PostKind(kindStr)
+
+	// Set up basic author information
+	author := &model.User{
+		ID:       post.UserID,
+		Username: username,
+	}
+
+	if imageURL.Valid {
+		author.ImageURL = &imageURL.String
+	}
+
+	post.Author = author
+
+	return &post, nil
+}
+
+// scanRevision scans a post revision row
+func scanRevision(row rowScanner) (*model.PostRevision, error) {
+	var revision model.PostRevision
+	var embedURLsJSON, hashtagsJSON []byte
+
+	err := row.Scan(
+		&revision.PostID,
+		&revision.Revision,
+		&revision.Content,
+		&embedURLsJSON,
+		&hashtagsJSON,
+		&revision.CreatedAt,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(embedURLsJSON) > 0 {
+		if err := json.Unmarshal(embedURLsJSON, &revision.EmbedURLs); err != nil {
+			return nil, fmt.Errorf("unmarshal embed URLs: %w", err)
+		}
+	}
+
+	if len(hashtagsJSON) > 0 {
+		if err := json.Unmarshal(hashtagsJSON, &revision.Hashtags); err != nil {
+			return nil, fmt.Errorf("unmarshal hashtags: %w", err)
+		}
+	}
+
+	return &revision, nil
 }
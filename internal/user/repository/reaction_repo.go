@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/platform/database"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/google/uuid"
+)
+
+// ReactionRepository defines methods to interact with reaction data
+type ReactionRepository interface {
+	Create(ctx context.Context, reaction *model.Reaction) error
+	GetByPostAndUser(ctx context.Context, postID, userID string) (*model.Reaction, error)
+	UpdateType(ctx context.Context, postID, userID string, reactionType model.ReactionType) (*model.Reaction, error)
+	Delete(ctx context.Context, postID, userID string) error
+	GetByPostID(ctx context.Context, postID string, limit, offset int) ([]*model.Reaction, error)
+}
+
+type reactionRepository struct {
+	db *database.DB
+}
+
+// NewReactionRepository creates a new ReactionRepository
+func NewReactionRepository(db *database.DB) ReactionRepository {
+	return &reactionRepository{
+		db: db,
+	}
+}
+
+// Create adds a new reaction, failing if the user already reacted to the post
+func (r *reactionRepository) Create(ctx context.Context, reaction *model.Reaction) error {
+	// Generate ID if not provided
+	if reaction.ID == "" {
+		reaction.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO reaction (id, post_id, user_id, type, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (post_id, user_id) DO NOTHING
+		RETURNING id
+	`
+
+	now := time.Now().UTC()
+
+	var id string
+	err := r.db.QueryRowContext(ctx, query,
+		reaction.ID,
+		reaction.PostID,
+		reaction.UserID,
+		string(reaction.Type),
+		now,
+	).Scan(&id)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.Conflict("you have already reacted to this post")
+		}
+		return fmt.Errorf("create reaction: %w", err)
+	}
+
+	reaction.CreatedAt = now
+
+	return nil
+}
+
+// GetByPostAndUser fetches a user's reaction to a post
+func (r *reactionRepository) GetByPostAndUser(ctx context.Context, postID, userID string) (*model.Reaction, error) {
+	query := `
+		SELECT id, post_id, user_id, type, created_at
+		FROM reaction
+		WHERE post_id = $1 AND user_id = $2
+	`
+
+	var reaction model.Reaction
+	var typeStr string
+
+	err := r.db.QueryRowContext(ctx, query, postID, userID).Scan(
+		&reaction.ID,
+		&reaction.PostID,
+		&reaction.UserID,
+		&typeStr,
+		&reaction.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NotFound(fmt.Sprintf("reaction on post: %s", postID))
+		}
+		return nil, fmt.Errorf("query reaction: %w", err)
+	}
+
+	reaction.Type = model.ReactionType(typeStr)
+
+	return &reaction, nil
+}
+
+// UpdateType changes the type of an existing reaction
+func (r *reactionRepository) UpdateType(ctx context.Context, postID, userID string, reactionType model.ReactionType) (*model.Reaction, error) {
+	query := `
+		UPDATE reaction
+		SET type = $1
+		WHERE post_id = $2 AND user_id = $3
+		RETURNING id, post_id, user_id, type, created_at
+	`
+
+	var reaction model.Reaction
+	var typeStr string
+
+	err := r.db.QueryRowContext(ctx, query, string(reactionType), postID, userID).Scan(
+		&reaction.ID,
+		&reaction.PostID,
+		&reaction.UserID,
+		&typeStr,
+		&reaction.CreatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NotFound(fmt.Sprintf("reaction on post: %s", postID))
+		}
+		return nil, fmt.Errorf("update reaction: %w", err)
+	}
+
+	reaction.Type = model.ReactionType(typeStr)
+
+	return &reaction, nil
+}
+
+// Delete removes a user's reaction to a post
+func (r *reactionRepository) Delete(ctx context.Context, postID, userID string) error {
+	query := `DELETE FROM reaction WHERE post_id = $1 AND user_id = $2 RETURNING id`
+
+	var deletedID string
+	err := r.db.QueryRowContext(ctx, query, postID, userID).Scan(&deletedID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.NotFound(fmt.Sprintf("reaction on post: %s", postID))
+		}
+		return fmt.Errorf("delete reaction: %w", err)
+	}
+
+	return nil
+}
+
+// GetByPostID fetches reactions to a post, newest first
+func (r *reactionRepository) GetByPostID(ctx context.Context, postID string, limit, offset int) ([]*model.Reaction, error) {
+	if limit <= 0 {
+		limit = 20 // Default limit
+	}
+
+	query := `
+		SELECT
+			r.id, r.post_id, r.user_id, r.type, r.created_at,
+			u.username, u.image_url
+		FROM
+			reaction r
+		JOIN
+			users u ON r.user_id = u.id
+		WHERE
+			r.post_id = $1
+		ORDER BY
+			r.created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, postID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("query post reactions: %w", err)
+	}
+	defer rows.Close()
+
+	var reactions []*model.Reaction
+
+	for rows.Next() {
+		var reaction model.Reaction
+		var typeStr string
+		var username string
+		var imageURL sql.NullString
+
+		err := rows.Scan(
+			&reaction.ID,
+			&reaction.PostID,
+			&reaction.UserID,
+			&typeStr,
+			&reaction.CreatedAt,
+			&username,
+			&imageURL,
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("scan reaction row: %w", err)
+		}
+
+		reaction.Type = model.ReactionType(typeStr)
+
+		// Set up basic user information
+		user := &model.User{
+			ID:       reaction.UserID,
+			Username: username,
+		}
+
+		if imageURL.Valid {
+			user.ImageURL = &imageURL.String
+		}
+
+		reaction.User = user
+
+		reactions = append(reactions, &reaction)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return reactions, nil
+}
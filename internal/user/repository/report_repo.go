@@ -0,0 +1,394 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/platform/database"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/google/uuid"
+)
+
+// ReportRepository defines methods to interact with reports and the moderation audit trail
+type ReportRepository interface {
+	Create(ctx context.Context, report *model.Report) error
+	GetByID(ctx context.Context, id string) (*model.Report, error)
+	GetByStatus(ctx context.Context, status model.ReportStatus, limit, offset int) ([]*model.Report, error)
+	GetCommentTarget(ctx context.Context, commentID string) (postID string, userID string, err error)
+	Resolve(ctx context.Context, action *model.ModerationAction) error
+	GetActions(ctx context.Context, limit, offset int) ([]*model.ModerationAction, error)
+}
+
+// reportColumns is the select list read by scanReport
+const reportColumns = `
+			r.id, r.reporter_id, r.target_type, r.target_id, r.target_user_id,
+			r.reason, r.details, r.status, r.created_at, r.resolved_at, r.resolved_by`
+
+type reportRepository struct {
+	db *database.DB
+}
+
+// NewReportRepository creates a new ReportRepository
+func NewReportRepository(db *database.DB) ReportRepository {
+	return &reportRepository{
+		db: db,
+	}
+}
+
+// Create files a report, failing if the reporter already has an open report
+// on the same target. Reports without a reporter are never duplicates.
+func (r *reportRepository) Create(ctx context.Context, report *model.Report) error {
+	// Generate ID if not provided
+	if report.ID == "" {
+		report.ID = uuid.New().String()
+	}
+
+	query := `
+		INSERT INTO report (
+			id, reporter_id, target_type, target_id, target_user_id,
+			reason, details, status, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, 'open', $8
+		)
+		ON CONFLICT (reporter_id, target_type, target_id) WHERE status = 'open' DO NOTHING
+		RETURNING id
+	`
+
+	now := time.Now().UTC()
+
+	var id string
+	err := r.db.QueryRowContext(ctx, query,
+		report.ID,
+		report.ReporterID,
+		report.TargetType,
+		report.TargetID,
+		report.TargetUserID,
+		report.Reason,
+		report.Details,
+		now,
+	).Scan(&id)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.Conflict("you have already reported this")
+		}
+		return fmt.Errorf("create report: %w", err)
+	}
+
+	report.Status = model.ReportStatusOpen
+	report.CreatedAt = now
+
+	return nil
+}
+
+// GetByID fetches a report by ID
+func (r *reportRepository) GetByID(ctx context.Context, id string) (*model.Report, error) {
+	query := `
+		SELECT ` + reportColumns + `
+		FROM
+			report r
+		WHERE
+			r.id = $1
+	`
+
+	report, err := scanReport(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.NotFound(fmt.Sprintf("report: %s", id))
+		}
+		return nil, fmt.Errorf("query report: %w", err)
+	}
+
+	return report, nil
+}
+
+// GetByStatus fetches reports in a status, oldest first so the queue is
+// worked through in the order reports came in
+func (r *reportRepository) GetByStatus(ctx context.Context, status model.ReportStatus, limit, offset int) ([]*model.Report, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	query := `
+		SELECT ` + reportColumns + `
+		FROM
+			report r
+		WHERE
+			r.status = $1
+		ORDER BY
+			r.created_at ASC, r.id ASC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("query reports: %w", err)
+	}
+	defer rows.Close()
+
+	var reports []*model.Report
+
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan report row: %w", err)
+		}
+
+		reports = append(reports, report)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return reports, nil
+}
+
+// GetCommentTarget fetches the post and author of a visible comment
+func (r *reportRepository) GetCommentTarget(ctx context.Context, commentID string) (string, string, error) {
+	query := `SELECT post_id, user_id FROM comment WHERE id = $1 AND hidden_at IS NULL`
+
+	var postID, userID string
+	err := r.db.QueryRowContext(ctx, query, commentID).Scan(&postID, &userID)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", "", apperrors.NotFound(fmt.Sprintf("comment: %s", commentID))
+		}
+		return "", "", fmt.Errorf("query comment: %w", err)
+	}
+
+	return postID, userID, nil
+}
+
+// Resolve applies a moderator's action to the target of action.ReportID and
+// closes every open report on that target, recording the action in the audit
+// trail. It all happens in one transaction, so a report is never resolved
+// twice and no action goes unrecorded. The target fields of the action are
+// filled in from the report.
+func (r *reportRepository) Resolve(ctx context.Context, action *model.ModerationAction) error {
+	if action.ReportID == nil {
+		return apperrors.BadRequest("report ID cannot be empty")
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Lock the report so concurrent moderators cannot both resolve it
+	var targetType, status string
+	err = tx.QueryRowContext(ctx, `SELECT target_type, target_id, target_user_id, status FROM report WHERE id = $1 FOR UPDATE`, *action.ReportID).
+		Scan(&targetType, &action.TargetID, &action.TargetUserID, &status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.NotFound(fmt.Sprintf("report: %s", *action.ReportID))
+		}
+		return fmt.Errorf("lock report: %w", err)
+	}
+
+	if model.ReportStatus(status) != model.ReportStatusOpen {
+		return apperrors.Conflict("report has already been resolved")
+	}
+
+	action.TargetType = model.ReportTargetType(targetType)
+	now := time.Now().UTC()
+
+	reportStatus := model.ReportStatusActioned
+	switch action.Action {
+	case model.ModerationActionHide:
+		var table string
+		switch action.TargetType {
+		case model.ReportTargetPost:
+			table = "post"
+		case model.ReportTargetComment:
+			table = "comment"
+		default:
+			return apperrors.BadRequest("only posts and comments can be hidden")
+		}
+
+		// Content deleted since it was reported has nothing left to hide
+		if _, err := tx.ExecContext(ctx, `UPDATE `+table+` SET hidden_at = $1 WHERE id = $2 AND hidden_at IS NULL`, now, action.TargetID); err != nil {
+			return fmt.Errorf("hide %s: %w", table, err)
+		}
+	case model.ModerationActionSuspend:
+		if _, err := tx.ExecContext(ctx, `UPDATE users SET is_active = FALSE, updated_at = $1 WHERE id = $2`, now, action.TargetUserID); err != nil {
+			return fmt.Errorf("suspend user: %w", err)
+		}
+	case model.ModerationActionDismiss:
+		reportStatus = model.ReportStatusDismissed
+	default:
+		return apperrors.BadRequest(fmt.Sprintf("unknown moderation action: %s", action.Action))
+	}
+
+	resolveQuery := `
+		UPDATE report
+		SET
+			status = $1,
+			resolved_at = $2,
+			resolved_by = $3
+		WHERE
+			target_type = $4 AND
+			target_id = $5 AND
+			status = 'open'
+	`
+
+	if _, err := tx.ExecContext(ctx, resolveQuery, reportStatus, now, action.ModeratorID, targetType, action.TargetID); err != nil {
+		return fmt.Errorf("resolve reports: %w", err)
+	}
+
+	if action.ID == "" {
+		action.ID = uuid.New().String()
+	}
+
+	auditQuery := `
+		INSERT INTO moderation_action (
+			id, moderator_id, action, target_type, target_id, target_user_id,
+			report_id, note, created_at
+		) VALUES (
+			$1, $2, $3, $4, $5, $6, $7, $8, $9
+		)
+	`
+
+	_, err = tx.ExecContext(ctx, auditQuery,
+		action.ID,
+		action.ModeratorID,
+		action.Action,
+		action.TargetType,
+		action.TargetID,
+		action.TargetUserID,
+		action.ReportID,
+		action.Note,
+		now,
+	)
+
+	if err != nil {
+		return fmt.Errorf("create moderation action: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	action.CreatedAt = now
+
+	return nil
+}
+
+// GetActions fetches the moderation audit trail, newest first
+func (r *reportRepository) GetActions(ctx context.Context, limit, offset int) ([]*model.ModerationAction, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	query := `
+		SELECT
+			id, moderator_id, action, target_type, target_id, target_user_id,
+			report_id, note, created_at
+		FROM
+			moderation_action
+		ORDER BY
+			created_at DESC, id DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("query moderation actions: %w", err)
+	}
+	defer rows.Close()
+
+	var actions []*model.ModerationAction
+
+	for rows.Next() {
+		var action model.ModerationAction
+		var actionStr, targetTypeStr string
+		var reportID, note sql.NullString
+
+		if err := rows.Scan(
+			&action.ID,
+			&action.ModeratorID,
+			&actionStr,
+			&targetTypeStr,
+			&action.TargetID,
+			&action.TargetUserID,
+			&reportID,
+			&note,
+			&action.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan moderation action row: %w", err)
+		}
+
+		if reportID.Valid {
+			action.ReportID = &reportID.String
+		}
+
+		if note.Valid {
+			action.Note = &note.String
+		}
+
+		action.Action = model.ModerationActionType(actionStr)
+		action.TargetType = model.ReportTargetType(targetTypeStr)
+
+		actions = append(actions, &action)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return actions, nil
+}
+
+// scanReport scans a report row
+func scanReport(row rowScanner) (*model.Report, error) {
+	var report model.Report
+	var targetTypeStr, reasonStr, statusStr string
+	var reporterID, details, resolvedBy sql.NullString
+	var resolvedAt sql.NullTime
+
+	err := row.Scan(
+		&report.ID,
+		&reporterID,
+		&targetTypeStr,
+		&report.TargetID,
+		&report.TargetUserID,
+		&reasonStr,
+		&details,
+		&statusStr,
+		&report.CreatedAt,
+		&resolvedAt,
+		&resolvedBy,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if reporterID.Valid {
+		report.ReporterID = &reporterID.String
+	}
+
+	if details.Valid {
+		report.Details = &details.String
+	}
+
+	if resolvedAt.Valid {
+		report.ResolvedAt = &resolvedAt.Time
+	}
+
+	if resolvedBy.Valid {
+		report.ResolvedBy = &resolvedBy.String
+	}
+
+	report.TargetType = model.ReportTargetType(targetTypeStr)
+	report.Reason = model.ReportReason(reasonStr)
+	report.Status = model.ReportStatus(statusStr)
+
+	return &report, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/PeterM45/perfolio-api/internal/common/model"
 	"github.com/PeterM45/perfolio-api/internal/platform/database"
 	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/PeterM45/perfolio-api/pkg/pagination"
 )
 
 // UserRepository defines methods to interact with user data
@@ -19,17 +20,39 @@ type UserRepository interface {
 	GetByEmail(ctx context.Context, email string) (*model.User, error)
 	Create(ctx context.Context, user *model.User) error
 	Update(ctx context.Context, id string, updates map[string]interface{}) error
-	Search(ctx context.Context, query string, limit int) ([]*model.User, error)
+	Search(ctx context.Context, query string, viewerID string, limit int) ([]*model.User, error)
 
 	AddFollow(ctx context.Context, followerID, followingID string) error
 	RemoveFollow(ctx context.Context, followerID, followingID string) error
 	IsFollowing(ctx context.Context, followerID, followingID string) (bool, error)
 	GetFollowerCount(ctx context.Context, userID string) (int, error)
 	GetFollowingCount(ctx context.Context, userID string) (int, error)
-	GetFollowers(ctx context.Context, userID string, limit, offset int) ([]*model.User, error)
-	GetFollowing(ctx context.Context, userID string, limit, offset int) ([]*model.User, error)
+	GetFollowers(ctx context.Context, userID string, viewerID string, cursor *pagination.Cursor, limit, offset int) ([]*model.User, error)
+	GetFollowing(ctx context.Context, userID string, viewerID string, cursor *pagination.Cursor, limit, offset int) ([]*model.User, error)
+
+	Block(ctx context.Context, blockerID, blockedID string) error
+	Unblock(ctx context.Context, blockerID, blockedID string) error
+	IsBlocked(ctx context.Context, userID, otherID string) (bool, error)
+	GetBlocked(ctx context.Context, userID string, limit, offset int) ([]*model.User, error)
+	Mute(ctx context.Context, muterID, mutedID string) error
+	Unmute(ctx context.Context, muterID, mutedID string) error
+	GetMuted(ctx context.Context, userID string, limit, offset int) ([]*model.User, error)
 }
 
+// notBlocked returns a condition that holds when there is no block in either
+// direction between the user in column and the viewer bound to param
+func notBlocked(param string, column string) string {
+	return `NOT EXISTS (
+				SELECT 1 FROM blocks b
+				WHERE (b.blocker_id = ` + param + ` AND b.blocked_id = ` + column + `) OR
+					(b.blocker_id = ` + column + ` AND b.blocked_id = ` + param + `)
+			)`
+}
+
+// timelineBackfillSize is the number of recent posts put on a user's timeline
+// when they follow someone
+const timelineBackfillSize = 100
+
 type userRepository struct {
 	db *database.DB
 }
@@ -310,7 +333,7 @@ func (r *userRepository) Update(ctx context.Context, id string, updates map[stri
 }
 
 // Search searches users by query
-func (r *userRepository) Search(ctx context.Context, query string, limit int) ([]*model.User, error) {
+func (r *userRepository) Search(ctx context.Context, query string, viewerID string, limit int) ([]*model.User, error) {
 	if limit <= 0 {
 		limit = 10 // Default limit
 	}
@@ -322,14 +345,15 @@ func (r *userRepository) Search(ctx context.Context, query string, limit int) ([
 		FROM 
 			"users"
 		WHERE 
-			username ILIKE $1 OR
+			(username ILIKE $1 OR
 			first_name ILIKE $1 OR
-			last_name ILIKE $1
-		LIMIT $2
+			last_name ILIKE $1) AND
+			` + notBlocked("$2", "id") + `
+		LIMIT $3
 	`
 
 	searchPattern := "%" + query + "%"
-	rows, err := r.db.QueryContext(ctx, sqlQuery, searchPattern, limit)
+	rows, err := r.db.QueryContext(ctx, sqlQuery, searchPattern, viewerID, limit)
 	if err != nil {
 		return nil, fmt.Errorf("search users: %w", err)
 	}
@@ -393,7 +417,8 @@ func (r *userRepository) Search(ctx context.Context, query string, limit int) ([
 	return users, nil
 }
 
-// AddFollow creates a follow relationship
+// AddFollow creates a follow relationship and puts the followed user's recent
+// posts on the follower's timeline
 func (r *userRepository) AddFollow(ctx context.Context, followerID, followingID string) error {
 	// Check if users exist first
 	for _, id := range []string{followerID, followingID} {
@@ -415,24 +440,67 @@ func (r *userRepository) AddFollow(ctx context.Context, followerID, followingID
 		return nil // Already following, just return success
 	}
 
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	// Add follow
-	query := `INSERT INTO follows (follower_id, following_id, created_at) VALUES ($1, $2, $3)`
-	_, err = r.db.ExecContext(ctx, query, followerID, followingID, time.Now().UTC())
+	query := `INSERT INTO follows (follower_id, following_id, created_at) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+	_, err = tx.ExecContext(ctx, query, followerID, followingID, time.Now().UTC())
 	if err != nil {
 		return fmt.Errorf("add follow: %w", err)
 	}
 
+	// Backfill the follower's timeline with the latest posts of the account
+	// they followed, so it does not start empty
+	query = `
+		INSERT INTO timeline (user_id, post_id, author_id, created_at)
+		SELECT $1, p.id, p.user_id, p.created_at
+		FROM post p
+		WHERE p.user_id = $2 AND p.status = 'published' AND p.deleted_at IS NULL
+		ORDER BY p.created_at DESC
+		LIMIT $3
+		ON CONFLICT DO NOTHING
+	`
+	_, err = tx.ExecContext(ctx, query, followerID, followingID, timelineBackfillSize)
+	if err != nil {
+		return fmt.Errorf("backfill timeline: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
 	return nil
 }
 
-// RemoveFollow removes a follow relationship
+// RemoveFollow removes a follow relationship along with the posts of the
+// unfollowed account from the follower's timeline
 func (r *userRepository) RemoveFollow(ctx context.Context, followerID, followingID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
 	query := `DELETE FROM follows WHERE follower_id = $1 AND following_id = $2`
-	_, err := r.db.ExecContext(ctx, query, followerID, followingID)
+	_, err = tx.ExecContext(ctx, query, followerID, followingID)
 	if err != nil {
 		return fmt.Errorf("remove follow: %w", err)
 	}
 
+	query = `DELETE FROM timeline WHERE user_id = $1 AND author_id = $2`
+	_, err = tx.ExecContext(ctx, query, followerID, followingID)
+	if err != nil {
+		return fmt.Errorf("remove timeline posts: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
 	return nil
 }
 
@@ -475,8 +543,10 @@ func (r *userRepository) GetFollowingCount(ctx context.Context, userID string) (
 	return count, nil
 }
 
-// GetFollowers returns a list of users following the given user
-func (r *userRepository) GetFollowers(ctx context.Context, userID string, limit, offset int) ([]*model.User, error) {
+// GetFollowers returns a list of users following the given user, most recent
+// followers first. Users in a block with the viewer are left out. Pages
+// continue after the cursor when one is given, and skip offset rows otherwise.
+func (r *userRepository) GetFollowers(ctx context.Context, userID string, viewerID string, cursor *pagination.Cursor, limit, offset int) ([]*model.User, error) {
 	if limit <= 0 {
 		limit = 10 // Default limit
 	}
@@ -484,17 +554,31 @@ func (r *userRepository) GetFollowers(ctx context.Context, userID string, limit,
 	query := `
 		SELECT 
 			u.id, u.email, u.username, u.first_name, u.last_name, u.bio, 
-			u.auth_provider, u.image_url, u.is_active, u.created_at, u.updated_at
+			u.auth_provider, u.image_url, u.is_active, u.created_at, u.updated_at,
+			f.created_at
 		FROM 
 			"users" u
 		JOIN 
 			follows f ON u.id = f.follower_id
 		WHERE 
-			f.following_id = $1
-		LIMIT $2 OFFSET $3
-	`
+			f.following_id = $1 AND
+			` + notBlocked("$2", "u.id")
+	params := []interface{}{userID, viewerID}
 
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	if cursor != nil {
+		params = append(params, cursor.CreatedAt, cursor.ID)
+		query += fmt.Sprintf(" AND (f.created_at, u.id) < ($%d, $%d)", len(params)-1, len(params))
+	}
+
+	params = append(params, limit)
+	query += fmt.Sprintf(" ORDER BY f.created_at DESC, u.id DESC LIMIT $%d", len(params))
+
+	if cursor == nil {
+		params = append(params, offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(params))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, params...)
 	if err != nil {
 		return nil, fmt.Errorf("get followers: %w", err)
 	}
@@ -506,6 +590,7 @@ func (r *userRepository) GetFollowers(ctx context.Context, userID string, limit,
 		var user model.User
 		var firstName, lastName, bio, imageURL, email sql.NullString
 		var updatedAt sql.NullTime
+		var followedAt time.Time
 		var authProviderStr string
 
 		err := rows.Scan(
@@ -520,6 +605,7 @@ func (r *userRepository) GetFollowers(ctx context.Context, userID string, limit,
 			&user.IsActive,
 			&user.CreatedAt,
 			&updatedAt,
+			&followedAt,
 		)
 
 		if err != nil {
@@ -547,6 +633,7 @@ func (r *userRepository) GetFollowers(ctx context.Context, userID string, limit,
 		}
 
 		user.AuthProvider = model.AuthProvider(authProviderStr)
+		user.FollowedAt = &followedAt
 
 		users = append(users, &user)
 	}
@@ -558,8 +645,11 @@ func (r *userRepository) GetFollowers(ctx context.Context, userID string, limit,
 	return users, nil
 }
 
-// GetFollowing returns a list of users the given user is following
-func (r *userRepository) GetFollowing(ctx context.Context, userID string, limit, offset int) ([]*model.User, error) {
+// GetFollowing returns a list of users the given user is following, most
+// recently followed first. Users in a block with the viewer are left out.
+// Pages continue after the cursor when one is given, and skip offset rows
+// otherwise.
+func (r *userRepository) GetFollowing(ctx context.Context, userID string, viewerID string, cursor *pagination.Cursor, limit, offset int) ([]*model.User, error) {
 	if limit <= 0 {
 		limit = 10 // Default limit
 	}
@@ -567,17 +657,31 @@ func (r *userRepository) GetFollowing(ctx context.Context, userID string, limit,
 	query := `
 		SELECT 
 			u.id, u.email, u.username, u.first_name, u.last_name, u.bio, 
-			u.auth_provider, u.image_url, u.is_active, u.created_at, u.updated_at
+			u.auth_provider, u.image_url, u.is_active, u.created_at, u.updated_at,
+			f.created_at
 		FROM 
 			"users" u
 		JOIN 
 			follows f ON u.id = f.following_id
 		WHERE 
-			f.follower_id = $1
-		LIMIT $2 OFFSET $3
-	`
+			f.follower_id = $1 AND
+			` + notBlocked("$2", "u.id")
+	params := []interface{}{userID, viewerID}
+
+	if cursor != nil {
+		params = append(params, cursor.CreatedAt, cursor.ID)
+		query += fmt.Sprintf(" AND (f.created_at, u.id) < ($%d, $%d)", len(params)-1, len(params))
+	}
+
+	params = append(params, limit)
+	query += fmt.Sprintf(" ORDER BY f.created_at DESC, u.id DESC LIMIT $%d", len(params))
 
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
+	if cursor == nil {
+		params = append(params, offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(params))
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, params...)
 	if err != nil {
 		return nil, fmt.Errorf("get following: %w", err)
 	}
@@ -589,6 +693,7 @@ func (r *userRepository) GetFollowing(ctx context.Context, userID string, limit,
 		var user model.User
 		var firstName, lastName, bio, imageURL, email sql.NullString
 		var updatedAt sql.NullTime
+		var followedAt time.Time
 		var authProviderStr string
 
 		err := rows.Scan(
@@ -603,6 +708,7 @@ func (r *userRepository) GetFollowing(ctx context.Context, userID string, limit,
 			&user.IsActive,
 			&user.CreatedAt,
 			&updatedAt,
+			&followedAt,
 		)
 
 		if err != nil {
@@ -629,6 +735,215 @@ func (r *userRepository) GetFollowing(ctx context.Context, userID string, limit,
 			user.UpdatedAt = &updatedAt.Time
 		}
 
+		user.AuthProvider = model.AuthProvider(authProviderStr)
+		user.FollowedAt = &followedAt
+
+		users = append(users, &user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration: %w", err)
+	}
+
+	return users, nil
+}
+
+// Block records that a user blocked another. Follows between the two are
+// removed in both directions, along with their posts on each other's
+// timelines.
+func (r *userRepository) Block(ctx context.Context, blockerID, blockedID string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO blocks (blocker_id, blocked_id, created_at) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+	if _, err := tx.ExecContext(ctx, query, blockerID, blockedID, time.Now().UTC()); err != nil {
+		return fmt.Errorf("add block: %w", err)
+	}
+
+	query = `
+		DELETE FROM follows
+		WHERE (follower_id = $1 AND following_id = $2) OR (follower_id = $2 AND following_id = $1)
+	`
+	if _, err := tx.ExecContext(ctx, query, blockerID, blockedID); err != nil {
+		return fmt.Errorf("remove follows: %w", err)
+	}
+
+	query = `
+		DELETE FROM timeline
+		WHERE (user_id = $1 AND author_id = $2) OR (user_id = $2 AND author_id = $1)
+	`
+	if _, err := tx.ExecContext(ctx, query, blockerID, blockedID); err != nil {
+		return fmt.Errorf("remove timeline posts: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// Unblock removes a block. Follows removed by the block are not restored.
+func (r *userRepository) Unblock(ctx context.Context, blockerID, blockedID string) error {
+	query := `DELETE FROM blocks WHERE blocker_id = $1 AND blocked_id = $2`
+	if _, err := r.db.ExecContext(ctx, query, blockerID, blockedID); err != nil {
+		return fmt.Errorf("remove block: %w", err)
+	}
+
+	return nil
+}
+
+// IsBlocked checks whether either user has blocked the other
+func (r *userRepository) IsBlocked(ctx context.Context, userID, otherID string) (bool, error) {
+	var blocked bool
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM blocks
+			WHERE (blocker_id = $1 AND blocked_id = $2) OR (blocker_id = $2 AND blocked_id = $1)
+		)
+	`
+	if err := r.db.QueryRowContext(ctx, query, userID, otherID).Scan(&blocked); err != nil {
+		return false, fmt.Errorf("check block: %w", err)
+	}
+
+	return blocked, nil
+}
+
+// GetBlocked returns the users a user has blocked, most recent first
+func (r *userRepository) GetBlocked(ctx context.Context, userID string, limit, offset int) ([]*model.User, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	query := `
+		SELECT 
+			u.id, u.email, u.username, u.first_name, u.last_name, u.bio, 
+			u.auth_provider, u.image_url, u.is_active, u.created_at, u.updated_at
+		FROM 
+			"users" u
+		JOIN 
+			blocks b ON u.id = b.blocked_id
+		WHERE 
+			b.blocker_id = $1
+		ORDER BY 
+			b.created_at DESC, u.id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	users, err := r.queryUsers(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get blocked users: %w", err)
+	}
+
+	return users, nil
+}
+
+// Mute records that a user muted another
+func (r *userRepository) Mute(ctx context.Context, muterID, mutedID string) error {
+	query := `INSERT INTO mutes (muter_id, muted_id, created_at) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`
+	if _, err := r.db.ExecContext(ctx, query, muterID, mutedID, time.Now().UTC()); err != nil {
+		return fmt.Errorf("add mute: %w", err)
+	}
+
+	return nil
+}
+
+// Unmute removes a mute
+func (r *userRepository) Unmute(ctx context.Context, muterID, mutedID string) error {
+	query := `DELETE FROM mutes WHERE muter_id = $1 AND muted_id = $2`
+	if _, err := r.db.ExecContext(ctx, query, muterID, mutedID); err != nil {
+		return fmt.Errorf("remove mute: %w", err)
+	}
+
+	return nil
+}
+
+// GetMuted returns the users a user has muted, most recent first
+func (r *userRepository) GetMuted(ctx context.Context, userID string, limit, offset int) ([]*model.User, error) {
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	query := `
+		SELECT 
+			u.id, u.email, u.username, u.first_name, u.last_name, u.bio, 
+			u.auth_provider, u.image_url, u.is_active, u.created_at, u.updated_at
+		FROM 
+			"users" u
+		JOIN 
+			mutes m ON u.id = m.muted_id
+		WHERE 
+			m.muter_id = $1
+		ORDER BY 
+			m.created_at DESC, u.id DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	users, err := r.queryUsers(ctx, query, userID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("get muted users: %w", err)
+	}
+
+	return users, nil
+}
+
+// queryUsers runs a query selecting user columns and scans every row
+func (r *userRepository) queryUsers(ctx context.Context, query string, params ...interface{}) ([]*model.User, error) {
+	rows, err := r.db.QueryContext(ctx, query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*model.User
+
+	for rows.Next() {
+		var user model.User
+		var firstName, lastName, bio, imageURL, email sql.NullString
+		var updatedAt sql.NullTime
+		var authProviderStr string
+
+		err := rows.Scan(
+			&user.ID,
+			&email,
+			&user.Username,
+			&firstName,
+			&lastName,
+			&bio,
+			&authProviderStr,
+			&imageURL,
+			&user.IsActive,
+			&user.CreatedAt,
+			&updatedAt,
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("scan user row: %w", err)
+		}
+
+		// Handle null fields
+		if email.Valid {
+			user.Email = email.String
+		}
+		if firstName.Valid {
+			user.FirstName = &firstName.String
+		}
+		if lastName.Valid {
+			user.LastName = &lastName.String
+		}
+		if bio.Valid {
+			user.Bio = &bio.String
+		}
+		if imageURL.Valid {
+			user.ImageURL = &imageURL.String
+		}
+		if updatedAt.Valid {
+			user.UpdatedAt = &updatedAt.Time
+		}
+
 		user.AuthProvider = model.AuthProvider(authProviderStr)
 
 		users = append(users, &user)
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+)
+
+// GetProfile retrieves a user by ID as seen by the viewer. Users in a block
+// with the viewer are reported missing.
+func (s *userService) GetProfile(ctx context.Context, id string, viewerID string) (*model.User, error) {
+	user, err := s.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkNotBlocked(ctx, user.ID, viewerID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// GetProfileByUsername retrieves a user by username as seen by the viewer.
+// Users in a block with the viewer are reported missing.
+func (s *userService) GetProfileByUsername(ctx context.Context, username string, viewerID string) (*model.User, error) {
+	user, err := s.GetUserByUsername(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkNotBlocked(ctx, user.ID, viewerID); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// BlockUser blocks a user. Follows between the two users are removed in both
+// directions and neither can follow the other until the block is lifted.
+func (s *userService) BlockUser(ctx context.Context, blockerID, blockedID string) error {
+	if err := s.checkRelationTarget(ctx, blockerID, blockedID, "block"); err != nil {
+		return err
+	}
+
+	if err := s.repo.Block(ctx, blockerID, blockedID); err != nil {
+		return err
+	}
+
+	// Invalidate caches
+	s.cache.Delete(blockCacheKey(blockerID, blockedID))
+	for _, pair := range [][2]string{{blockerID, blockedID}, {blockedID, blockerID}} {
+		s.cache.Delete(fmt.Sprintf("follows:%s:%s", pair[0], pair[1]))
+		s.cache.Delete(fmt.Sprintf("follower_count:%s", pair[0]))
+		s.cache.Delete(fmt.Sprintf("following_count:%s", pair[0]))
+	}
+
+	// Each user's posts left the other's timeline and profile view
+	setTimelineVersion(s.cache, blockerID, newTimelineVersion())
+	setTimelineVersion(s.cache, blockedID, newTimelineVersion())
+
+	return nil
+}
+
+// UnblockUser lifts a block. Follows removed by the block are not restored.
+func (s *userService) UnblockUser(ctx context.Context, blockerID, blockedID string) error {
+	if err := s.checkRelationTarget(ctx, blockerID, blockedID, "unblock"); err != nil {
+		return err
+	}
+
+	if err := s.repo.Unblock(ctx, blockerID, blockedID); err != nil {
+		return err
+	}
+
+	s.cache.Delete(blockCacheKey(blockerID, blockedID))
+	setTimelineVersion(s.cache, blockerID, newTimelineVersion())
+	setTimelineVersion(s.cache, blockedID, newTimelineVersion())
+
+	return nil
+}
+
+// IsBlocked checks whether either user has blocked the other
+func (s *userService) IsBlocked(ctx context.Context, userID, otherID string) (bool, error) {
+	if userID == "" || otherID == "" || userID == otherID {
+		return false, nil
+	}
+
+	// Check cache first
+	cacheKey := blockCacheKey(userID, otherID)
+	if cachedResult, found := s.cache.Get(cacheKey); found {
+		return cachedResult.(bool), nil
+	}
+
+	blocked, err := s.repo.IsBlocked(ctx, userID, otherID)
+	if err != nil {
+		return false, err
+	}
+
+	// Cache the result
+	s.cache.Set(cacheKey, blocked, 5*time.Minute)
+
+	return blocked, nil
+}
+
+// GetBlockedUsers lists the users a user has blocked, most recent first
+func (s *userService) GetBlockedUsers(ctx context.Context, userID string, limit, offset int) ([]*model.User, error) {
+	if _, err := s.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetBlocked(ctx, userID, limit, offset)
+}
+
+// MuteUser mutes a user. Their posts no longer appear in the muter's feed,
+// but stay visible everywhere else.
+func (s *userService) MuteUser(ctx context.Context, muterID, mutedID string) error {
+	if err := s.checkRelationTarget(ctx, muterID, mutedID, "mute"); err != nil {
+		return err
+	}
+
+	if err := s.repo.Mute(ctx, muterID, mutedID); err != nil {
+		return err
+	}
+
+	// The muter's feed lost the muted user's posts
+	setTimelineVersion(s.cache, muterID, newTimelineVersion())
+
+	return nil
+}
+
+// UnmuteUser lifts a mute
+func (s *userService) UnmuteUser(ctx context.Context, muterID, mutedID string) error {
+	if err := s.checkRelationTarget(ctx, muterID, mutedID, "unmute"); err != nil {
+		return err
+	}
+
+	if err := s.repo.Unmute(ctx, muterID, mutedID); err != nil {
+		return err
+	}
+
+	setTimelineVersion(s.cache, muterID, newTimelineVersion())
+
+	return nil
+}
+
+// GetMutedUsers lists the users a user has muted, most recent first
+func (s *userService) GetMutedUsers(ctx context.Context, userID string, limit, offset int) ([]*model.User, error) {
+	if _, err := s.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetMuted(ctx, userID, limit, offset)
+}
+
+// checkRelationTarget verifies that a user may block or mute the target
+func (s *userService) checkRelationTarget(ctx context.Context, userID, targetID string, action string) error {
+	if targetID == "" {
+		return apperrors.BadRequest("user ID cannot be empty")
+	}
+
+	if userID == targetID {
+		return apperrors.BadRequest(fmt.Sprintf("cannot %s yourself", action))
+	}
+
+	if _, err := s.GetUserByID(ctx, userID); err != nil {
+		return err
+	}
+
+	_, err := s.GetUserByID(ctx, targetID)
+	return err
+}
+
+// checkNotBlocked returns a not found error when the user and the viewer are
+// in a block, so blocked users are indistinguishable from missing ones
+func (s *userService) checkNotBlocked(ctx context.Context, userID, viewerID string) error {
+	blocked, err := s.IsBlocked(ctx, userID, viewerID)
+	if err != nil {
+		return err
+	}
+
+	if blocked {
+		return apperrors.NotFound(fmt.Sprintf("user: %s", userID))
+	}
+
+	return nil
+}
+
+// blockCacheKey returns the cache key of the block state between two users,
+// which is the same in either order
+func blockCacheKey(userID, otherID string) string {
+	if userID > otherID {
+		userID, otherID = otherID, userID
+	}
+	return fmt.Sprintf("blocked:%s:%s", userID, otherID)
+}
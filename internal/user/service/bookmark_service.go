@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/platform/cache"
+	"github.com/PeterM45/perfolio-api/internal/user/interfaces"
+	"github.com/PeterM45/perfolio-api/internal/user/repository"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/PeterM45/perfolio-api/pkg/logger"
+)
+
+type bookmarkService struct {
+	repo        repository.BookmarkRepository
+	postService interfaces.PostService
+	cache       cache.Cache
+	logger      logger.Logger
+}
+
+// NewBookmarkService creates a new BookmarkService
+func NewBookmarkService(
+	repo repository.BookmarkRepository,
+	postService interfaces.PostService,
+	cache cache.Cache,
+	logger logger.Logger,
+) interfaces.BookmarkService {
+	return &bookmarkService{
+		repo:        repo,
+		postService: postService,
+		cache:       cache,
+		logger:      logger,
+	}
+}
+
+// AddBookmark saves a post the user can see to their bookmarks
+func (s *bookmarkService) AddBookmark(ctx context.Context, postID string, userID string) error {
+	if postID == "" {
+		return apperrors.BadRequest("post ID cannot be empty")
+	}
+
+	if _, err := s.postService.GetPostByID(ctx, postID, userID); err != nil {
+		return err
+	}
+
+	return s.repo.Create(ctx, userID, postID)
+}
+
+// RemoveBookmark removes a post from the user's bookmarks
+func (s *bookmarkService) RemoveBookmark(ctx context.Context, postID string, userID string) error {
+	if postID == "" {
+		return apperrors.BadRequest("post ID cannot be empty")
+	}
+
+	return s.repo.Delete(ctx, userID, postID)
+}
+
+// GetBookmarks lists the user's bookmarked posts, most recently saved first
+func (s *bookmarkService) GetBookmarks(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error) {
+	if userID == "" {
+		return nil, apperrors.BadRequest("user ID cannot be empty")
+	}
+
+	postIDs, err := s.repo.GetPostIDs(ctx, userID, clampPageSize(limit), offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.postService.GetPostsByIDs(ctx, postIDs, userID)
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/platform/cache"
+	"github.com/PeterM45/perfolio-api/internal/user/interfaces"
+	"github.com/PeterM45/perfolio-api/internal/user/repository"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/PeterM45/perfolio-api/pkg/logger"
+	"github.com/PeterM45/perfolio-api/pkg/pagination"
+	"github.com/PeterM45/perfolio-api/pkg/validator"
+	"github.com/google/uuid"
+)
+
+const maxCommentPageSize = 100
+
+type commentService struct {
+	repo        repository.CommentRepository
+	postService interfaces.PostService
+	cache       cache.Cache
+	validator   validator.Validator
+	logger      logger.Logger
+}
+
+// NewCommentService creates a new CommentService
+func NewCommentService(
+	repo repository.CommentRepository,
+	postService interfaces.PostService,
+	cache cache.Cache,
+	logger logger.Logger,
+) interfaces.CommentService {
+	return &commentService{
+		repo:        repo,
+		postService: postService,
+		cache:       cache,
+		validator:   validator.NewValidator(),
+		logger:      logger,
+	}
+}
+
+// CreateComment adds a comment to a post, or a reply to another comment
+func (s *commentService) CreateComment(ctx context.Context, postID string, userID string, req *model.CreateCommentRequest) (*model.Comment, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, apperrors.BadRequest(err.Error())
+	}
+
+	if _, err := s.getVisiblePost(ctx, postID, userID); err != nil {
+		return nil, err
+	}
+
+	comment := &model.Comment{
+		ID:      uuid.New().String(),
+		PostID:  postID,
+		UserID:  userID,
+		Content: req.Content,
+	}
+
+	// Replies inherit their position in the thread from the parent
+	if req.ParentID != nil && *req.ParentID != "" {
+		parent, err := s.getComment(ctx, postID, *req.ParentID)
+		if err != nil {
+			return nil, err
+		}
+
+		if parent.Depth+1 >= model.MaxCommentDepth {
+			return nil, apperrors.BadRequest(fmt.Sprintf("replies can only be nested %d levels deep", model.MaxCommentDepth))
+		}
+
+		comment.ParentID = &parent.ID
+		comment.Depth = parent.Depth + 1
+	}
+
+	if err := s.repo.Create(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// UpdateComment edits a comment's content. Only the author may edit.
+func (s *commentService) UpdateComment(ctx context.Context, postID string, commentID string, userID string, req *model.UpdateCommentRequest) (*model.Comment, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, apperrors.BadRequest(err.Error())
+	}
+
+	if _, err := s.getVisiblePost(ctx, postID, userID); err != nil {
+		return nil, err
+	}
+
+	comment, err := s.getComment(ctx, postID, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if user owns the comment
+	if comment.UserID != userID {
+		return nil, apperrors.Forbidden("you don't have permission to update this comment")
+	}
+
+	comment.Content = req.Content
+
+	if err := s.repo.Update(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// DeleteComment deletes a comment and its replies. The comment author and the
+// owner of the post may delete it.
+func (s *commentService) DeleteComment(ctx context.Context, postID string, commentID string, userID string) error {
+	post, err := s.getVisiblePost(ctx, postID, userID)
+	if err != nil {
+		return err
+	}
+
+	comment, err := s.getComment(ctx, postID, commentID)
+	if err != nil {
+		return err
+	}
+
+	if comment.UserID != userID && post.UserID != userID {
+		return apperrors.Forbidden("you don't have permission to delete this comment")
+	}
+
+	return s.repo.Delete(ctx, commentID)
+}
+
+// GetPostComments lists the top-level comments on a post
+func (s *commentService) GetPostComments(ctx context.Context, postID string, viewerID string, cursor string, limit int) ([]*model.Comment, string, error) {
+	if _, err := s.getVisiblePost(ctx, postID, viewerID); err != nil {
+		return nil, "", err
+	}
+
+	return s.getThread(ctx, postID, nil, viewerID, cursor, limit)
+}
+
+// GetCommentReplies lists the direct replies to a comment
+func (s *commentService) GetCommentReplies(ctx context.Context, postID string, commentID string, viewerID string, cursor string, limit int) ([]*model.Comment, string, error) {
+	if _, err := s.getVisiblePost(ctx, postID, viewerID); err != nil {
+		return nil, "", err
+	}
+
+	if _, err := s.getComment(ctx, postID, commentID); err != nil {
+		return nil, "", err
+	}
+
+	return s.getThread(ctx, postID, &commentID, viewerID, cursor, limit)
+}
+
+// getThread loads one page of a thread level and computes the next cursor
+func (s *commentService) getThread(ctx context.Context, postID string, parentID *string, viewerID string, cursorToken string, limit int) ([]*model.Comment, string, error) {
+	cursor, err := pagination.Decode(cursorToken)
+	if err != nil {
+		return nil, "", apperrors.BadRequest(err.Error())
+	}
+
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > maxCommentPageSize {
+		limit = maxCommentPageSize
+	}
+
+	// Fetch one extra row to know whether another page exists
+	comments, err := s.repo.GetThread(ctx, postID, parentID, viewerID, cursor, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(comments) > limit {
+		comments = comments[:limit]
+		last := comments[len(comments)-1]
+		nextCursor = pagination.NewCursor(last.CreatedAt, last.ID).Encode()
+	}
+
+	return comments, nextCursor, nil
+}
+
+// getVisiblePost loads the parent post, which is reported missing when the viewer may not see it
+func (s *commentService) getVisiblePost(ctx context.Context, postID string, viewerID string) (*model.Post, error) {
+	return s.postService.GetPostByID(ctx, postID, viewerID)
+}
+
+// getComment loads a comment and verifies it belongs to the given post
+func (s *commentService) getComment(ctx context.Context, postID string, commentID string) (*model.Comment, error) {
+	if commentID == "" {
+		return nil, apperrors.BadRequest("comment ID cannot be empty")
+	}
+
+	comment, err := s.repo.GetByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if comment.PostID != postID {
+		return nil, apperrors.NotFound(fmt.Sprintf("comment: %s", commentID))
+	}
+
+	return comment, nil
+}
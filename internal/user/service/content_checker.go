@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/platform/moderation"
+	"github.com/PeterM45/perfolio-api/internal/user/repository"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/PeterM45/perfolio-api/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// ContentChecker runs the content filters over user-submitted text before it
+// is stored. Rejected content never reaches the database. Flagged content is
+// stored and then filed in the moderation queue.
+type ContentChecker struct {
+	filter  moderation.ContentFilter
+	reports repository.ReportRepository
+	logger  logger.Logger
+}
+
+// NewContentChecker creates a new ContentChecker
+func NewContentChecker(filter moderation.ContentFilter, reports repository.ReportRepository, logger logger.Logger) *ContentChecker {
+	return &ContentChecker{
+		filter:  filter,
+		reports: reports,
+		logger:  logger,
+	}
+}
+
+// Check runs the filters over the content. Rejected content is returned as a
+// bad request with the filters' reasons as its details.
+func (c *ContentChecker) Check(ctx context.Context, content *moderation.Content) (moderation.Verdict, error) {
+	verdict, err := c.filter.Check(ctx, content)
+	if err != nil {
+		return moderation.Verdict{}, err
+	}
+
+	if verdict.Action == moderation.Reject {
+		c.logger.Info().
+			Str("user_id", content.UserID).
+			Str("kind", string(content.Kind)).
+			Strs("reasons", verdict.Reasons).
+			Msg("Rejected content")
+		return verdict, apperrors.BadRequest("content was rejected by the content filter").WithDetails(verdict.Reasons...)
+	}
+
+	return verdict, nil
+}
+
+// Record tells the filters that checked content was stored. The content is
+// already saved, so failures are logged rather than returned.
+func (c *ContentChecker) Record(ctx context.Context, content *moderation.Content) {
+	recorder, ok := c.filter.(moderation.ContentRecorder)
+	if !ok {
+		return
+	}
+
+	if err := recorder.Record(ctx, content); err != nil {
+		c.logger.Error().Err(err).
+			Str("user_id", content.UserID).
+			Str("kind", string(content.Kind)).
+			Msg("Failed to record content")
+	}
+}
+
+// FlagForReview files a report for stored content the filters flagged. The
+// content is already saved, so failures are logged rather than returned.
+func (c *ContentChecker) FlagForReview(ctx context.Context, verdict moderation.Verdict, targetType model.ReportTargetType, targetID string, userID string) {
+	if verdict.Action != moderation.Flag {
+		return
+	}
+
+	details := strings.Join(verdict.Reasons, "; ")
+	report := &model.Report{
+		ID:           uuid.New().String(),
+		TargetType:   targetType,
+		TargetID:     targetID,
+		TargetUserID: userID,
+		Reason:       model.ReportReasonContentFilter,
+		Details:      &details,
+	}
+
+	if err := c.reports.Create(ctx, report); err != nil {
+		c.logger.Error().Err(err).
+			Str("target_type", string(targetType)).
+			Str("target_id", targetID).
+			Msg("Failed to flag content for review")
+		return
+	}
+
+	c.logger.Info().
+		Str("report_id", report.ID).
+		Str("target_type", string(targetType)).
+		Str("target_id", targetID).
+		Msg("Flagged content for review")
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/google/uuid"
+)
+
+// CreateDraft starts a new draft. Drafts are only visible to their author.
+func (s *postService) CreateDraft(ctx context.Context, userID string, req *model.CreateDraftRequest) (*model.Post, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, apperrors.BadRequest(err.Error())
+	}
+
+	// Verify user exists
+	if _, err := s.userService.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	post := &model.Post{
+		ID:         uuid.New().String(),
+		UserID:     userID,
+		Content:    req.Content,
+		EmbedURLs:  req.EmbedURLs,
+		Hashtags:   req.Hashtags,
+		Visibility: model.VisibilityPublic, // Default visibility
+		Status:     model.PostStatusDraft,
+	}
+
+	if err := s.repo.Create(ctx, post); err != nil {
+		return nil, err
+	}
+
+	return post, nil
+}
+
+// UpdateDraft autosaves a draft. Fields omitted from the request keep their value.
+func (s *postService) UpdateDraft(ctx context.Context, id string, userID string, req *model.UpdateDraftRequest) (*model.Post, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, apperrors.BadRequest(err.Error())
+	}
+
+	post, err := s.getDraft(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Content != nil {
+		post.Content = *req.Content
+	}
+	if req.EmbedURLs != nil {
+		post.EmbedURLs = req.EmbedURLs
+	}
+	if req.Hashtags != nil {
+		post.Hashtags = req.Hashtags
+	}
+
+	if err := s.repo.UpdateDraft(ctx, post); err != nil {
+		return nil, err
+	}
+
+	s.cache.Delete(fmt.Sprintf("post:%s", id))
+
+	return post, nil
+}
+
+// GetDrafts lists the user's drafts, most recently saved first
+func (s *postService) GetDrafts(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error) {
+	if userID == "" {
+		return nil, apperrors.BadRequest("user ID cannot be empty")
+	}
+
+	return s.repo.GetDraftsByUserID(ctx, userID, clampPageSize(limit), offset)
+}
+
+// PublishDraft publishes a draft now, or schedules it when a future publish
+// time is given. The draft must pass the same validation as a new post.
+func (s *postService) PublishDraft(ctx context.Context, id string, userID string, req *model.PublishDraftRequest) (*model.Post, error) {
+	post, err := s.getDraft(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	createReq := &model.CreatePostRequest{
+		Content:    post.Content,
+		EmbedURLs:  post.EmbedURLs,
+		Hashtags:   post.Hashtags,
+		Visibility: req.Visibility,
+		PublishAt:  req.PublishAt,
+	}
+	if err := s.validator.Validate(createReq); err != nil {
+		return nil, apperrors.BadRequest(err.Error())
+	}
+
+	// Drafts are private, so they are only filtered once published
+	verdict, err := s.checkContent(ctx, userID, post.Content, post.EmbedURLs)
+	if err != nil {
+		return nil, err
+	}
+
+	mentions, err := s.resolveMentions(ctx, post.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	post.Hashtags = normalizeHashtags(post.Hashtags)
+	post.Mentions = mentions
+	if req.Visibility != "" {
+		post.Visibility = req.Visibility
+	}
+
+	if err := schedulePost(post, req.PublishAt); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.PublishDraft(ctx, post); err != nil {
+		return nil, err
+	}
+
+	s.recordContent(ctx, userID, post.Content, post.EmbedURLs)
+	s.content.FlagForReview(ctx, verdict, model.ReportTargetPost, post.ID, userID)
+
+	s.cache.Delete(fmt.Sprintf("post:%s", id))
+
+	if post.Status == model.PostStatusPublished {
+		s.fanOut(ctx, post)
+		s.invalidateTimelines(userID)
+	}
+
+	return post, nil
+}
+
+// getDraft loads a draft owned by the user straight from the database, since
+// autosaves change drafts too often for caching to help
+func (s *postService) getDraft(ctx context.Context, id string, userID string) (*model.Post, error) {
+	if id == "" {
+		return nil, apperrors.BadRequest("post ID cannot be empty")
+	}
+
+	post, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Other users' drafts are reported as missing rather than forbidden
+	if post.Status != model.PostStatusDraft || post.UserID != userID {
+		return nil, apperrors.NotFound(fmt.Sprintf("draft: %s", id))
+	}
+
+	return post, nil
+}
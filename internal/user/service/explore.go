@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/platform/cache"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/PeterM45/perfolio-api/pkg/pagination"
+)
+
+const (
+	// exploreCacheKey holds the precomputed explore feed
+	exploreCacheKey = "explore"
+	// maxExploreCandidates is the most recent posts scored for the explore feed
+	maxExploreCandidates = 2000
+)
+
+// GetExplore lists popular recent public posts from across the platform, best
+// first. Signed-in viewers do not see their own posts or posts by accounts
+// they already follow or are in a block with. Pages continue after the score,
+// creation time and ID in the cursor.
+func (s *postService) GetExplore(ctx context.Context, viewerID string, cursorToken string, limit int) ([]*model.Post, string, error) {
+	cursor, err := pagination.Decode(cursorToken)
+	if err != nil {
+		return nil, "", apperrors.BadRequest(err.Error())
+	}
+
+	limit = clampPageSize(limit)
+
+	var ranked []rankedPost
+	if !cache.GetInto(s.cache, exploreCacheKey, &ranked) {
+		// The explore builder has not run yet, or its result expired
+		ranked, err = s.buildExplore(ctx)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	if viewerID != "" {
+		ranked, err = s.excludeKnownAuthors(ctx, ranked, viewerID)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	ids, nextCursor := pageRanked(ranked, cursor, limit)
+
+	posts, err := s.GetPostsByIDs(ctx, ids, viewerID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return posts, nextCursor, nil
+}
+
+// BuildExplore precomputes the explore feed and caches it for every instance
+// to serve. It returns the number of posts in the feed.
+func (s *postService) BuildExplore(ctx context.Context) (int, error) {
+	ranked, err := s.buildExplore(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(ranked), nil
+}
+
+// buildExplore ranks the public posts of the explore window and keeps the
+// best of them, with at most ExploreMaxPerAuthor posts by any one author so a
+// single prolific account cannot fill the feed
+func (s *postService) buildExplore(ctx context.Context) ([]rankedPost, error) {
+	now := time.Now()
+	posts, err := s.repo.GetExploreCandidates(ctx, now.Add(-s.config.ExploreWindow), maxExploreCandidates)
+	if err != nil {
+		return nil, err
+	}
+
+	ranked, err := s.rankPosts(ctx, posts, nil, now)
+	if err != nil {
+		return nil, err
+	}
+
+	perAuthor := make(map[string]int)
+	explore := make([]rankedPost, 0, s.config.ExploreSize)
+	for _, entry := range ranked {
+		if len(explore) == s.config.ExploreSize {
+			break
+		}
+		if perAuthor[entry.AuthorID] == s.config.ExploreMaxPerAuthor {
+			continue
+		}
+
+		perAuthor[entry.AuthorID]++
+		explore = append(explore, entry)
+	}
+
+	// Kept for two rebuilds, so a slow or failed rebuild never leaves it empty
+	s.cache.Set(exploreCacheKey, explore, 2*s.config.ExploreInterval)
+
+	return explore, nil
+}
+
+// excludeKnownAuthors drops the viewer's own posts and posts by accounts the
+// viewer follows or is in a block with from a ranking
+func (s *postService) excludeKnownAuthors(ctx context.Context, ranked []rankedPost, viewerID string) ([]rankedPost, error) {
+	authorIDs := make([]string, 0, len(ranked))
+	for _, entry := range ranked {
+		authorIDs = append(authorIDs, entry.AuthorID)
+	}
+
+	excluded, err := s.repo.GetExcludedAuthors(ctx, viewerID, uniqueStrings(authorIDs))
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]rankedPost, 0, len(ranked))
+	for _, entry := range ranked {
+		if entry.AuthorID == viewerID || excluded[entry.AuthorID] {
+			continue
+		}
+		filtered = append(filtered, entry)
+	}
+
+	return filtered, nil
+}
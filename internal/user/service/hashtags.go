@@ -0,0 +1,31 @@
+package service
+
+import (
+	"strings"
+)
+
+// normalizeHashtag lowercases a hashtag and strips a leading '#'
+func normalizeHashtag(tag string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(tag), "#"))
+}
+
+// normalizeHashtags normalizes a list of hashtags, dropping empty and duplicate tags
+func normalizeHashtags(tags []string) []string {
+	if len(tags) == 0 {
+		return tags
+	}
+
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		tag = normalizeHashtag(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/platform/unfurl"
+	"github.com/PeterM45/perfolio-api/internal/user/interfaces"
+	"github.com/PeterM45/perfolio-api/internal/user/repository"
+	"github.com/PeterM45/perfolio-api/pkg/logger"
+)
+
+const (
+	// unfurlConcurrency is how many URLs are fetched at once
+	unfurlConcurrency = 4
+	// unfurlClaimTimeout is how long a claimed URL may go unfinished before
+	// another instance takes it over
+	unfurlClaimTimeout = 5 * time.Minute
+)
+
+type linkPreviewService struct {
+	repo     repository.LinkPreviewRepository
+	unfurler *unfurl.Unfurler
+	logger   logger.Logger
+}
+
+// NewLinkPreviewService creates a new LinkPreviewService
+func NewLinkPreviewService(
+	repo repository.LinkPreviewRepository,
+	unfurler *unfurl.Unfurler,
+	logger logger.Logger,
+) interfaces.LinkPreviewService {
+	return &linkPreviewService{
+		repo:     repo,
+		unfurler: unfurler,
+		logger:   logger,
+	}
+}
+
+// ProcessPending unfurls up to limit queued URLs and stores their previews.
+// It returns the number of URLs processed, whether or not they could be fetched.
+func (s *linkPreviewService) ProcessPending(ctx context.Context, limit int) (int, error) {
+	urls, err := s.repo.ClaimPending(ctx, time.Now().UTC().Add(-unfurlClaimTimeout), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, unfurlConcurrency)
+
+	for _, url := range urls {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(url string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			s.process(ctx, url)
+		}(url)
+	}
+
+	wg.Wait()
+
+	if len(urls) > 0 {
+		s.logger.Info().Int("count", len(urls)).Msg("Unfurled link previews")
+	}
+
+	return len(urls), nil
+}
+
+// process fetches a single URL and records the outcome
+func (s *linkPreviewService) process(ctx context.Context, url string) {
+	meta, err := s.unfurler.Fetch(ctx, url)
+	if err != nil {
+		s.logger.Debug().Err(err).Str("url", url).Msg("Failed to unfurl link")
+
+		if err := s.repo.MarkFailed(ctx, url, err.Error()); err != nil {
+			s.logger.Warn().Err(err).Str("url", url).Msg("Failed to record link preview failure")
+		}
+		return
+	}
+
+	preview := &model.LinkPreview{
+		URL:         url,
+		Title:       meta.Title,
+		Description: meta.Description,
+		SiteName:    meta.SiteName,
+	}
+
+	if meta.ImageURL != "" {
+		preview.ImageURL = &meta.ImageURL
+	}
+
+	if meta.URL != "" && meta.URL != url {
+		preview.FinalURL = &meta.URL
+	}
+
+	if err := s.repo.SaveResult(ctx, preview); err != nil {
+		s.logger.Warn().Err(err).Str("url", url).Msg("Failed to save link preview")
+	}
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/platform/imaging"
+	"github.com/PeterM45/perfolio-api/internal/platform/storage"
+	"github.com/PeterM45/perfolio-api/internal/user/interfaces"
+	"github.com/PeterM45/perfolio-api/internal/user/repository"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/PeterM45/perfolio-api/pkg/logger"
+	"github.com/google/uuid"
+)
+
+// MediaConfig holds the limits applied to uploads
+type MediaConfig struct {
+	MaxUploadBytes int64
+	MaxPixels      int
+	MaxFrames      int
+	ThumbnailSize  int
+	// OrphanTTL is how long an upload may stay unattached before it is removed
+	OrphanTTL time.Duration
+}
+
+type mediaService struct {
+	repo    repository.MediaRepository
+	storage storage.Storage
+	config  MediaConfig
+	logger  logger.Logger
+}
+
+// NewMediaService creates a new MediaService
+func NewMediaService(
+	repo repository.MediaRepository,
+	storage storage.Storage,
+	config MediaConfig,
+	logger logger.Logger,
+) interfaces.MediaService {
+	if config.MaxUploadBytes <= 0 {
+		config.MaxUploadBytes = 10 << 20
+	}
+	if config.OrphanTTL <= 0 {
+		config.OrphanTTL = 24 * time.Hour
+	}
+
+	return &mediaService{
+		repo:    repo,
+		storage: storage,
+		config:  config,
+		logger:  logger,
+	}
+}
+
+// MaxUploadBytes returns the largest file accepted by UploadMedia
+func (s *mediaService) MaxUploadBytes() int64 {
+	return s.config.MaxUploadBytes
+}
+
+// UploadMedia validates an image, strips its metadata, stores it with a
+// thumbnail and records it as an unattached upload of the user
+func (s *mediaService) UploadMedia(ctx context.Context, userID string, data []byte) (*model.Media, error) {
+	if len(data) == 0 {
+		return nil, apperrors.BadRequest("file cannot be empty")
+	}
+
+	if int64(len(data)) > s.config.MaxUploadBytes {
+		return nil, apperrors.BadRequest(fmt.Sprintf("file cannot be larger than %d bytes", s.config.MaxUploadBytes))
+	}
+
+	processed, err := imaging.Process(data, imaging.Options{
+		MaxPixels:     s.config.MaxPixels,
+		MaxFrames:     s.config.MaxFrames,
+		ThumbnailSize: s.config.ThumbnailSize,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, imaging.ErrUnsupportedFormat):
+			return nil, apperrors.BadRequest("file must be a JPEG, PNG or GIF image")
+		case errors.Is(err, imaging.ErrTooLarge):
+			return nil, apperrors.BadRequest("image dimensions are too large")
+		case errors.Is(err, imaging.ErrTooManyFrames):
+			return nil, apperrors.BadRequest("animation has too many frames")
+		}
+		return nil, err
+	}
+
+	id := uuid.New().String()
+	media := &model.Media{
+		ID:           id,
+		UserID:       userID,
+		ContentType:  processed.ContentType,
+		Size:         int64(len(processed.Data)),
+		Width:        processed.Width,
+		Height:       processed.Height,
+		StorageKey:   fmt.Sprintf("media/%s.%s", id, processed.Extension),
+		ThumbnailKey: fmt.Sprintf("media/%s_thumb.%s", id, processed.ThumbnailExtension),
+	}
+	media.URL = s.storage.URL(media.StorageKey)
+	media.ThumbnailURL = s.storage.URL(media.ThumbnailKey)
+
+	if err := s.storage.Put(ctx, media.StorageKey, processed.Data, processed.ContentType); err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.Put(ctx, media.ThumbnailKey, processed.Thumbnail, processed.ThumbnailContentType); err != nil {
+		s.deleteFiles(ctx, media)
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, media); err != nil {
+		s.deleteFiles(ctx, media)
+		return nil, err
+	}
+
+	return media, nil
+}
+
+// DeleteMedia removes one of the user's uploads that is not attached to a post
+func (s *mediaService) DeleteMedia(ctx context.Context, id string, userID string) error {
+	media, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if media.UserID != userID {
+		return apperrors.Forbidden("you can only delete your own uploads")
+	}
+
+	if media.PostID != nil {
+		return apperrors.Conflict("media is attached to a post")
+	}
+
+	if err := s.repo.DeleteUnattached(ctx, id); err != nil {
+		return err
+	}
+
+	s.deleteFiles(ctx, media)
+
+	return nil
+}
+
+// CollectOrphans removes up to limit uploads that were never attached to a
+// post, or whose post was purged, once they are older than the orphan TTL.
+// It returns the number removed.
+func (s *mediaService) CollectOrphans(ctx context.Context, limit int) (int, error) {
+	orphans, err := s.repo.DeleteOrphaned(ctx, time.Now().UTC().Add(-s.config.OrphanTTL), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, media := range orphans {
+		s.deleteFiles(ctx, media)
+	}
+
+	if len(orphans) > 0 {
+		s.logger.Info().Int("count", len(orphans)).Msg("Removed orphaned media")
+	}
+
+	return len(orphans), nil
+}
+
+// deleteFiles removes the stored files of an upload. Failures are logged
+// rather than returned, since the record is already gone.
+func (s *mediaService) deleteFiles(ctx context.Context, media *model.Media) {
+	for _, key := range []string{media.StorageKey, media.ThumbnailKey} {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			s.logger.Warn().Err(err).Str("key", key).Msg("Failed to delete media file")
+		}
+	}
+}
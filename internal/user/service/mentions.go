@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"regexp"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+)
+
+// maxMentionsPerPost caps how many distinct usernames are resolved for one post
+const maxMentionsPerPost = 20
+
+// mentionPattern matches @username tokens
+var mentionPattern = regexp.MustCompile(`@([A-Za-z0-9_]+)`)
+
+// parseMentions extracts @username tokens from content with character offsets.
+// Tokens where the @ directly follows a word character (such as an email
+// address) are ignored.
+func parseMentions(content string) []model.Mention {
+	var mentions []model.Mention
+
+	for _, loc := range mentionPattern.FindAllStringSubmatchIndex(content, -1) {
+		start, end := loc[0], loc[1]
+
+		if start > 0 {
+			prev, _ := utf8.DecodeLastRuneInString(content[:start])
+			if prev == '_' || unicode.IsLetter(prev) || unicode.IsDigit(prev) {
+				continue
+			}
+		}
+
+		username := content[loc[2]:loc[3]]
+		if len(username) < 3 || len(username) > 64 {
+			continue
+		}
+
+		runeStart := utf8.RuneCountInString(content[:start])
+		mentions = append(mentions, model.Mention{
+			Username: username,
+			Start:    runeStart,
+			End:      runeStart + utf8.RuneCountInString(content[start:end]),
+		})
+	}
+
+	return mentions
+}
+
+// resolveMentions parses the mentions in content and resolves them to users.
+// Mentions of unknown users are dropped and stay plain text.
+func (s *postService) resolveMentions(ctx context.Context, content string) ([]model.Mention, error) {
+	candidates := parseMentions(content)
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	resolved := make(map[string]*model.User)
+	mentions := make([]model.Mention, 0, len(candidates))
+
+	for _, mention := range candidates {
+		user, seen := resolved[mention.Username]
+		if !seen {
+			if len(resolved) >= maxMentionsPerPost {
+				continue
+			}
+
+			var err error
+			user, err = s.userService.GetUserByUsername(ctx, mention.Username)
+			if err != nil {
+				if !apperrors.Is(err, apperrors.ErrTypeNotFound) {
+					return nil, err
+				}
+				user = nil
+			}
+			resolved[mention.Username] = user
+		}
+
+		if user == nil {
+			continue
+		}
+
+		mention.UserID = user.ID
+		mention.Username = user.Username
+		mentions = append(mentions, mention)
+	}
+
+	return mentions, nil
+}
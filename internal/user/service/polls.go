@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/google/uuid"
+)
+
+// newPoll builds the poll for a new post. The poll opens when the post is
+// published, which is opensAt for scheduled posts and now otherwise.
+func newPoll(req *model.CreatePollRequest, opensAt *time.Time) (*model.Poll, error) {
+	start := time.Now()
+	if opensAt != nil {
+		start = *opensAt
+	}
+
+	duration := req.ClosesAt.Sub(start)
+	if duration < model.MinPollDuration {
+		return nil, apperrors.BadRequest(fmt.Sprintf("polls must stay open for at least %s", model.MinPollDuration))
+	}
+	if duration > model.MaxPollDuration {
+		return nil, apperrors.BadRequest("polls can stay open for at most 7 days")
+	}
+
+	poll := &model.Poll{
+		ClosesAt: req.ClosesAt.UTC(),
+	}
+
+	seen := make(map[string]bool, len(req.Options))
+	for i, text := range req.Options {
+		text = strings.TrimSpace(text)
+		if text == "" {
+			return nil, apperrors.BadRequest("poll options cannot be empty")
+		}
+
+		key := strings.ToLower(text)
+		if seen[key] {
+			return nil, apperrors.BadRequest("poll options must be different")
+		}
+		seen[key] = true
+
+		poll.Options = append(poll.Options, &model.PollOption{
+			ID:       uuid.New().String(),
+			Position: i,
+			Text:     text,
+		})
+	}
+
+	return poll, nil
+}
+
+// pollText returns the text of a post together with its poll options, so
+// both can be checked by the content filters
+func pollText(content string, req *model.CreatePollRequest) string {
+	if req == nil {
+		return content
+	}
+
+	return content + "\n" + strings.Join(req.Options, "\n")
+}
+
+// VotePoll records the user's vote in the poll of a post they can see and
+// returns the poll with its results
+func (s *postService) VotePoll(ctx context.Context, postID string, userID string, req *model.PollVoteRequest) (*model.Poll, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, apperrors.BadRequest(err.Error())
+	}
+
+	post, err := s.getPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkView(ctx, post, userID); err != nil {
+		return nil, err
+	}
+
+	if post.Status != model.PostStatusPublished {
+		return nil, apperrors.BadRequest("polls open when their post is published")
+	}
+
+	poll, err := s.getPoll(ctx, postID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if poll.Closed {
+		return nil, apperrors.BadRequest("poll is closed")
+	}
+
+	if poll.ViewerOptionID != nil {
+		return nil, apperrors.Conflict("you have already voted in this poll")
+	}
+
+	found := false
+	for _, option := range poll.Options {
+		if option.ID == req.OptionID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, apperrors.BadRequest("option is not part of this poll")
+	}
+
+	if err := s.repo.VotePoll(ctx, postID, req.OptionID, userID); err != nil {
+		return nil, err
+	}
+
+	return s.getPoll(ctx, postID, userID)
+}
+
+// getPoll loads the poll of a post as the viewer may see it
+func (s *postService) getPoll(ctx context.Context, postID string, viewerID string) (*model.Poll, error) {
+	polls, err := s.repo.GetPolls(ctx, []string{postID}, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	poll, ok := polls[postID]
+	if !ok {
+		return nil, apperrors.NotFound(fmt.Sprintf("poll: %s", postID))
+	}
+
+	return viewPoll(poll, time.Now()), nil
+}
+
+// withPolls attaches the polls of the posts as the viewer may see them. The
+// posts must already be copies, since they are modified in place.
+func (s *postService) withPolls(ctx context.Context, posts []*model.Post, viewerID string) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	postIDs := make([]string, 0, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+	}
+
+	polls, err := s.repo.GetPolls(ctx, postIDs, viewerID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, post := range posts {
+		if poll, ok := polls[post.ID]; ok {
+			post.Poll = viewPoll(poll, now)
+		} else {
+			post.Poll = nil
+		}
+	}
+
+	return nil
+}
+
+// viewPoll marks whether the poll has closed and hides its results until it
+// has or the viewer has voted, so early results cannot sway the vote
+func viewPoll(poll *model.Poll, now time.Time) *model.Poll {
+	poll.Closed = !now.Before(poll.ClosesAt)
+
+	if !poll.Closed && poll.ViewerOptionID == nil {
+		poll.TotalVotes = nil
+		for _, option := range poll.Options {
+			option.VoteCount = nil
+		}
+	}
+
+	return poll
+}
@@ -2,40 +2,120 @@ package service
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/PeterM45/perfolio-api/internal/common/model"
 	"github.com/PeterM45/perfolio-api/internal/platform/cache"
+	"github.com/PeterM45/perfolio-api/internal/platform/moderation"
+	"github.com/PeterM45/perfolio-api/internal/platform/ranking"
 	"github.com/PeterM45/perfolio-api/internal/user/interfaces"
 	"github.com/PeterM45/perfolio-api/internal/user/repository"
 	"github.com/PeterM45/perfolio-api/pkg/apperrors"
 	"github.com/PeterM45/perfolio-api/pkg/logger"
+	"github.com/PeterM45/perfolio-api/pkg/pagination"
 	"github.com/PeterM45/perfolio-api/pkg/validator"
 	"github.com/google/uuid"
 )
 
+const (
+	maxScheduleAhead      = 365 * 24 * time.Hour
+	maxPostPageSize       = 100
+	defaultTrendingWindow = 24 * time.Hour
+	maxTrendingWindow     = 7 * 24 * time.Hour
+)
+
+// FeedConfig controls how home timelines are built
+type FeedConfig struct {
+	// FanOutMaxFollowers is the follower count above which new posts are not
+	// copied to every follower's timeline but read from the author instead
+	FanOutMaxFollowers int
+	// TopWindow is how far back the top feed looks for posts
+	TopWindow time.Duration
+	// TopCandidates is the most posts the top feed ranks at once
+	TopCandidates int
+	// AffinityWindow is how far back the viewer's interactions with an
+	// author count towards their affinity
+	AffinityWindow time.Duration
+	// Strategy scores posts in the top and explore feeds
+	Strategy ranking.Strategy
+	// ExploreWindow is how far back the explore feed looks for posts
+	ExploreWindow time.Duration
+	// ExploreSize is the number of posts in the explore feed
+	ExploreSize int
+	// ExploreMaxPerAuthor is the most posts by one author in the explore feed
+	ExploreMaxPerAuthor int
+	// ExploreInterval is how often the explore feed is rebuilt
+	ExploreInterval time.Duration
+}
+
 type postService struct {
 	repo        repository.PostRepository
 	userService interfaces.UserService
+	content     *ContentChecker
+	config      FeedConfig
 	cache       cache.Cache
 	validator   validator.Validator
 	logger      logger.Logger
+
+	// staleAuthors holds the authors whose followers' timelines are waiting
+	// for the timeline invalidator. It is local to the process; see
+	// InvalidateFollowerTimelines.
+	staleMu      sync.Mutex
+	staleAuthors map[string]bool
 }
 
 // NewPostService creates a new PostService
 func NewPostService(
 	repo repository.PostRepository,
 	userService interfaces.UserService,
+	content *ContentChecker,
+	config FeedConfig,
 	cache cache.Cache,
 	logger logger.Logger,
 ) interfaces.PostService {
+	if config.FanOutMaxFollowers <= 0 {
+		config.FanOutMaxFollowers = 10000
+	}
+	if config.TopWindow <= 0 {
+		config.TopWindow = 72 * time.Hour
+	}
+	if config.TopCandidates <= 0 {
+		config.TopCandidates = 500
+	}
+	if config.AffinityWindow <= 0 {
+		config.AffinityWindow = 30 * 24 * time.Hour
+	}
+	if config.Strategy == nil {
+		config.Strategy = ranking.NewWeightedStrategy(ranking.DefaultWeights())
+	}
+	if config.ExploreWindow <= 0 {
+		config.ExploreWindow = 48 * time.Hour
+	}
+	if config.ExploreSize <= 0 {
+		config.ExploreSize = 200
+	}
+	if config.ExploreMaxPerAuthor <= 0 {
+		config.ExploreMaxPerAuthor = 2
+	}
+	if config.ExploreInterval <= 0 {
+		config.ExploreInterval = 5 * time.Minute
+	}
+
 	return &postService{
-		repo:        repo,
-		userService: userService,
-		cache:       cache,
-		validator:   validator.NewValidator(),
-		logger:      logger,
+		repo:         repo,
+		userService:  userService,
+		content:      content,
+		config:       config,
+		cache:        cache,
+		validator:    validator.NewValidator(),
+		logger:       logger,
+		staleAuthors: make(map[string]bool),
 	}
 }
 
@@ -46,7 +126,22 @@ func (s *postService) CreatePost(ctx context.Context, userID string, req *model.
 	}
 
 	// Verify user exists
-	if _, err := s.userService.GetUserByID(ctx, userID); err != nil {
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsActive {
+		return nil, apperrors.Forbidden("your account is suspended")
+	}
+
+	verdict, err := s.checkContent(ctx, userID, pollText(req.Content, req.Poll), req.EmbedURLs)
+	if err != nil {
+		return nil, err
+	}
+
+	mentions, err := s.resolveMentions(ctx, req.Content)
+	if err != nil {
 		return nil, err
 	}
 
@@ -56,31 +151,101 @@ func (s *postService) CreatePost(ctx context.Context, userID string, req *model.
 		UserID:     userID,
 		Content:    req.Content,
 		EmbedURLs:  req.EmbedURLs,
-		Hashtags:   req.Hashtags,
+		Hashtags:   normalizeHashtags(req.Hashtags),
+		MediaIDs:   uniqueStrings(req.MediaIDs),
 		Visibility: model.VisibilityPublic, // Default visibility
+		Status:     model.PostStatusPublished,
+		Mentions:   mentions,
+	}
+
+	if req.Visibility != "" {
+		post.Visibility = req.Visibility
+	}
+
+	if err := schedulePost(post, req.PublishAt); err != nil {
+		return nil, err
+	}
+
+	if req.Poll != nil {
+		post.Poll, err = newPoll(req.Poll, post.PublishAt)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	if err := s.repo.Create(ctx, post); err != nil {
 		return nil, err
 	}
 
-	// Invalidate feed caches
-	s.cache.Delete(fmt.Sprintf("user_posts:%s", userID))
+	s.recordContent(ctx, userID, pollText(req.Content, req.Poll), req.EmbedURLs)
+	s.content.FlagForReview(ctx, verdict, model.ReportTargetPost, post.ID, userID)
+
+	if post.Status == model.PostStatusPublished {
+		s.fanOut(ctx, post)
+
+		// The post belongs at the top of the author's and followers' timelines
+		s.invalidateTimelines(userID)
+	}
+
+	if err := s.withMedia(ctx, []*model.Post{post}); err != nil {
+		return nil, err
+	}
 
 	return post, nil
 }
 
-// GetPostByID retrieves a post by ID
-func (s *postService) GetPostByID(ctx context.Context, id string) (*model.Post, error) {
+// schedulePost marks a post as scheduled when publishAt is in the future.
+// Otherwise the post is published immediately.
+func schedulePost(post *model.Post, publishAt *time.Time) error {
+	post.Status = model.PostStatusPublished
+	post.PublishAt = nil
+
+	if publishAt == nil || !publishAt.After(time.Now()) {
+		return nil
+	}
+
+	if publishAt.After(time.Now().Add(maxScheduleAhead)) {
+		return apperrors.BadRequest("posts can be scheduled at most one year ahead")
+	}
+
+	at := publishAt.UTC()
+	post.Status = model.PostStatusScheduled
+	post.PublishAt = &at
+
+	return nil
+}
+
+// GetPostByID retrieves a post by ID with its reaction summary for the viewer
+func (s *postService) GetPostByID(ctx context.Context, id string, viewerID string) (*model.Post, error) {
+	post, err := s.getPost(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkView(ctx, post, viewerID); err != nil {
+		return nil, err
+	}
+
+	posts, err := s.enrichPosts(ctx, []*model.Post{post}, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return posts[0], nil
+}
+
+// getPost retrieves a post by ID, using the cache when possible
+func (s *postService) getPost(ctx context.Context, id string) (*model.Post, error) {
 	if id == "" {
 		return nil, apperrors.BadRequest("post ID cannot be empty")
 	}
 
 	// Check cache first
 	cacheKey := fmt.Sprintf("post:%s", id)
-	if cachedPost, found := s.cache.Get(cacheKey); found {
+	var cachedPost *model.Post
+	if cache.GetInto(s.cache, cacheKey, &cachedPost) && cachedPost != nil {
 		s.logger.Debug().Str("post_id", id).Msg("Post found in cache")
-		return cachedPost.(*model.Post), nil
+		return cachedPost, nil
 	}
 
 	post, err := s.repo.GetByID(ctx, id)
@@ -105,7 +270,66 @@ func (s *postService) UpdatePost(ctx context.Context, id string, userID string,
 	}
 
 	// Get existing post
-	post, err := s.GetPostByID(ctx, id)
+	post, err := s.getPost(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check if user owns the post
+	if post.UserID != userID {
+		return nil, apperrors.Forbidden("you don't have permission to update this post")
+	}
+
+	// Drafts are saved through the draft endpoints and keep no revisions
+	if post.Status == model.PostStatusDraft {
+		return nil, apperrors.BadRequest("drafts must be updated through the draft endpoints")
+	}
+
+	if post.Kind == model.PostKindRepost {
+		return nil, apperrors.BadRequest("reposts cannot be edited")
+	}
+
+	// Edit a copy, since the post may be the cached one and the edit can
+	// still be rejected
+	edited := *post
+	if req.Visibility != "" {
+		edited.Visibility = req.Visibility
+	}
+
+	// Cached timelines are refreshed by saveEdit, which also covers a change
+	// of audience
+	if err := s.saveEdit(ctx, &edited, req.Content, req.EmbedURLs, req.Hashtags); err != nil {
+		return nil, err
+	}
+
+	return &edited, nil
+}
+
+// GetPostRevisions lists the earlier versions of a post, newest first
+func (s *postService) GetPostRevisions(ctx context.Context, id string, viewerID string, limit, offset int) ([]*model.PostRevision, error) {
+	post, err := s.getPost(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkView(ctx, post, viewerID); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > maxPostPageSize {
+		limit = maxPostPageSize
+	}
+
+	return s.repo.GetRevisions(ctx, id, limit, offset)
+}
+
+// RestorePostRevision makes an earlier revision the current version of a post.
+// The version being replaced is kept as a new revision, so a restore can be undone.
+func (s *postService) RestorePostRevision(ctx context.Context, id string, revision int, userID string) (*model.Post, error) {
+	post, err := s.getPost(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -115,21 +339,81 @@ func (s *postService) UpdatePost(ctx context.Context, id string, userID string,
 		return nil, apperrors.Forbidden("you don't have permission to update this post")
 	}
 
+	rev, err := s.repo.GetRevision(ctx, id, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	// Edit a copy, since the post may be the cached one
+	edited := *post
+	if err := s.saveEdit(ctx, &edited, rev.Content, rev.EmbedURLs, rev.Hashtags); err != nil {
+		return nil, err
+	}
+
+	return &edited, nil
+}
+
+// saveEdit applies new content to a post, stores it and invalidates cached
+// copies. The post must not be the cached one, since it is modified in place.
+func (s *postService) saveEdit(ctx context.Context, post *model.Post, content string, embedURLs, hashtags []string) error {
+	// Cached copies are dropped whether or not the edit is saved, so a failed
+	// edit never leaves a version the database does not hold
+	defer s.cache.Delete(fmt.Sprintf("post:%s", post.ID))
+
+	// Unchanged text was checked when it was first saved
+	var verdict moderation.Verdict
+	if content != post.Content || strings.Join(embedURLs, " ") != strings.Join(post.EmbedURLs, " ") {
+		var err error
+		verdict, err = s.checkContent(ctx, post.UserID, content, embedURLs)
+		if err != nil {
+			return err
+		}
+	}
+
+	mentions, err := s.resolveMentions(ctx, content)
+	if err != nil {
+		return err
+	}
+
 	// Update post fields
-	post.Content = req.Content
-	post.EmbedURLs = req.EmbedURLs
-	post.Hashtags = req.Hashtags
+	post.Content = content
+	post.EmbedURLs = embedURLs
+	post.Hashtags = normalizeHashtags(hashtags)
+	post.Mentions = mentions
 
 	// Save updates
 	if err := s.repo.Update(ctx, post); err != nil {
-		return nil, err
+		return err
 	}
 
-	// Invalidate cache
-	s.cache.Delete(fmt.Sprintf("post:%s", id))
-	s.cache.Delete(fmt.Sprintf("user_posts:%s", userID))
+	s.content.FlagForReview(ctx, verdict, model.ReportTargetPost, post.ID, post.UserID)
 
-	return post, nil
+	// Cached timelines hold the old version of the post
+	if post.Status == model.PostStatusPublished {
+		s.invalidateTimelines(post.UserID)
+	}
+
+	return nil
+}
+
+// checkContent runs the content filters over the text and links of a post
+func (s *postService) checkContent(ctx context.Context, userID string, content string, embedURLs []string) (moderation.Verdict, error) {
+	return s.content.Check(ctx, postContent(userID, content, embedURLs))
+}
+
+// recordContent tells the content filters a checked post was stored
+func (s *postService) recordContent(ctx context.Context, userID string, content string, embedURLs []string) {
+	s.content.Record(ctx, postContent(userID, content, embedURLs))
+}
+
+// postContent describes the text and links of a post to the content filters
+func postContent(userID string, content string, embedURLs []string) *moderation.Content {
+	return &moderation.Content{
+		UserID: userID,
+		Kind:   moderation.KindPost,
+		Text:   content,
+		Links:  embedURLs,
+	}
 }
 
 // DeletePost deletes a post
@@ -139,7 +423,7 @@ func (s *postService) DeletePost(ctx context.Context, id string, userID string)
 	}
 
 	// Get existing post
-	post, err := s.GetPostByID(ctx, id)
+	post, err := s.getPost(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -149,85 +433,743 @@ func (s *postService) DeletePost(ctx context.Context, id string, userID string)
 		return apperrors.Forbidden("you don't have permission to delete this post")
 	}
 
-	// Delete post
-	if err := s.repo.Delete(ctx, id); err != nil {
+	// Move post to the trash
+	reposts, err := s.repo.Delete(ctx, id)
+	if err != nil {
 		return err
 	}
 
 	// Invalidate cache
 	s.cache.Delete(fmt.Sprintf("post:%s", id))
-	s.cache.Delete(fmt.Sprintf("user_posts:%s", userID))
+
+	// The post leaves the timelines of its author and their followers, and
+	// plain reposts went with it, so they leave their authors' timelines too
+	authors := map[string]bool{userID: true}
+	for _, repost := range reposts {
+		s.cache.Delete(fmt.Sprintf("post:%s", repost.ID))
+		authors[repost.UserID] = true
+	}
+
+	for authorID := range authors {
+		s.invalidateTimelines(authorID)
+	}
 
 	return nil
 }
 
-// GetUserPosts gets posts by a user
-func (s *postService) GetUserPosts(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error) {
+// GetUserPosts gets posts by a user, pinned posts first, with the cursor for
+// the next page
+func (s *postService) GetUserPosts(ctx context.Context, userID string, viewerID string, cursorToken string, limit, offset int) ([]*model.Post, string, error) {
 	if userID == "" {
-		return nil, apperrors.BadRequest("user ID cannot be empty")
+		return nil, "", apperrors.BadRequest("user ID cannot be empty")
+	}
+
+	cursor, err := pagination.Decode(cursorToken)
+	if err != nil {
+		return nil, "", apperrors.BadRequest(err.Error())
+	}
+
+	// Verify user exists and is visible to the viewer
+	if _, err := s.userService.GetProfile(ctx, userID, viewerID); err != nil {
+		return nil, "", err
+	}
+
+	// Viewers with the same relationship to the author see the same posts
+	audience, err := s.audience(ctx, userID, viewerID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	limit = clampPageSize(limit)
+
+	// Check cache for list of posts
+	var posts []*model.Post
+	cacheKey := fmt.Sprintf("user_posts:%s:%s:%s:%s:%d:%d", userID, audience, s.timelineVersion(userID), cursorToken, limit, offset)
+	if cache.GetInto(s.cache, cacheKey, &posts) {
+		s.logger.Debug().Str("user_id", userID).Msg("User posts found in cache")
+	} else {
+		// Fetch one extra row to know whether another page exists
+		posts, err = s.repo.GetByUserID(ctx, userID, viewerID, cursor, limit+1, offset)
+		if err != nil {
+			return nil, "", err
+		}
+
+		// Store in cache
+		s.cache.Set(cacheKey, posts, 2*time.Minute) // Shorter TTL for lists
+	}
+
+	posts, nextCursor := pageUserPosts(posts, limit)
+
+	posts, err = s.enrichPosts(ctx, posts, viewerID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return posts, nextCursor, nil
+}
+
+// GetFeed gets posts for user's feed from their home timeline in the given
+// mode, newest first by default, with the cursor for the next page
+func (s *postService) GetFeed(ctx context.Context, userID string, mode model.FeedMode, cursorToken string, limit, offset int) ([]*model.Post, string, error) {
+	if userID == "" {
+		return nil, "", apperrors.BadRequest("user ID cannot be empty")
+	}
+
+	switch mode {
+	case "", model.FeedModeLatest, model.FeedModeTop:
+	default:
+		return nil, "", apperrors.BadRequest(fmt.Sprintf("unknown feed mode: %s", mode))
+	}
+
+	cursor, err := pagination.Decode(cursorToken)
+	if err != nil {
+		return nil, "", apperrors.BadRequest(err.Error())
 	}
 
 	// Verify user exists
 	if _, err := s.userService.GetUserByID(ctx, userID); err != nil {
+		return nil, "", err
+	}
+
+	limit = clampPageSize(limit)
+
+	if mode == model.FeedModeTop {
+		return s.getTopFeed(ctx, userID, cursor, limit)
+	}
+
+	version, err := s.feedVersion(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Check cache
+	var posts []*model.Post
+	cacheKey := fmt.Sprintf("feed:%s:%s:%s:%d:%d", userID, version, cursorToken, limit, offset)
+	if cache.GetInto(s.cache, cacheKey, &posts) {
+		s.logger.Debug().Str("user_id", userID).Msg("Feed found in cache")
+	} else {
+		// Fetch one extra row to know whether another page exists
+		posts, err = s.repo.GetFeed(ctx, userID, cursor, limit+1, offset)
+		if err != nil {
+			return nil, "", err
+		}
+
+		// Store in cache (short TTL for feeds)
+		s.cache.Set(cacheKey, posts, 1*time.Minute)
+	}
+
+	posts, nextCursor := pagePosts(posts, limit)
+
+	posts, err = s.enrichPosts(ctx, posts, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return posts, nextCursor, nil
+}
+
+// GetPostsByIDs loads the posts the viewer may see, in the order of ids.
+// Missing and hidden posts are left out.
+func (s *postService) GetPostsByIDs(ctx context.Context, ids []string, viewerID string) ([]*model.Post, error) {
+	posts, err := s.repo.GetVisibleByIDs(ctx, ids, viewerID)
+	if err != nil {
 		return nil, err
 	}
 
-	// Check cache for list of posts
-	cacheKey := fmt.Sprintf("user_posts:%s:%d:%d", userID, limit, offset)
-	if cachedPosts, found := s.cache.Get(cacheKey); found {
-		s.logger.Debug().Str("user_id", userID).Msg("User posts found in cache")
-		return cachedPosts.([]*model.Post), nil
+	byID := make(map[string]*model.Post, len(posts))
+	for _, post := range posts {
+		byID[post.ID] = post
 	}
 
-	posts, err := s.repo.GetByUserID(ctx, userID, limit, offset)
+	ordered := make([]*model.Post, 0, len(posts))
+	for _, id := range ids {
+		if post, ok := byID[id]; ok {
+			ordered = append(ordered, post)
+		}
+	}
+
+	return s.enrichPosts(ctx, ordered, viewerID)
+}
+
+// PinPost pins one of the user's published posts to the top of their profile timeline
+func (s *postService) PinPost(ctx context.Context, id string, userID string) (*model.Post, error) {
+	post, err := s.getPost(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	// Store in cache
-	s.cache.Set(cacheKey, posts, 2*time.Minute) // Shorter TTL for lists
+	// Check if user owns the post
+	if post.UserID != userID {
+		return nil, apperrors.Forbidden("you don't have permission to pin this post")
+	}
 
-	return posts, nil
+	if post.Status != model.PostStatusPublished {
+		return nil, apperrors.BadRequest("only published posts can be pinned")
+	}
+
+	if err := s.repo.Pin(ctx, id, userID, model.MaxPinnedPosts); err != nil {
+		return nil, err
+	}
+
+	s.cache.Delete(fmt.Sprintf("post:%s", id))
+	setTimelineVersion(s.cache, userID, newTimelineVersion())
+
+	return s.GetPostByID(ctx, id, userID)
 }
 
-// GetFeed gets posts for user's feed
-func (s *postService) GetFeed(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error) {
+// UnpinPost removes a post from the pinned posts of its author
+func (s *postService) UnpinPost(ctx context.Context, id string, userID string) error {
+	post, err := s.getPost(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// Check if user owns the post
+	if post.UserID != userID {
+		return apperrors.Forbidden("you don't have permission to unpin this post")
+	}
+
+	if err := s.repo.Unpin(ctx, id); err != nil {
+		return err
+	}
+
+	s.cache.Delete(fmt.Sprintf("post:%s", id))
+	setTimelineVersion(s.cache, userID, newTimelineVersion())
+
+	return nil
+}
+
+// GetScheduledPosts lists the user's own scheduled posts, soonest first
+func (s *postService) GetScheduledPosts(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error) {
 	if userID == "" {
 		return nil, apperrors.BadRequest("user ID cannot be empty")
 	}
 
-	// Verify user exists
-	if _, err := s.userService.GetUserByID(ctx, userID); err != nil {
-		return nil, err
+	return s.repo.GetScheduledByUserID(ctx, userID, clampPageSize(limit), offset)
+}
+
+// PublishScheduledPosts publishes up to limit scheduled posts that are due and
+// invalidates the timelines they appear in. It returns the number published.
+func (s *postService) PublishScheduledPosts(ctx context.Context, limit int) (int, error) {
+	posts, err := s.repo.PublishDue(ctx, time.Now().UTC(), limit)
+	if err != nil {
+		return 0, err
+	}
+
+	authors := make(map[string]bool)
+	for _, post := range posts {
+		s.cache.Delete(fmt.Sprintf("post:%s", post.ID))
+		s.fanOut(ctx, post)
+		authors[post.UserID] = true
 	}
 
-	// Get following users
-	following, err := s.userService.GetFollowing(ctx, userID, 500, 0) // Get a reasonable number of followed users
+	for authorID := range authors {
+		s.invalidateTimelines(authorID)
+	}
+
+	if len(posts) > 0 {
+		s.logger.Info().Int("count", len(posts)).Msg("Published scheduled posts")
+	}
+
+	return len(posts), nil
+}
+
+// GetMentions gets posts that mention the user
+func (s *postService) GetMentions(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error) {
+	if userID == "" {
+		return nil, apperrors.BadRequest("user ID cannot be empty")
+	}
+
+	posts, err := s.repo.GetMentioning(ctx, userID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
 
-	// Build array of user IDs to fetch posts from (include self)
-	userIDs := []string{userID}
-	for _, user := range following {
-		userIDs = append(userIDs, user.ID)
+	return s.enrichPosts(ctx, posts, userID)
+}
+
+// GetPostsByHashtag gets public posts tagged with a hashtag
+func (s *postService) GetPostsByHashtag(ctx context.Context, tag string, viewerID string, cursorToken string, limit int) ([]*model.Post, string, error) {
+	tag = normalizeHashtag(tag)
+	if tag == "" {
+		return nil, "", apperrors.BadRequest("hashtag cannot be empty")
+	}
+
+	cursor, err := pagination.Decode(cursorToken)
+	if err != nil {
+		return nil, "", apperrors.BadRequest(err.Error())
+	}
+
+	limit = clampPageSize(limit)
+
+	// Fetch one extra row to know whether another page exists
+	posts, err := s.repo.GetByHashtag(ctx, tag, viewerID, cursor, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	posts, nextCursor := pagePosts(posts, limit)
+
+	posts, err = s.enrichPosts(ctx, posts, viewerID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return posts, nextCursor, nil
+}
+
+// SearchPosts runs a full-text search over posts visible to the viewer
+func (s *postService) SearchPosts(ctx context.Context, query string, viewerID string, cursorToken string, limit int) ([]*model.Post, string, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, "", apperrors.BadRequest("search query cannot be empty")
+	}
+
+	cursor, err := pagination.Decode(cursorToken)
+	if err != nil {
+		return nil, "", apperrors.BadRequest(err.Error())
+	}
+
+	limit = clampPageSize(limit)
+
+	// Fetch one extra row to know whether another page exists
+	posts, err := s.repo.Search(ctx, query, viewerID, cursor, limit+1)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var nextCursor string
+	if len(posts) > limit {
+		posts = posts[:limit]
+		last := posts[len(posts)-1]
+		nextCursor = pagination.NewRankedCursor(last.Search.Rank, last.CreatedAt, last.ID).Encode()
+	}
+
+	posts, err = s.enrichPosts(ctx, posts, viewerID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return posts, nextCursor, nil
+}
+
+// GetTrendingHashtags ranks hashtags by usage over a sliding window ending now
+func (s *postService) GetTrendingHashtags(ctx context.Context, window time.Duration, limit int) ([]*model.HashtagTrend, error) {
+	if window <= 0 {
+		window = defaultTrendingWindow
+	}
+	if window > maxTrendingWindow {
+		window = maxTrendingWindow
+	}
+
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 50 {
+		limit = 50
 	}
 
 	// Check cache
-	cacheKey := fmt.Sprintf("feed:%s:%d:%d", userID, limit, offset)
-	if cachedFeed, found := s.cache.Get(cacheKey); found {
-		s.logger.Debug().Str("user_id", userID).Msg("Feed found in cache")
-		return cachedFeed.([]*model.Post), nil
+	cacheKey := fmt.Sprintf("trending_hashtags:%d:%d", int(window.Minutes()), limit)
+	var trends []*model.HashtagTrend
+	if cache.GetInto(s.cache, cacheKey, &trends) {
+		s.logger.Debug().Str("cache_key", cacheKey).Msg("Trending hashtags found in cache")
+		return trends, nil
+	}
+
+	trends, err := s.repo.GetTrendingHashtags(ctx, time.Now().UTC().Add(-window), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// Store in cache
+	s.cache.Set(cacheKey, trends, 5*time.Minute)
+
+	return trends, nil
+}
+
+// enrichPosts returns copies of the posts with the shared originals, reaction
+// summaries, link previews, media and polls the viewer may see
+func (s *postService) enrichPosts(ctx context.Context, posts []*model.Post, viewerID string) ([]*model.Post, error) {
+	posts, err := s.withReactionSummaries(ctx, posts, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.withLinkPreviews(ctx, posts); err != nil {
+		return nil, err
+	}
+
+	if err := s.withMedia(ctx, posts); err != nil {
+		return nil, err
+	}
+
+	if err := s.withPolls(ctx, posts, viewerID); err != nil {
+		return nil, err
+	}
+
+	return s.withOriginals(ctx, posts, viewerID)
+}
+
+// withOriginals attaches the original post to reposts and quotes. The posts
+// must already be copies, since they are modified in place.
+func (s *postService) withOriginals(ctx context.Context, posts []*model.Post, viewerID string) ([]*model.Post, error) {
+	var originalIDs []string
+	seen := make(map[string]bool)
+	for _, post := range posts {
+		if post.OriginalPostID != nil && !seen[*post.OriginalPostID] {
+			seen[*post.OriginalPostID] = true
+			originalIDs = append(originalIDs, *post.OriginalPostID)
+		}
+	}
+
+	if len(originalIDs) == 0 {
+		return posts, nil
 	}
 
-	// Get posts
-	posts, err := s.repo.GetFeed(ctx, userIDs, limit, offset)
+	originals, err := s.repo.GetVisibleByIDs(ctx, originalIDs, viewerID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Store in cache (short TTL for feeds)
-	s.cache.Set(cacheKey, posts, 1*time.Minute)
+	originals, err = s.withReactionSummaries(ctx, originals, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.withLinkPreviews(ctx, originals); err != nil {
+		return nil, err
+	}
+
+	if err := s.withMedia(ctx, originals); err != nil {
+		return nil, err
+	}
+
+	if err := s.withPolls(ctx, originals, viewerID); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*model.Post, len(originals))
+	for _, original := range originals {
+		byID[original.ID] = original
+	}
+
+	for _, post := range posts {
+		if post.OriginalPostID != nil {
+			post.OriginalPost = byID[*post.OriginalPostID]
+		}
+	}
 
 	return posts, nil
 }
+
+// withReactionSummaries returns copies of the posts with their reaction summaries
+// attached, loaded in one aggregate query. Cached posts are left untouched because
+// the summary includes the viewer's own reaction.
+func (s *postService) withReactionSummaries(ctx context.Context, posts []*model.Post, viewerID string) ([]*model.Post, error) {
+	if len(posts) == 0 {
+		return posts, nil
+	}
+
+	postIDs := make([]string, 0, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+	}
+
+	summaries, err := s.repo.GetReactionSummaries(ctx, postIDs, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Post, 0, len(posts))
+	for _, post := range posts {
+		enriched := *post
+		enriched.ReactionSummary = summaries[post.ID]
+		result = append(result, &enriched)
+	}
+
+	return result, nil
+}
+
+// withLinkPreviews attaches the fetched previews of each post's embedded URLs,
+// in embed order. The posts must already be copies, since they are modified in place.
+func (s *postService) withLinkPreviews(ctx context.Context, posts []*model.Post) error {
+	var urls []string
+	seen := make(map[string]bool)
+	for _, post := range posts {
+		for _, url := range post.EmbedURLs {
+			if !seen[url] {
+				seen[url] = true
+				urls = append(urls, url)
+			}
+		}
+	}
+
+	if len(urls) == 0 {
+		return nil
+	}
+
+	previews, err := s.repo.GetLinkPreviews(ctx, urls)
+	if err != nil {
+		return err
+	}
+
+	for _, post := range posts {
+		post.LinkPreviews = nil
+		for _, url := range post.EmbedURLs {
+			if preview, ok := previews[url]; ok {
+				post.LinkPreviews = append(post.LinkPreviews, preview)
+			}
+		}
+	}
+
+	return nil
+}
+
+// withMedia attaches the uploaded media of each post. The posts must already
+// be copies, since they are modified in place.
+func (s *postService) withMedia(ctx context.Context, posts []*model.Post) error {
+	if len(posts) == 0 {
+		return nil
+	}
+
+	postIDs := make([]string, 0, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+	}
+
+	media, err := s.repo.GetMedia(ctx, postIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, post := range posts {
+		post.Media = media[post.ID]
+	}
+
+	return nil
+}
+
+// uniqueStrings removes repeated values, keeping the first occurrence
+func uniqueStrings(values []string) []string {
+	if len(values) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			result = append(result, value)
+		}
+	}
+
+	return result
+}
+
+// clampPageSize applies the default and maximum page sizes
+func clampPageSize(limit int) int {
+	if limit <= 0 {
+		return 20
+	}
+	if limit > maxPostPageSize {
+		return maxPostPageSize
+	}
+	return limit
+}
+
+// pagePosts trims a result fetched with limit+1 rows and returns the cursor
+// for the next page, or an empty string on the last page
+func pagePosts(posts []*model.Post, limit int) ([]*model.Post, string) {
+	if len(posts) <= limit {
+		return posts, ""
+	}
+
+	posts = posts[:limit]
+	last := posts[len(posts)-1]
+
+	return posts, pagination.NewCursor(last.CreatedAt, last.ID).Encode()
+}
+
+// pageUserPosts is pagePosts for a user's posts, where pinned posts come first
+// and are paged by the time they were pinned
+func pageUserPosts(posts []*model.Post, limit int) ([]*model.Post, string) {
+	if len(posts) <= limit {
+		return posts, ""
+	}
+
+	posts = posts[:limit]
+	last := posts[len(posts)-1]
+
+	if last.PinnedAt != nil {
+		return posts, pagination.NewPinnedCursor(*last.PinnedAt, last.CreatedAt, last.ID).Encode()
+	}
+
+	return posts, pagination.NewCursor(last.CreatedAt, last.ID).Encode()
+}
+
+// timelineVersion returns the cache version of the timelines cached for a
+// user: their own posts and their feed. Bumping it orphans every cached page.
+func (s *postService) timelineVersion(userID string) string {
+	var version string
+	cache.GetInto(s.cache, fmt.Sprintf("timeline_version:%s", userID), &version)
+	return version
+}
+
+// feedVersion returns the cache version of a user's feed: their timeline
+// version, combined with the timeline versions of the followed authors whose
+// posts are read from them. The timeline invalidator leaves the followers of
+// those authors alone, so their writes reach feeds through this version.
+func (s *postService) feedVersion(ctx context.Context, userID string) (string, error) {
+	version := s.timelineVersion(userID)
+
+	// Follows bump the user's timeline version, which refreshes the authors
+	var authorIDs []string
+	cacheKey := fmt.Sprintf("fanout_on_read_authors:%s:%s", userID, version)
+	if !cache.GetInto(s.cache, cacheKey, &authorIDs) {
+		var err error
+		authorIDs, err = s.repo.GetFanoutOnReadAuthors(ctx, userID)
+		if err != nil {
+			return "", err
+		}
+
+		s.cache.Set(cacheKey, authorIDs, 1*time.Minute)
+	}
+
+	if len(authorIDs) == 0 {
+		return version, nil
+	}
+
+	hash := sha256.New()
+	for _, authorID := range authorIDs {
+		fmt.Fprintf(hash, "%s:%s\n", authorID, s.timelineVersion(authorID))
+	}
+
+	return version + "." + hex.EncodeToString(hash.Sum(nil)[:8]), nil
+}
+
+// invalidateTimelines bumps the timeline version of an author, so their next
+// read misses the cache. Their followers' feeds are left to the timeline
+// invalidator, which keeps writes from paying for every follower.
+func (s *postService) invalidateTimelines(authorID string) {
+	setTimelineVersion(s.cache, authorID, newTimelineVersion())
+
+	s.staleMu.Lock()
+	s.staleAuthors[authorID] = true
+	s.staleMu.Unlock()
+}
+
+// InvalidateFollowerTimelines bumps the timeline versions of the followers of
+// every author whose timeline changed since the last run. Authors with more
+// than FanOutMaxFollowers followers whose posts are read from them are
+// skipped, like in fanOut; feeds see their writes through feedVersion instead.
+// Authors that fail are retried on the next run. It returns the number of
+// authors handled.
+//
+// Pending authors are kept in memory, so those written since the last run are
+// lost when the process stops. Their followers' feeds are then refreshed when
+// their cached pages expire: a minute for the latest feed, two for top feeds.
+func (s *postService) InvalidateFollowerTimelines(ctx context.Context) (int, error) {
+	s.staleMu.Lock()
+	authors := s.staleAuthors
+	s.staleAuthors = make(map[string]bool)
+	s.staleMu.Unlock()
+
+	var lastErr error
+	handled := 0
+	for authorID := range authors {
+		if err := s.invalidateFollowerTimelines(ctx, authorID); err != nil {
+			s.logger.Warn().Err(err).Str("user_id", authorID).Msg("Failed to invalidate follower timelines")
+			lastErr = err
+
+			s.staleMu.Lock()
+			s.staleAuthors[authorID] = true
+			s.staleMu.Unlock()
+			continue
+		}
+		handled++
+	}
+
+	return handled, lastErr
+}
+
+// invalidateFollowerTimelines bumps the timeline versions of an author's
+// followers, unless there are too many of them and feeds already follow the
+// author's own version
+func (s *postService) invalidateFollowerTimelines(ctx context.Context, authorID string) error {
+	stats, err := s.userService.GetProfileStats(ctx, authorID)
+	if err != nil {
+		if apperrors.Is(err, apperrors.ErrTypeNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	if stats.FollowerCount > s.config.FanOutMaxFollowers {
+		onRead, err := s.repo.HasFanoutOnReadPosts(ctx, authorID)
+		if err != nil {
+			return err
+		}
+		if onRead {
+			return nil
+		}
+	}
+
+	version := newTimelineVersion()
+	cursor := ""
+	for {
+		followers, nextCursor, err := s.userService.GetFollowers(ctx, authorID, "", cursor, maxPostPageSize, 0)
+		if err != nil {
+			return err
+		}
+
+		for _, follower := range followers {
+			setTimelineVersion(s.cache, follower.ID, version)
+		}
+
+		if nextCursor == "" {
+			return nil
+		}
+		cursor = nextCursor
+	}
+}
+
+// InvalidatePost drops every cached copy of a post changed outside the post
+// service, such as one hidden by a moderator, including the timelines of its
+// author and their followers. Such changes are rare, so followers are not
+// left to the timeline invalidator.
+func (s *postService) InvalidatePost(ctx context.Context, id string, authorID string) {
+	s.cache.Delete(fmt.Sprintf("post:%s", id))
+	setTimelineVersion(s.cache, authorID, newTimelineVersion())
+
+	if err := s.invalidateFollowerTimelines(ctx, authorID); err != nil {
+		s.logger.Warn().Err(err).Str("user_id", authorID).Msg("Failed to invalidate follower timelines")
+		s.invalidateTimelines(authorID)
+	}
+}
+
+// fanOut puts a newly published post on the home timelines it belongs on. The
+// post is already saved, so failures are logged rather than returned.
+func (s *postService) fanOut(ctx context.Context, post *model.Post) {
+	fannedOut, err := s.repo.FanOut(ctx, post.ID, s.config.FanOutMaxFollowers)
+	if err != nil {
+		s.logger.Error().Err(err).Str("post_id", post.ID).Msg("Failed to fan out post")
+		return
+	}
+
+	if !fannedOut {
+		s.logger.Debug().Str("post_id", post.ID).Msg("Post will be read from its author's timeline")
+	}
+}
+
+// setTimelineVersion stores a user's timeline version
+func setTimelineVersion(c cache.Cache, userID string, version string) {
+	// The version only has to outlive the timelines cached under it
+	c.Set(fmt.Sprintf("timeline_version:%s", userID), version, time.Hour)
+}
+
+// newTimelineVersion returns a version that differs from any issued before
+func newTimelineVersion() string {
+	return strconv.FormatInt(time.Now().UnixNano(), 36)
+}
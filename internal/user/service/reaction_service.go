@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/platform/cache"
+	"github.com/PeterM45/perfolio-api/internal/user/interfaces"
+	"github.com/PeterM45/perfolio-api/internal/user/repository"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/PeterM45/perfolio-api/pkg/logger"
+	"github.com/PeterM45/perfolio-api/pkg/validator"
+	"github.com/google/uuid"
+)
+
+type reactionService struct {
+	repo        repository.ReactionRepository
+	postService interfaces.PostService
+	cache       cache.Cache
+	validator   validator.Validator
+	logger      logger.Logger
+}
+
+// NewReactionService creates a new ReactionService
+func NewReactionService(
+	repo repository.ReactionRepository,
+	postService interfaces.PostService,
+	cache cache.Cache,
+	logger logger.Logger,
+) interfaces.ReactionService {
+	return &reactionService{
+		repo:        repo,
+		postService: postService,
+		cache:       cache,
+		validator:   validator.NewValidator(),
+		logger:      logger,
+	}
+}
+
+// AddReaction adds a reaction to a post
+func (s *reactionService) AddReaction(ctx context.Context, userID string, req *model.ReactionRequest) (*model.Reaction, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, apperrors.BadRequest(err.Error())
+	}
+
+	if err := s.checkPostAccess(ctx, req.PostID, userID); err != nil {
+		return nil, err
+	}
+
+	reaction := &model.Reaction{
+		ID:     uuid.New().String(),
+		PostID: req.PostID,
+		UserID: userID,
+		Type:   req.Type,
+	}
+
+	if err := s.repo.Create(ctx, reaction); err != nil {
+		return nil, err
+	}
+
+	return reaction, nil
+}
+
+// UpdateReaction changes the type of a user's existing reaction
+func (s *reactionService) UpdateReaction(ctx context.Context, userID string, req *model.ReactionRequest) (*model.Reaction, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, apperrors.BadRequest(err.Error())
+	}
+
+	if err := s.checkPostAccess(ctx, req.PostID, userID); err != nil {
+		return nil, err
+	}
+
+	reaction, err := s.repo.UpdateType(ctx, req.PostID, userID, req.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	return reaction, nil
+}
+
+// RemoveReaction removes a user's reaction from a post
+func (s *reactionService) RemoveReaction(ctx context.Context, postID string, userID string) error {
+	if postID == "" {
+		return apperrors.BadRequest("post ID cannot be empty")
+	}
+
+	if err := s.repo.Delete(ctx, postID, userID); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetPostReactions lists the reactions on a post the viewer can see
+func (s *reactionService) GetPostReactions(ctx context.Context, postID string, viewerID string, limit, offset int) ([]*model.Reaction, error) {
+	if postID == "" {
+		return nil, apperrors.BadRequest("post ID cannot be empty")
+	}
+
+	if err := s.checkPostAccess(ctx, postID, viewerID); err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetByPostID(ctx, postID, limit, offset)
+}
+
+// checkPostAccess verifies the post exists and the user is allowed to see it
+func (s *reactionService) checkPostAccess(ctx context.Context, postID string, userID string) error {
+	_, err := s.postService.GetPostByID(ctx, postID, userID)
+	return err
+}
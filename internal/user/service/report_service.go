@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/platform/cache"
+	"github.com/PeterM45/perfolio-api/internal/user/interfaces"
+	"github.com/PeterM45/perfolio-api/internal/user/repository"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/PeterM45/perfolio-api/pkg/logger"
+	"github.com/PeterM45/perfolio-api/pkg/validator"
+	"github.com/google/uuid"
+)
+
+type reportService struct {
+	repo        repository.ReportRepository
+	postService interfaces.PostService
+	userService interfaces.UserService
+	cache       cache.Cache
+	validator   validator.Validator
+	logger      logger.Logger
+}
+
+// NewReportService creates a new ReportService
+func NewReportService(
+	repo repository.ReportRepository,
+	postService interfaces.PostService,
+	userService interfaces.UserService,
+	cache cache.Cache,
+	logger logger.Logger,
+) interfaces.ReportService {
+	return &reportService{
+		repo:        repo,
+		postService: postService,
+		userService: userService,
+		cache:       cache,
+		validator:   validator.NewValidator(),
+		logger:      logger,
+	}
+}
+
+// CreateReport files a report about a post, comment or user the reporter can see
+func (s *reportService) CreateReport(ctx context.Context, reporterID string, req *model.CreateReportRequest) (*model.Report, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, apperrors.BadRequest(err.Error())
+	}
+
+	targetUserID, err := s.targetUser(ctx, req.TargetType, req.TargetID, reporterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if targetUserID == reporterID {
+		return nil, apperrors.BadRequest("you cannot report yourself or your own content")
+	}
+
+	report := &model.Report{
+		ID:           uuid.New().String(),
+		ReporterID:   &reporterID,
+		TargetType:   req.TargetType,
+		TargetID:     req.TargetID,
+		TargetUserID: targetUserID,
+		Reason:       req.Reason,
+		Details:      req.Details,
+	}
+
+	if err := s.repo.Create(ctx, report); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+// targetUser returns the author of the reported content, or the reported user.
+// Content the reporter cannot see is reported missing.
+func (s *reportService) targetUser(ctx context.Context, targetType model.ReportTargetType, targetID string, reporterID string) (string, error) {
+	switch targetType {
+	case model.ReportTargetPost:
+		post, err := s.postService.GetPostByID(ctx, targetID, reporterID)
+		if err != nil {
+			return "", err
+		}
+		return post.UserID, nil
+	case model.ReportTargetComment:
+		postID, userID, err := s.repo.GetCommentTarget(ctx, targetID)
+		if err != nil {
+			return "", err
+		}
+		if _, err := s.postService.GetPostByID(ctx, postID, reporterID); err != nil {
+			if apperrors.Is(err, apperrors.ErrTypeNotFound) {
+				return "", apperrors.NotFound(fmt.Sprintf("comment: %s", targetID))
+			}
+			return "", err
+		}
+		return userID, nil
+	case model.ReportTargetUser:
+		user, err := s.userService.GetUserByID(ctx, targetID)
+		if err != nil {
+			return "", err
+		}
+		return user.ID, nil
+	default:
+		return "", apperrors.BadRequest(fmt.Sprintf("unknown report target: %s", targetType))
+	}
+}
+
+// GetReport retrieves a report for a moderator
+func (s *reportService) GetReport(ctx context.Context, id string) (*model.Report, error) {
+	if id == "" {
+		return nil, apperrors.BadRequest("report ID cannot be empty")
+	}
+
+	return s.repo.GetByID(ctx, id)
+}
+
+// GetReportQueue lists reports in a status, oldest first. Open reports are
+// listed when no status is given.
+func (s *reportService) GetReportQueue(ctx context.Context, status model.ReportStatus, limit, offset int) ([]*model.Report, error) {
+	switch status {
+	case "":
+		status = model.ReportStatusOpen
+	case model.ReportStatusOpen, model.ReportStatusActioned, model.ReportStatusDismissed:
+	default:
+		return nil, apperrors.BadRequest(fmt.Sprintf("unknown report status: %s", status))
+	}
+
+	return s.repo.GetByStatus(ctx, status, clampPageSize(limit), offset)
+}
+
+// ResolveReport applies a moderator's action to the reported target and closes
+// every open report on it. The action is recorded in the audit trail.
+func (s *reportService) ResolveReport(ctx context.Context, id string, moderatorID string, req *model.ModerationActionRequest) (*model.ModerationAction, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, apperrors.BadRequest(err.Error())
+	}
+
+	report, err := s.GetReport(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Action == model.ModerationActionHide && report.TargetType == model.ReportTargetUser {
+		return nil, apperrors.BadRequest("users cannot be hidden; suspend the account instead")
+	}
+
+	// Load the user up front so their cached copies can be found afterwards
+	var suspended *model.User
+	if req.Action == model.ModerationActionSuspend {
+		suspended, err = s.userService.GetUserByID(ctx, report.TargetUserID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	action := &model.ModerationAction{
+		ID:          uuid.New().String(),
+		ModeratorID: moderatorID,
+		Action:      req.Action,
+		ReportID:    &report.ID,
+		Note:        req.Note,
+	}
+
+	if err := s.repo.Resolve(ctx, action); err != nil {
+		return nil, err
+	}
+
+	// The hidden post leaves cached timelines right away
+	if req.Action == model.ModerationActionHide && action.TargetType == model.ReportTargetPost {
+		s.postService.InvalidatePost(ctx, action.TargetID, action.TargetUserID)
+	}
+
+	if suspended != nil {
+		s.cache.Delete(fmt.Sprintf("user:%s", suspended.ID))
+		s.cache.Delete(fmt.Sprintf("user:username:%s", suspended.Username))
+		s.cache.Delete(fmt.Sprintf("user:email:%s", suspended.Email))
+	}
+
+	s.logger.Info().
+		Str("moderator_id", moderatorID).
+		Str("report_id", report.ID).
+		Str("action", string(action.Action)).
+		Str("target_type", string(action.TargetType)).
+		Str("target_id", action.TargetID).
+		Msg("Resolved report")
+
+	return action, nil
+}
+
+// GetModerationActions lists the moderation audit trail, newest first
+func (s *reportService) GetModerationActions(ctx context.Context, limit, offset int) ([]*model.ModerationAction, error) {
+	return s.repo.GetActions(ctx, clampPageSize(limit), offset)
+}
@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/platform/moderation"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/google/uuid"
+)
+
+// Repost shares a post with the user's followers. With a quote the share
+// becomes a quote post carrying the user's own comment.
+func (s *postService) Repost(ctx context.Context, originalID string, userID string, req *model.RepostRequest) (*model.Post, error) {
+	if err := s.validator.Validate(req); err != nil {
+		return nil, apperrors.BadRequest(err.Error())
+	}
+
+	// Verify user exists
+	user, err := s.userService.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsActive {
+		return nil, apperrors.Forbidden("your account is suspended")
+	}
+
+	original, err := s.getPost(ctx, originalID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkView(ctx, original, userID); err != nil {
+		return nil, err
+	}
+
+	// Sharing a plain repost shares the post it points at
+	if original.Kind == model.PostKindRepost {
+		if original.OriginalPostID == nil {
+			return nil, apperrors.NotFound(fmt.Sprintf("post: %s", originalID))
+		}
+
+		original, err = s.getPost(ctx, *original.OriginalPostID)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := s.checkView(ctx, original, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	// Reposts are shown to the reposter's audience, which may not be allowed
+	// to see anything but public posts
+	if original.Visibility != model.VisibilityPublic {
+		return nil, apperrors.BadRequest("only public posts can be reposted")
+	}
+
+	post := &model.Post{
+		ID:             uuid.New().String(),
+		UserID:         userID,
+		Visibility:     model.VisibilityPublic, // Default visibility
+		Status:         model.PostStatusPublished,
+		Kind:           model.PostKindRepost,
+		OriginalPostID: &original.ID,
+	}
+
+	var verdict moderation.Verdict
+	if req.Quote != "" {
+		verdict, err = s.checkContent(ctx, userID, req.Quote, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		mentions, err := s.resolveMentions(ctx, req.Quote)
+		if err != nil {
+			return nil, err
+		}
+
+		post.Kind = model.PostKindQuote
+		post.Content = req.Quote
+		post.Mentions = mentions
+	}
+
+	if err := s.repo.Create(ctx, post); err != nil {
+		return nil, err
+	}
+
+	if req.Quote != "" {
+		s.recordContent(ctx, userID, req.Quote, nil)
+	}
+	s.content.FlagForReview(ctx, verdict, model.ReportTargetPost, post.ID, userID)
+
+	// The original's repost count changed
+	s.cache.Delete(fmt.Sprintf("post:%s", original.ID))
+
+	s.fanOut(ctx, post)
+	s.invalidateTimelines(userID)
+
+	post.OriginalPost = original
+
+	return post, nil
+}
+
+// Unrepost removes the user's plain repost of a post. Quote posts are deleted
+// like any other post.
+func (s *postService) Unrepost(ctx context.Context, originalID string, userID string) error {
+	if originalID == "" {
+		return apperrors.BadRequest("post ID cannot be empty")
+	}
+
+	repost, err := s.repo.GetRepost(ctx, originalID, userID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.repo.Delete(ctx, repost.ID); err != nil {
+		return err
+	}
+
+	s.cache.Delete(fmt.Sprintf("post:%s", repost.ID))
+	s.cache.Delete(fmt.Sprintf("post:%s", originalID))
+
+	s.invalidateTimelines(userID)
+
+	return nil
+}
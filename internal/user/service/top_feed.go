@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/platform/cache"
+	"github.com/PeterM45/perfolio-api/internal/platform/ranking"
+	"github.com/PeterM45/perfolio-api/pkg/pagination"
+)
+
+// rankedPost is an entry of a cached ranking of posts
+type rankedPost struct {
+	ID        string    `json:"id"`
+	AuthorID  string    `json:"authorId"`
+	Score     float64   `json:"score"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// getTopFeed lists the recent posts of a user's home timeline best first.
+// Pages continue after the score, creation time and ID in the cursor.
+func (s *postService) getTopFeed(ctx context.Context, userID string, cursor *pagination.Cursor, limit int) ([]*model.Post, string, error) {
+	ranked, err := s.rankFeed(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ids, nextCursor := pageRanked(ranked, cursor, limit)
+
+	posts, err := s.GetPostsByIDs(ctx, ids, userID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return posts, nextCursor, nil
+}
+
+// rankFeed scores the recent posts of a user's home timeline and returns them
+// best first. The ranking is cached under the user's feed version, so it is
+// rebuilt when a post reaches their timeline.
+func (s *postService) rankFeed(ctx context.Context, userID string) ([]rankedPost, error) {
+	version, err := s.feedVersion(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ranked []rankedPost
+	cacheKey := fmt.Sprintf("top_feed:%s:%s", userID, version)
+	if cache.GetInto(s.cache, cacheKey, &ranked) {
+		s.logger.Debug().Str("user_id", userID).Msg("Top feed found in cache")
+		return ranked, nil
+	}
+
+	now := time.Now()
+	posts, err := s.repo.GetFeed(ctx, userID, nil, s.config.TopCandidates, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	since := now.Add(-s.config.TopWindow)
+	authorIDs := make([]string, 0, len(posts))
+	recent := 0
+	for _, post := range posts {
+		if post.CreatedAt.Before(since) {
+			break
+		}
+		authorIDs = append(authorIDs, post.UserID)
+		recent++
+	}
+	posts = posts[:recent]
+
+	affinities, err := s.repo.GetAffinities(ctx, userID, uniqueStrings(authorIDs), now.Add(-s.config.AffinityWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	ranked, err = s.rankPosts(ctx, posts, affinities, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Scores drift as posts age and gather reactions, so rankings are kept briefly
+	s.cache.Set(cacheKey, ranked, 2*time.Minute)
+
+	return ranked, nil
+}
+
+// rankPosts scores posts with the feed strategy from their reactions and
+// comments and the viewer's affinity for their authors, and returns them
+// best first. Affinities may be empty for rankings that have no viewer.
+func (s *postService) rankPosts(ctx context.Context, posts []*model.Post, affinities map[string]int, now time.Time) ([]rankedPost, error) {
+	postIDs := make([]string, 0, len(posts))
+	for _, post := range posts {
+		postIDs = append(postIDs, post.ID)
+	}
+
+	summaries, err := s.repo.GetReactionSummaries(ctx, postIDs, "")
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := s.repo.GetCommentCounts(ctx, postIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates := make([]*ranking.Candidate, 0, len(posts))
+	for _, post := range posts {
+		reactions := make(map[string]int)
+		if summary := summaries[post.ID]; summary != nil {
+			for reactionType, count := range summary.Counts {
+				reactions[string(reactionType)] = count
+			}
+		}
+
+		candidates = append(candidates, &ranking.Candidate{
+			PostID:    post.ID,
+			AuthorID:  post.UserID,
+			CreatedAt: post.CreatedAt,
+			Reactions: reactions,
+			Comments:  comments[post.ID],
+			Affinity:  affinities[post.UserID],
+		})
+	}
+
+	ranked := make([]rankedPost, 0, len(candidates))
+	for _, scored := range ranking.Rank(s.config.Strategy, candidates, now) {
+		ranked = append(ranked, rankedPost{
+			ID:        scored.PostID,
+			AuthorID:  scored.AuthorID,
+			Score:     scored.Score,
+			CreatedAt: scored.CreatedAt,
+		})
+	}
+
+	return ranked, nil
+}
+
+// pageRanked returns the IDs of up to limit posts of a ranking that follow
+// the cursor, and the cursor for the next page or an empty string on the last
+// page
+func pageRanked(ranked []rankedPost, cursor *pagination.Cursor, limit int) ([]string, string) {
+	start := 0
+	if cursor != nil {
+		start = sort.Search(len(ranked), func(i int) bool {
+			return ranking.Before(cursor.Rank, cursor.CreatedAt, cursor.ID, ranked[i].Score, ranked[i].CreatedAt, ranked[i].ID)
+		})
+	}
+
+	page := ranked[start:]
+	var nextCursor string
+	if len(page) > limit {
+		page = page[:limit]
+		last := page[len(page)-1]
+		nextCursor = pagination.NewRankedCursor(last.Score, last.CreatedAt, last.ID).Encode()
+	}
+
+	ids := make([]string, 0, len(page))
+	for _, entry := range page {
+		ids = append(ids, entry.ID)
+	}
+
+	return ids, nextCursor
+}
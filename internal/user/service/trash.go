@@ -0,0 +1,67 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+)
+
+// GetTrash gets the user's deleted posts that can still be restored
+func (s *postService) GetTrash(ctx context.Context, userID string, limit, offset int) ([]*model.Post, error) {
+	if userID == "" {
+		return nil, apperrors.BadRequest("user ID cannot be empty")
+	}
+
+	deletedAfter := time.Now().UTC().Add(-model.TrashRetention)
+
+	posts, err := s.repo.GetDeletedByUserID(ctx, userID, deletedAfter, clampPageSize(limit), offset)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.enrichPosts(ctx, posts, userID)
+}
+
+// RestorePost takes a post out of the user's trash. Posts deleted longer ago
+// than the trash retention can no longer be restored. Restored posts stay unpinned.
+func (s *postService) RestorePost(ctx context.Context, id string, userID string) (*model.Post, error) {
+	if id == "" {
+		return nil, apperrors.BadRequest("post ID cannot be empty")
+	}
+
+	deletedAfter := time.Now().UTC().Add(-model.TrashRetention)
+
+	if err := s.repo.Restore(ctx, id, userID, deletedAfter); err != nil {
+		return nil, err
+	}
+
+	s.cache.Delete(fmt.Sprintf("post:%s", id))
+
+	s.invalidateTimelines(userID)
+
+	return s.GetPostByID(ctx, id, userID)
+}
+
+// PurgeDeletedPosts permanently removes up to limit posts that have been in
+// the trash longer than the retention period. It returns the number purged.
+func (s *postService) PurgeDeletedPosts(ctx context.Context, limit int) (int, error) {
+	deletedBefore := time.Now().UTC().Add(-model.TrashRetention)
+
+	posts, err := s.repo.PurgeDeleted(ctx, deletedBefore, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, post := range posts {
+		s.cache.Delete(fmt.Sprintf("post:%s", post.ID))
+	}
+
+	if len(posts) > 0 {
+		s.logger.Info().Int("count", len(posts)).Msg("Purged deleted posts")
+	}
+
+	return len(posts), nil
+}
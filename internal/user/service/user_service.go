@@ -7,25 +7,29 @@ import (
 
 	"github.com/PeterM45/perfolio-api/internal/common/model"
 	"github.com/PeterM45/perfolio-api/internal/platform/cache"
+	"github.com/PeterM45/perfolio-api/internal/platform/moderation"
 	"github.com/PeterM45/perfolio-api/internal/user/interfaces"
 	"github.com/PeterM45/perfolio-api/internal/user/repository"
 	"github.com/PeterM45/perfolio-api/pkg/apperrors"
 	"github.com/PeterM45/perfolio-api/pkg/logger"
+	"github.com/PeterM45/perfolio-api/pkg/pagination"
 	"github.com/PeterM45/perfolio-api/pkg/validator"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type userService struct {
 	repo      repository.UserRepository
+	content   *ContentChecker
 	cache     cache.Cache
 	validator validator.Validator
 	logger    logger.Logger
 }
 
 // NewUserService creates a new UserService
-func NewUserService(repo repository.UserRepository, cache cache.Cache, logger logger.Logger) interfaces.UserService {
+func NewUserService(repo repository.UserRepository, content *ContentChecker, cache cache.Cache, logger logger.Logger) interfaces.UserService {
 	return &userService{
 		repo:      repo,
+		content:   content,
 		cache:     cache,
 		validator: validator.NewValidator(),
 		logger:    logger,
@@ -199,6 +203,19 @@ func (s *userService) UpdateUser(ctx context.Context, id string, req *model.Upda
 		updates["lastName"] = *req.LastName
 	}
 
+	// An unchanged bio was checked when it was first saved
+	var bioVerdict moderation.Verdict
+	if req.Bio != nil && (existingUser.Bio == nil || *req.Bio != *existingUser.Bio) {
+		bioVerdict, err = s.content.Check(ctx, &moderation.Content{
+			UserID: id,
+			Kind:   moderation.KindBio,
+			Text:   *req.Bio,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if req.Bio != nil {
 		updates["bio"] = *req.Bio
 	}
@@ -249,6 +266,8 @@ func (s *userService) UpdateUser(ctx context.Context, id string, req *model.Upda
 			return nil, err
 		}
 
+		s.content.FlagForReview(ctx, bioVerdict, model.ReportTargetUser, id, id)
+
 		// Invalidate cache
 		s.cache.Delete(fmt.Sprintf("user:%s", id))
 		if req.Username != nil {
@@ -317,8 +336,8 @@ func (s *userService) VerifyPassword(ctx context.Context, id string, password st
 	return err == nil, nil
 }
 
-// SearchUsers searches for users
-func (s *userService) SearchUsers(ctx context.Context, query string, limit int) ([]*model.User, error) {
+// SearchUsers searches for users, leaving out users in a block with the viewer
+func (s *userService) SearchUsers(ctx context.Context, query string, viewerID string, limit int) ([]*model.User, error) {
 	if query == "" {
 		return nil, apperrors.BadRequest("search query cannot be empty")
 	}
@@ -327,7 +346,7 @@ func (s *userService) SearchUsers(ctx context.Context, query string, limit int)
 		limit = 10
 	}
 
-	return s.repo.Search(ctx, query, limit)
+	return s.repo.Search(ctx, query, viewerID, limit)
 }
 
 // ToggleFollow toggles a follow relationship
@@ -350,6 +369,17 @@ func (s *userService) ToggleFollow(ctx context.Context, req *model.FollowRequest
 		return err
 	}
 
+	// Neither side of a block may follow the other
+	if req.Action == "follow" {
+		blocked, err := s.IsBlocked(ctx, followerID, req.FollowingID)
+		if err != nil {
+			return err
+		}
+		if blocked {
+			return apperrors.Forbidden("cannot follow this user")
+		}
+	}
+
 	// Perform requested action
 	var err error
 	if req.Action == "follow" {
@@ -367,6 +397,9 @@ func (s *userService) ToggleFollow(ctx context.Context, req *model.FollowRequest
 	s.cache.Delete(fmt.Sprintf("follower_count:%s", req.FollowingID))
 	s.cache.Delete(fmt.Sprintf("following_count:%s", followerID))
 
+	// The follower's timeline gained or lost the other user's posts
+	setTimelineVersion(s.cache, followerID, newTimelineVersion())
+
 	return nil
 }
 
@@ -432,22 +465,74 @@ func (s *userService) GetProfileStats(ctx context.Context, userID string) (*mode
 	}, nil
 }
 
-// GetFollowers gets users who follow the given user
-func (s *userService) GetFollowers(ctx context.Context, userID string, limit, offset int) ([]*model.User, error) {
-	// Check if user exists
-	if _, err := s.GetUserByID(ctx, userID); err != nil {
-		return nil, err
+// GetFollowers gets users who follow the given user, most recent followers
+// first, with the cursor for the next page. Users in a block with the viewer
+// are left out.
+func (s *userService) GetFollowers(ctx context.Context, userID string, viewerID string, cursorToken string, limit, offset int) ([]*model.User, string, error) {
+	cursor, err := pagination.Decode(cursorToken)
+	if err != nil {
+		return nil, "", apperrors.BadRequest(err.Error())
+	}
+
+	// Check if user exists and is visible to the viewer
+	if _, err := s.GetProfile(ctx, userID, viewerID); err != nil {
+		return nil, "", err
+	}
+
+	if limit <= 0 {
+		limit = 10 // Default limit
 	}
 
-	return s.repo.GetFollowers(ctx, userID, limit, offset)
+	// Fetch one extra row to know whether another page exists
+	users, err := s.repo.GetFollowers(ctx, userID, viewerID, cursor, limit+1, offset)
+	if err != nil {
+		return nil, "", err
+	}
+
+	users, nextCursor := pageFollows(users, limit)
+	return users, nextCursor, nil
 }
 
-// GetFollowing gets users the given user follows
-func (s *userService) GetFollowing(ctx context.Context, userID string, limit, offset int) ([]*model.User, error) {
-	// Check if user exists
-	if _, err := s.GetUserByID(ctx, userID); err != nil {
-		return nil, err
+// GetFollowing gets users the given user follows, most recently followed
+// first, with the cursor for the next page. Users in a block with the viewer
+// are left out.
+func (s *userService) GetFollowing(ctx context.Context, userID string, viewerID string, cursorToken string, limit, offset int) ([]*model.User, string, error) {
+	cursor, err := pagination.Decode(cursorToken)
+	if err != nil {
+		return nil, "", apperrors.BadRequest(err.Error())
+	}
+
+	// Check if user exists and is visible to the viewer
+	if _, err := s.GetProfile(ctx, userID, viewerID); err != nil {
+		return nil, "", err
+	}
+
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	// Fetch one extra row to know whether another page exists
+	users, err := s.repo.GetFollowing(ctx, userID, viewerID, cursor, limit+1, offset)
+	if err != nil {
+		return nil, "", err
+	}
+
+	users, nextCursor := pageFollows(users, limit)
+	return users, nextCursor, nil
+}
+
+// pageFollows trims a follow list fetched with limit+1 rows and returns the
+// cursor for the next page, or an empty string on the last page
+func pageFollows(users []*model.User, limit int) ([]*model.User, string) {
+	if len(users) <= limit {
+		return users, ""
+	}
+
+	users = users[:limit]
+	last := users[len(users)-1]
+	if last.FollowedAt == nil {
+		return users, ""
 	}
 
-	return s.repo.GetFollowing(ctx, userID, limit, offset)
+	return users, pagination.NewCursor(*last.FollowedAt, last.ID).Encode()
 }
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+)
+
+// canView reports whether the viewer may see a post. An empty viewerID is an
+// anonymous visitor. The post list queries apply the same rule in SQL (see
+// visibleToViewer in the post repository); keep the two in sync.
+func (s *postService) canView(ctx context.Context, post *model.Post, viewerID string) (bool, error) {
+	// Authors can always see their own posts
+	if viewerID != "" && post.UserID == viewerID {
+		return true, nil
+	}
+
+	// Drafts and scheduled posts are only visible to their author
+	if post.Status != model.PostStatusPublished {
+		return false, nil
+	}
+
+	// So are posts hidden by a moderator
+	if post.HiddenAt != nil {
+		return false, nil
+	}
+
+	// Users in a block never see each other's posts
+	blocked, err := s.userService.IsBlocked(ctx, viewerID, post.UserID)
+	if err != nil {
+		return false, err
+	}
+	if blocked {
+		return false, nil
+	}
+
+	switch post.Visibility {
+	case model.VisibilityPublic:
+		return true, nil
+	case model.VisibilityFollowers:
+		if viewerID == "" {
+			return false, nil
+		}
+		return s.userService.IsFollowing(ctx, viewerID, post.UserID)
+	default:
+		return false, nil
+	}
+}
+
+// checkView returns a not found error when the viewer may not see the post,
+// so hidden posts are indistinguishable from missing ones
+func (s *postService) checkView(ctx context.Context, post *model.Post, viewerID string) error {
+	ok, err := s.canView(ctx, post, viewerID)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return apperrors.NotFound(fmt.Sprintf("post: %s", post.ID))
+	}
+
+	return nil
+}
+
+// audience classifies the viewer relative to an author. Every viewer in the same
+// class sees the same posts from that author, which lets timelines be cached
+// per class rather than per viewer.
+func (s *postService) audience(ctx context.Context, authorID string, viewerID string) (string, error) {
+	if viewerID == "" {
+		return "public", nil
+	}
+
+	if viewerID == authorID {
+		return "self", nil
+	}
+
+	following, err := s.userService.IsFollowing(ctx, viewerID, authorID)
+	if err != nil {
+		return "", err
+	}
+
+	if following {
+		return "followers", nil
+	}
+
+	return "public", nil
+}
@@ -3,10 +3,12 @@ package service
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/PeterM45/perfolio-api/internal/common/model"
 	"github.com/PeterM45/perfolio-api/internal/platform/cache"
+	"github.com/PeterM45/perfolio-api/internal/platform/moderation"
 	"github.com/PeterM45/perfolio-api/internal/user/interfaces"
 	"github.com/PeterM45/perfolio-api/internal/user/repository"
 	"github.com/PeterM45/perfolio-api/internal/widgets"
@@ -19,6 +21,7 @@ import (
 type widgetService struct {
 	repo        repository.WidgetRepository
 	userService interfaces.UserService
+	content     *ContentChecker
 	cache       cache.Cache
 	validator   validator.Validator
 	logger      logger.Logger
@@ -28,12 +31,14 @@ type widgetService struct {
 func NewWidgetService(
 	repo repository.WidgetRepository,
 	userService interfaces.UserService,
+	content *ContentChecker,
 	cache cache.Cache,
 	logger logger.Logger,
 ) interfaces.WidgetService {
 	return &widgetService{
 		repo:        repo,
 		userService: userService,
+		content:     content,
 		cache:       cache,
 		validator:   validator.NewValidator(),
 		logger:      logger,
@@ -122,6 +127,29 @@ func (s *widgetService) DeleteWidget(ctx context.Context, id string, userID stri
 	return nil
 }
 
+// checkContent runs the content filters over the text in widget settings and
+// a widget's display name. Widgets belong to the profile, so flagged widgets
+// are reported as their owner.
+func (s *widgetService) checkContent(ctx context.Context, userID string, settings *string, displayName *string) (moderation.Verdict, error) {
+	var parts []string
+	if settings != nil && *settings != "" {
+		parts = append(parts, widgets.SettingsText(*settings))
+	}
+	if displayName != nil && *displayName != "" {
+		parts = append(parts, *displayName)
+	}
+
+	if len(parts) == 0 {
+		return moderation.Verdict{Action: moderation.Allow}, nil
+	}
+
+	return s.content.Check(ctx, &moderation.Content{
+		UserID: userID,
+		Kind:   moderation.KindWidget,
+		Text:   strings.Join(parts, "\n"),
+	})
+}
+
 // GetWidgetTypes returns all available widget types
 func (s *widgetService) GetWidgetTypes(ctx context.Context) (map[string]model.WidgetType, error) {
 	registryTypes := widgets.GetWidgetTypes()
@@ -183,6 +211,11 @@ func (s *widgetService) CreateWidget(ctx context.Context, userID string, req *mo
 		return nil, err
 	}
 
+	verdict, err := s.checkContent(ctx, userID, &req.Settings, &req.DisplayName)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get widget type config for defaults
 	config, _ := widgets.GetWidgetTypeConfig(req.Type)
 
@@ -217,6 +250,8 @@ func (s *widgetService) CreateWidget(ctx context.Context, userID string, req *mo
 		return nil, err
 	}
 
+	s.content.FlagForReview(ctx, verdict, model.ReportTargetUser, userID, userID)
+
 	// Invalidate cache
 	s.cache.Delete(fmt.Sprintf("user_widgets:%s", userID))
 
@@ -281,6 +316,12 @@ func (s *widgetService) UpdateWidget(ctx context.Context, id string, userID stri
 		}
 	}
 
+	// Check before changing the widget, which may be shared with the cache
+	verdict, err := s.checkContent(ctx, userID, req.Settings, req.DisplayName)
+	if err != nil {
+		return nil, err
+	}
+
 	// Update widget fields
 	if req.Component != nil {
 		widget.Component = *req.Component
@@ -322,6 +363,8 @@ func (s *widgetService) UpdateWidget(ctx context.Context, id string, userID stri
 		return nil, err
 	}
 
+	s.content.FlagForReview(ctx, verdict, model.ReportTargetUser, userID, userID)
+
 	// Invalidate cache
 	s.cache.Delete(fmt.Sprintf("widget:%s", id))
 	s.cache.Delete(fmt.Sprintf("user_widgets:%s", userID))
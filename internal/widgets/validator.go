@@ -3,6 +3,8 @@ package widgets
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/xeipuuv/gojsonschema"
 )
@@ -58,3 +60,41 @@ func ValidateCustomization(widgetType string, customization string) bool {
 
 	return false
 }
+
+// SettingsText returns the text users entered in widget settings, one value
+// per line, so it can be checked like any other user-submitted text. Settings
+// that are not valid JSON yield no text.
+func SettingsText(settings string) string {
+	var value interface{}
+	if err := json.Unmarshal([]byte(settings), &value); err != nil {
+		return ""
+	}
+
+	var lines []string
+	collectStrings(value, &lines)
+
+	return strings.Join(lines, "\n")
+}
+
+// collectStrings appends every string in a decoded JSON value to lines,
+// visiting object keys in sorted order
+func collectStrings(value interface{}, lines *[]string) {
+	switch v := value.(type) {
+	case string:
+		*lines = append(*lines, v)
+	case []interface{}:
+		for _, item := range v {
+			collectStrings(item, lines)
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			collectStrings(v[key], lines)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package apperrors
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 )
@@ -22,6 +23,7 @@ const (
 type Error struct {
 	errorType ErrorType
 	message   string
+	details   []string
 }
 
 // Error returns the error message
@@ -34,6 +36,18 @@ func (e *Error) Type() ErrorType {
 	return e.errorType
 }
 
+// Details returns the details attached to the error, if any
+func (e *Error) Details() []string {
+	return e.details
+}
+
+// WithDetails attaches details to the error, such as the individual reasons
+// behind a rejection, and returns it
+func (e *Error) WithDetails(details ...string) *Error {
+	e.details = append(e.details, details...)
+	return e
+}
+
 // Status returns the HTTP status code
 func (e *Error) Status() int {
 	switch e.errorType {
@@ -107,3 +121,9 @@ func Wrap(err error, errorType ErrorType, message string) *Error {
 		message:   message,
 	}
 }
+
+// Is reports whether err is an application error of the given type
+func Is(err error, errorType ErrorType) bool {
+	var appErr *Error
+	return errors.As(err, &appErr) && appErr.errorType == errorType
+}
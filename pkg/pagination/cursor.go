@@ -0,0 +1,82 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// ErrInvalidCursor is returned when a cursor token cannot be decoded
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks a position in a list ordered by creation time, with the ID
+// breaking ties between rows created at the same instant. Lists ordered by a
+// relevance score also carry the score of the last row, and lists that put
+// pinned rows first carry the pin time of a pinned last row.
+type Cursor struct {
+	Rank      float64    `json:"r,omitempty"`
+	PinnedAt  *time.Time `json:"p,omitempty"`
+	CreatedAt time.Time  `json:"t"`
+	ID        string     `json:"i"`
+}
+
+// NewCursor creates a cursor pointing at the given row
+func NewCursor(createdAt time.Time, id string) *Cursor {
+	return &Cursor{
+		CreatedAt: createdAt,
+		ID:        id,
+	}
+}
+
+// NewRankedCursor creates a cursor pointing at a row of a relevance-ordered list
+func NewRankedCursor(rank float64, createdAt time.Time, id string) *Cursor {
+	return &Cursor{
+		Rank:      rank,
+		CreatedAt: createdAt,
+		ID:        id,
+	}
+}
+
+// NewPinnedCursor creates a cursor pointing at a pinned row of a list that
+// puts pinned rows first
+func NewPinnedCursor(pinnedAt time.Time, createdAt time.Time, id string) *Cursor {
+	return &Cursor{
+		PinnedAt:  &pinnedAt,
+		CreatedAt: createdAt,
+		ID:        id,
+	}
+}
+
+// Encode returns the opaque token handed to clients
+func (c *Cursor) Encode() string {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Decode parses an opaque cursor token. An empty token yields a nil cursor,
+// meaning the first page.
+func Decode(token string) (*Cursor, error) {
+	if token == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if cursor.ID == "" || cursor.CreatedAt.IsZero() {
+		return nil, ErrInvalidCursor
+	}
+
+	return &cursor, nil
+}
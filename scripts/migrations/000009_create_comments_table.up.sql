@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS comment (
+    id VARCHAR(256) PRIMARY KEY,
+    post_id VARCHAR(256) NOT NULL REFERENCES post(id) ON DELETE CASCADE,
+    user_id VARCHAR(256) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    parent_id VARCHAR(256) REFERENCES comment(id) ON DELETE CASCADE,
+    depth INTEGER NOT NULL DEFAULT 0,
+    content TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT check_comment_depth CHECK (depth >= 0)
+);
+
+-- Supports keyset pagination of top-level comments and of replies
+CREATE INDEX idx_comment_post_top_level ON comment(post_id, created_at, id) WHERE parent_id IS NULL;
+CREATE INDEX idx_comment_parent_created_at ON comment(parent_id, created_at, id);
+CREATE INDEX idx_comment_user_id ON comment(user_id);
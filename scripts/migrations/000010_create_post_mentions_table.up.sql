@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS post_mention (
+    post_id VARCHAR(256) NOT NULL REFERENCES post(id) ON DELETE CASCADE,
+    user_id VARCHAR(256) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    username VARCHAR(64) NOT NULL,
+    start_offset INTEGER NOT NULL,
+    end_offset INTEGER NOT NULL,
+    PRIMARY KEY (post_id, start_offset)
+);
+
+CREATE INDEX idx_post_mention_user_id ON post_mention(user_id);
@@ -0,0 +1,2 @@
+-- Index hashtags for containment lookups (hashtags @> ARRAY['tag'])
+CREATE INDEX IF NOT EXISTS idx_post_hashtags ON post USING GIN (hashtags);
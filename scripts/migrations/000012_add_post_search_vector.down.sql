@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_post_search_vector;
+ALTER TABLE post DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,5 @@
+-- Full-text search over post content
+ALTER TABLE post ADD COLUMN IF NOT EXISTS search_vector TSVECTOR
+    GENERATED ALWAYS AS (to_tsvector('english', coalesce(content, ''))) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_post_search_vector ON post USING GIN (search_vector);
@@ -0,0 +1,2 @@
+ALTER TABLE post DROP COLUMN IF EXISTS revision_count;
+DROP TABLE IF EXISTS post_revision;
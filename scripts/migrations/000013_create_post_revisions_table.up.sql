@@ -0,0 +1,15 @@
+-- Each row keeps a version of a post as it was before an edit
+CREATE TABLE IF NOT EXISTS post_revision (
+    post_id VARCHAR(256) NOT NULL REFERENCES post(id) ON DELETE CASCADE,
+    revision INTEGER NOT NULL,
+    content TEXT NOT NULL,
+    embed_urls TEXT[],
+    hashtags TEXT[],
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (post_id, revision)
+);
+
+ALTER TABLE post ADD COLUMN IF NOT EXISTS revision_count INTEGER NOT NULL DEFAULT 0;
+
+-- Posts used to be created with updated_at set, which made every post look edited
+UPDATE post SET updated_at = NULL WHERE updated_at = created_at;
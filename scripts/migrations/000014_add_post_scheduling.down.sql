@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_post_scheduled_publish_at;
+ALTER TABLE post DROP COLUMN IF EXISTS publish_at;
+ALTER TABLE post DROP COLUMN IF EXISTS status;
@@ -0,0 +1,5 @@
+ALTER TABLE post ADD COLUMN IF NOT EXISTS status VARCHAR(32) NOT NULL DEFAULT 'published';
+ALTER TABLE post ADD COLUMN IF NOT EXISTS publish_at TIMESTAMPTZ;
+
+-- Lets the publisher find due posts without scanning published ones
+CREATE INDEX IF NOT EXISTS idx_post_scheduled_publish_at ON post(publish_at) WHERE status = 'scheduled';
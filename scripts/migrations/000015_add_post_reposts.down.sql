@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_post_unique_repost;
+DROP INDEX IF EXISTS idx_post_original_post_id;
+ALTER TABLE post DROP COLUMN IF EXISTS original_post_id;
+ALTER TABLE post DROP COLUMN IF EXISTS kind;
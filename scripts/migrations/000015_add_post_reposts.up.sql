@@ -0,0 +1,9 @@
+ALTER TABLE post ADD COLUMN IF NOT EXISTS kind VARCHAR(16) NOT NULL DEFAULT 'post';
+
+-- Quote posts outlive their original; plain reposts are deleted with it
+ALTER TABLE post ADD COLUMN IF NOT EXISTS original_post_id VARCHAR(256) REFERENCES post(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_post_original_post_id ON post(original_post_id) WHERE original_post_id IS NOT NULL;
+
+-- A user can repost a post only once
+CREATE UNIQUE INDEX IF NOT EXISTS idx_post_unique_repost ON post(user_id, original_post_id) WHERE kind = 'repost';
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS bookmark (
+    user_id VARCHAR(256) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id VARCHAR(256) NOT NULL REFERENCES post(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, post_id)
+);
+
+CREATE INDEX idx_bookmark_user_created_at ON bookmark(user_id, created_at DESC);
+CREATE INDEX idx_bookmark_post_id ON bookmark(post_id);
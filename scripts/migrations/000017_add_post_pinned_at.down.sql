@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_post_pinned;
+ALTER TABLE post DROP COLUMN IF EXISTS pinned_at;
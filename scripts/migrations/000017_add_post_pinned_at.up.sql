@@ -0,0 +1,3 @@
+ALTER TABLE post ADD COLUMN IF NOT EXISTS pinned_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_post_pinned ON post(user_id, pinned_at DESC) WHERE pinned_at IS NOT NULL;
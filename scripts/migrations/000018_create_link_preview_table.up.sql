@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS link_preview (
+    url TEXT PRIMARY KEY,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    title TEXT,
+    description TEXT,
+    image_url TEXT,
+    site_name TEXT,
+    final_url TEXT,
+    error TEXT,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    claimed_at TIMESTAMPTZ,
+    fetched_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_link_preview_pending ON link_preview(created_at) WHERE status IN ('pending', 'fetching');
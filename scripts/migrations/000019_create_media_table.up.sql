@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS media (
+    id VARCHAR(256) PRIMARY KEY,
+    user_id VARCHAR(256) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id VARCHAR(256) REFERENCES post(id) ON DELETE SET NULL,
+    position SMALLINT NOT NULL DEFAULT 0,
+    content_type VARCHAR(64) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    storage_key TEXT NOT NULL,
+    thumbnail_key TEXT NOT NULL,
+    url TEXT NOT NULL,
+    thumbnail_url TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_media_post_id ON media(post_id, position) WHERE post_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_media_orphaned ON media(created_at) WHERE post_id IS NULL;
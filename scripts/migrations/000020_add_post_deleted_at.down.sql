@@ -0,0 +1,3 @@
+DELETE FROM post WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_post_deleted_at;
+ALTER TABLE post DROP COLUMN IF EXISTS deleted_at;
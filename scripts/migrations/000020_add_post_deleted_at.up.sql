@@ -0,0 +1,3 @@
+ALTER TABLE post ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_post_deleted_at ON post(user_id, deleted_at DESC) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE comment DROP COLUMN IF EXISTS hidden_at;
+ALTER TABLE post DROP COLUMN IF EXISTS hidden_at;
+DROP TABLE IF EXISTS moderation_action;
+DROP TABLE IF EXISTS report;
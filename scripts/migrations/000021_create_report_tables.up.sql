@@ -0,0 +1,41 @@
+CREATE TABLE IF NOT EXISTS report (
+    id VARCHAR(256) PRIMARY KEY,
+    reporter_id VARCHAR(256) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    target_type VARCHAR(16) NOT NULL,
+    target_id VARCHAR(256) NOT NULL,
+    -- Author of the reported content, or the reported user
+    target_user_id VARCHAR(256) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason VARCHAR(32) NOT NULL,
+    details TEXT,
+    status VARCHAR(16) NOT NULL DEFAULT 'open',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMPTZ,
+    resolved_by VARCHAR(256) REFERENCES users(id) ON DELETE SET NULL,
+    CONSTRAINT check_report_target_type CHECK (target_type IN ('post', 'comment', 'user')),
+    CONSTRAINT check_report_status CHECK (status IN ('open', 'actioned', 'dismissed'))
+);
+
+-- A user can have only one open report on the same target
+CREATE UNIQUE INDEX IF NOT EXISTS idx_report_unique_open ON report(reporter_id, target_type, target_id) WHERE status = 'open';
+CREATE INDEX IF NOT EXISTS idx_report_status_created_at ON report(status, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_report_target ON report(target_type, target_id);
+
+-- Audit trail of every moderation action. Rows are never updated or deleted,
+-- so they are kept when the moderator or the target goes away.
+CREATE TABLE IF NOT EXISTS moderation_action (
+    id VARCHAR(256) PRIMARY KEY,
+    moderator_id VARCHAR(256) NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    target_type VARCHAR(16) NOT NULL,
+    target_id VARCHAR(256) NOT NULL,
+    target_user_id VARCHAR(256) NOT NULL,
+    report_id VARCHAR(256),
+    note TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_moderation_action_created_at ON moderation_action(created_at DESC, id DESC);
+
+-- Content hidden by a moderator stays visible to its author only
+ALTER TABLE post ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMPTZ;
+ALTER TABLE comment ADD COLUMN IF NOT EXISTS hidden_at TIMESTAMPTZ;
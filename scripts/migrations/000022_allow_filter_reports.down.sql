@@ -0,0 +1,2 @@
+DELETE FROM report WHERE reporter_id IS NULL;
+ALTER TABLE report ALTER COLUMN reporter_id SET NOT NULL;
@@ -0,0 +1,2 @@
+-- Reports filed by the content filters have no reporter
+ALTER TABLE report ALTER COLUMN reporter_id DROP NOT NULL;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS poll (
+    post_id VARCHAR(256) PRIMARY KEY REFERENCES post(id) ON DELETE CASCADE,
+    closes_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS poll_option (
+    id VARCHAR(256) PRIMARY KEY,
+    post_id VARCHAR(256) NOT NULL REFERENCES poll(post_id) ON DELETE CASCADE,
+    position SMALLINT NOT NULL,
+    text VARCHAR(80) NOT NULL,
+    UNIQUE (post_id, position)
+);
+
+-- The primary key allows one vote per user per poll, even under concurrent requests
+CREATE TABLE IF NOT EXISTS poll_vote (
+    post_id VARCHAR(256) NOT NULL REFERENCES poll(post_id) ON DELETE CASCADE,
+    user_id VARCHAR(256) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    option_id VARCHAR(256) NOT NULL REFERENCES poll_option(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (post_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_poll_vote_option_id ON poll_vote(option_id);
+CREATE INDEX IF NOT EXISTS idx_poll_vote_user_id ON poll_vote(user_id);
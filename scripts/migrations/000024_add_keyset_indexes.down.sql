@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_follows_follower_created_at;
+DROP INDEX IF EXISTS idx_follows_following_created_at;
+DROP INDEX IF EXISTS idx_post_user_created_at_id;
@@ -0,0 +1,4 @@
+-- Keyset pagination walks these lists newest first, with the ID breaking ties
+CREATE INDEX IF NOT EXISTS idx_post_user_created_at_id ON post(user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_follows_following_created_at ON follows(following_id, created_at DESC, follower_id DESC);
+CREATE INDEX IF NOT EXISTS idx_follows_follower_created_at ON follows(follower_id, created_at DESC, following_id DESC);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_post_fanout_on_read;
+ALTER TABLE post DROP COLUMN IF EXISTS fanout_on_read;
+DROP TABLE IF EXISTS timeline;
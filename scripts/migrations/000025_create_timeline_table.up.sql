@@ -0,0 +1,30 @@
+-- Home timelines are materialized when posts are published. Each row puts a
+-- post on the timeline of its author or of one of their followers.
+CREATE TABLE IF NOT EXISTS timeline (
+    user_id VARCHAR(256) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id VARCHAR(256) NOT NULL REFERENCES post(id) ON DELETE CASCADE,
+    author_id VARCHAR(256) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, post_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_timeline_user_created_at ON timeline(user_id, created_at DESC, post_id DESC);
+CREATE INDEX IF NOT EXISTS idx_timeline_user_author ON timeline(user_id, author_id);
+
+-- Posts by authors with too many followers to fan out are read from the
+-- author's posts instead
+ALTER TABLE post ADD COLUMN IF NOT EXISTS fanout_on_read BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_post_fanout_on_read ON post(user_id, created_at DESC, id DESC) WHERE fanout_on_read;
+
+-- Fill the timelines with the posts published so far
+INSERT INTO timeline (user_id, post_id, author_id, created_at)
+SELECT p.user_id, p.id, p.user_id, p.created_at
+FROM post p
+WHERE p.status = 'published'
+UNION ALL
+SELECT f.follower_id, p.id, p.user_id, p.created_at
+FROM post p
+JOIN follows f ON f.following_id = p.user_id
+WHERE p.status = 'published'
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,22 @@
+-- Blocks hide two users from each other and keep them from following each other
+CREATE TABLE IF NOT EXISTS blocks (
+    blocker_id VARCHAR(256) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id VARCHAR(256) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (blocker_id, blocked_id),
+    CONSTRAINT check_self_block CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_blocks_blocked ON blocks(blocked_id);
+CREATE INDEX IF NOT EXISTS idx_blocks_blocker_created_at ON blocks(blocker_id, created_at DESC);
+
+-- Mutes hide a user's posts from the muter's feed only
+CREATE TABLE IF NOT EXISTS mutes (
+    muter_id VARCHAR(256) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    muted_id VARCHAR(256) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (muter_id, muted_id),
+    CONSTRAINT check_self_mute CHECK (muter_id <> muted_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_mutes_muter_created_at ON mutes(muter_id, created_at DESC);
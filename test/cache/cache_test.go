@@ -0,0 +1,114 @@
+// test/cache/cache_test.go
+package cache_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/platform/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jsonCache stores values as JSON and hands back decoded JSON, the way
+// RedisCache does, without needing a Redis server
+type jsonCache struct {
+	items map[string][]byte
+}
+
+func newJSONCache() *jsonCache {
+	return &jsonCache{items: map[string][]byte{}}
+}
+
+func (c *jsonCache) Get(key string) (interface{}, bool) {
+	data, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+func (c *jsonCache) Set(key string, value interface{}, _ time.Duration) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+	c.items[key] = data
+}
+
+func (c *jsonCache) Delete(key string) {
+	delete(c.items, key)
+}
+
+func (c *jsonCache) Clear() {
+	c.items = map[string][]byte{}
+}
+
+func (c *jsonCache) Incr(key string, _ time.Duration) (int64, error) {
+	var count int64
+	if data, ok := c.items[key]; ok {
+		if err := json.Unmarshal(data, &count); err != nil {
+			return 0, err
+		}
+	}
+	count++
+	c.items[key], _ = json.Marshal(count)
+	return count, nil
+}
+
+func TestGetIntoReadsCachedPostsFromEveryCache(t *testing.T) {
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	posts := []*model.Post{
+		{ID: "post-2", UserID: "user-1", Content: "Second", CreatedAt: created.Add(time.Minute)},
+		{ID: "post-1", UserID: "user-1", Content: "First", CreatedAt: created},
+	}
+
+	caches := map[string]cache.Cache{
+		"memory": cache.NewInMemoryCache(time.Minute),
+		"json":   newJSONCache(),
+	}
+
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			c.Set("feed:user-1:v1", posts, time.Minute)
+
+			var cached []*model.Post
+			require.True(t, cache.GetInto(c, "feed:user-1:v1", &cached))
+			require.Len(t, cached, 2)
+			assert.Equal(t, "post-2", cached[0].ID)
+			assert.Equal(t, "First", cached[1].Content)
+			assert.True(t, created.Equal(cached[1].CreatedAt))
+
+			// Pages cached under an older version are never read again
+			var version string
+			assert.False(t, cache.GetInto(c, "feed:user-1:v2", &cached))
+			assert.False(t, cache.GetInto(c, "timeline_version:user-1", &version))
+
+			c.Set("timeline_version:user-1", "v2", time.Minute)
+			require.True(t, cache.GetInto(c, "timeline_version:user-1", &version))
+			assert.Equal(t, "v2", version)
+		})
+	}
+}
+
+func TestGetIntoRejectsMismatchedValues(t *testing.T) {
+	for name, c := range map[string]cache.Cache{
+		"memory": cache.NewInMemoryCache(time.Minute),
+		"json":   newJSONCache(),
+	} {
+		t.Run(name, func(t *testing.T) {
+			c.Set("key", "not a list", time.Minute)
+
+			var posts []*model.Post
+			assert.False(t, cache.GetInto(c, "key", &posts))
+			assert.False(t, cache.GetInto(c, "key", posts), "the destination must be a pointer")
+		})
+	}
+}
@@ -48,3 +48,38 @@ func TestAuthMiddleware_Authenticate(t *testing.T) {
 
 	assert.Equal(t, http.StatusUnauthorized, w.Code)
 }
+
+func TestAuthMiddleware_RequireRole(t *testing.T) {
+	// Setup
+	middleware := middleware.NewAuthMiddleware("test-jwt-secret")
+
+	adminToken, err := middleware.GenerateToken("admin-user", []string{"user", "admin"}, 1*time.Hour)
+	require.NoError(t, err)
+
+	userToken, err := middleware.GenerateToken("test-user", []string{"user"}, 1*time.Hour)
+	require.NoError(t, err)
+
+	// Setup test router
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.Authenticate(), middleware.RequireRole("admin"))
+	r.GET("/admin", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	// Test admin token
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest("GET", "/admin", nil)
+	req.Header.Set("Authorization", "Bearer "+adminToken)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Test token without the role
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest("GET", "/admin", nil)
+	req.Header.Set("Authorization", "Bearer "+userToken)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
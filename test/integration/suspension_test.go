@@ -0,0 +1,149 @@
+// test/integration/suspension_test.go
+package middleware_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/cmd/api/app"
+	"github.com/PeterM45/perfolio-api/internal/common/middleware"
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/user/handler"
+	"github.com/PeterM45/perfolio-api/internal/user/interfaces"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/PeterM45/perfolio-api/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAccounts serves users from a map. Only GetUserByID is implemented.
+type fakeAccounts struct {
+	interfaces.UserService
+
+	users map[string]*model.User
+}
+
+func (f *fakeAccounts) GetUserByID(_ context.Context, id string) (*model.User, error) {
+	user, ok := f.users[id]
+	if !ok {
+		return nil, apperrors.NotFound("user: " + id)
+	}
+	return user, nil
+}
+
+func newFakeAccounts() *fakeAccounts {
+	return &fakeAccounts{users: map[string]*model.User{
+		"active-user":    {ID: "active-user", IsActive: true},
+		"suspended-user": {ID: "suspended-user", IsActive: false},
+	}}
+}
+
+func TestAuthMiddleware_RequireActive(t *testing.T) {
+	// Setup
+	middleware := middleware.NewAuthMiddleware("test-jwt-secret")
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(middleware.Authenticate(), middleware.RequireActive(newFakeAccounts()))
+	r.POST("/posts", func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	tests := []struct {
+		userID string
+		status int
+	}{
+		{"active-user", http.StatusCreated},
+		{"suspended-user", http.StatusForbidden},
+		{"deleted-user", http.StatusUnauthorized},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.userID, func(t *testing.T) {
+			token, err := middleware.GenerateToken(tc.userID, []string{"user"}, 1*time.Hour)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/posts", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.status, w.Code)
+		})
+	}
+}
+
+func TestRouter_SuspendedUserCannotWrite(t *testing.T) {
+	// Setup
+	log := logger.NewLogger("error")
+	am := middleware.NewAuthMiddleware("test-jwt-secret")
+
+	// The handlers are never reached, since the middleware rejects the requests
+	r := app.NewRouter(&handler.UserHandler{}, &handler.PostHandler{}, &handler.WidgetHandler{}, &handler.ReactionHandler{},
+		&handler.CommentHandler{}, &handler.BookmarkHandler{}, &handler.MediaHandler{}, &handler.ReportHandler{},
+		handler.NewAuthHandler(nil, am, log), am, newFakeAccounts(), log)
+
+	token, err := am.GenerateToken("suspended-user", []string{"user"}, 1*time.Hour)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		path string
+		body string
+	}{
+		{"comment", "/api/v1/posts/post-1/comments", `{"content":"hello"}`},
+		{"reaction", "/api/v1/posts/post-1/reactions", `{"type":"like"}`},
+		{"repost", "/api/v1/posts/post-1/repost", `{}`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", tc.path, strings.NewReader(tc.body))
+			req.Header.Set("Authorization", "Bearer "+token)
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusForbidden, w.Code)
+			assert.Contains(t, w.Body.String(), "your account is suspended")
+		})
+	}
+}
+
+func TestAuthHandler_RefreshRefusesSuspendedUser(t *testing.T) {
+	// Setup
+	log := logger.NewLogger("error")
+	am := middleware.NewAuthMiddleware("test-jwt-secret")
+	authHandler := handler.NewAuthHandler(newFakeAccounts(), am, log)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	authHandler.RegisterRoutes(r.Group(""))
+
+	tests := []struct {
+		userID string
+		status int
+	}{
+		{"active-user", http.StatusOK},
+		{"suspended-user", http.StatusForbidden},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.userID, func(t *testing.T) {
+			refreshToken, err := am.GenerateToken(tc.userID, []string{"refresh"}, 1*time.Hour)
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest("POST", "/auth/refresh", strings.NewReader(`{"refreshToken":"`+refreshToken+`"}`))
+			req.Header.Set("Content-Type", "application/json")
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.status, w.Code)
+		})
+	}
+}
@@ -0,0 +1,170 @@
+// test/media/media_test.go
+package media_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PeterM45/perfolio-api/internal/platform/imaging"
+	"github.com/PeterM45/perfolio-api/internal/platform/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// jpegWithExif encodes a width x height JPEG carrying an EXIF segment with
+// the given orientation and a camera make tag
+func jpegWithExif(t *testing.T, width, height int, orientation uint16) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var encoded bytes.Buffer
+	require.NoError(t, jpeg.Encode(&encoded, img, nil))
+
+	// Little-endian TIFF header with a single IFD holding the orientation
+	var tiff bytes.Buffer
+	tiff.WriteString("II")
+	binary.Write(&tiff, binary.LittleEndian, uint16(42))
+	binary.Write(&tiff, binary.LittleEndian, uint32(8))
+	binary.Write(&tiff, binary.LittleEndian, uint16(1))
+	binary.Write(&tiff, binary.LittleEndian, uint16(0x0112))
+	binary.Write(&tiff, binary.LittleEndian, uint16(3))
+	binary.Write(&tiff, binary.LittleEndian, uint32(1))
+	binary.Write(&tiff, binary.LittleEndian, orientation)
+	binary.Write(&tiff, binary.LittleEndian, uint16(0))
+	binary.Write(&tiff, binary.LittleEndian, uint32(0))
+	tiff.WriteString("SecretCamera")
+
+	segment := append([]byte("Exif\x00\x00"), tiff.Bytes()...)
+
+	var out bytes.Buffer
+	out.Write(encoded.Bytes()[:2]) // SOI
+	out.Write([]byte{0xFF, 0xE1})
+	binary.Write(&out, binary.BigEndian, uint16(len(segment)+2))
+	out.Write(segment)
+	out.Write(encoded.Bytes()[2:])
+
+	return out.Bytes()
+}
+
+func TestProcess_StripsExifAndAppliesOrientation(t *testing.T) {
+	data := jpegWithExif(t, 60, 20, 6)
+	require.True(t, bytes.Contains(data, []byte("SecretCamera")))
+
+	result, err := imaging.Process(data, imaging.Options{ThumbnailSize: 10})
+	require.NoError(t, err)
+
+	assert.Equal(t, "image/jpeg", result.ContentType)
+	assert.False(t, bytes.Contains(result.Data, []byte("Exif")))
+	assert.False(t, bytes.Contains(result.Data, []byte("SecretCamera")))
+
+	// Orientation 6 rotates the image a quarter turn
+	assert.Equal(t, 20, result.Width)
+	assert.Equal(t, 60, result.Height)
+
+	thumb, err := jpeg.Decode(bytes.NewReader(result.Thumbnail))
+	require.NoError(t, err)
+	assert.LessOrEqual(t, thumb.Bounds().Dx(), 10)
+	assert.Equal(t, 10, thumb.Bounds().Dy())
+}
+
+func TestProcess_KeepsPNGTransparency(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 800, 400))
+	var encoded bytes.Buffer
+	require.NoError(t, png.Encode(&encoded, img))
+
+	result, err := imaging.Process(encoded.Bytes(), imaging.Options{ThumbnailSize: 200})
+	require.NoError(t, err)
+
+	assert.Equal(t, "image/png", result.ThumbnailContentType)
+
+	thumb, err := png.Decode(bytes.NewReader(result.Thumbnail))
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 200, 100), thumb.Bounds())
+
+	_, _, _, a := thumb.At(0, 0).RGBA()
+	assert.Zero(t, a)
+}
+
+func TestProcess_RejectsInvalidImages(t *testing.T) {
+	_, err := imaging.Process([]byte("%PDF-1.4 not an image"), imaging.Options{})
+	assert.ErrorIs(t, err, imaging.ErrUnsupportedFormat)
+
+	data := jpegWithExif(t, 100, 100, 1)
+	_, err = imaging.Process(data, imaging.Options{MaxPixels: 5000})
+	assert.ErrorIs(t, err, imaging.ErrTooLarge)
+}
+
+// animatedGIF encodes a GIF of the given number of width x height frames
+func animatedGIF(t *testing.T, width, height, frames int) []byte {
+	anim := &gif.GIF{}
+	for i := 0; i < frames; i++ {
+		anim.Image = append(anim.Image, image.NewPaletted(image.Rect(0, 0, width, height), palette.Plan9))
+		anim.Delay = append(anim.Delay, 10)
+	}
+
+	var encoded bytes.Buffer
+	require.NoError(t, gif.EncodeAll(&encoded, anim))
+	return encoded.Bytes()
+}
+
+func TestProcess_KeepsGIFFrames(t *testing.T) {
+	result, err := imaging.Process(animatedGIF(t, 20, 10, 3), imaging.Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "image/gif", result.ContentType)
+
+	anim, err := gif.DecodeAll(bytes.NewReader(result.Data))
+	require.NoError(t, err)
+	assert.Len(t, anim.Image, 3)
+}
+
+func TestProcess_RejectsLargeAnimations(t *testing.T) {
+	// Each frame is within the limit, all of them together are not
+	data := animatedGIF(t, 10, 10, 6)
+	_, err := imaging.Process(data, imaging.Options{MaxPixels: 500})
+	assert.ErrorIs(t, err, imaging.ErrTooLarge)
+
+	_, err = imaging.Process(data, imaging.Options{MaxFrames: 5})
+	assert.ErrorIs(t, err, imaging.ErrTooManyFrames)
+
+	_, err = imaging.Process(data, imaging.Options{MaxPixels: 600, MaxFrames: 6})
+	assert.NoError(t, err)
+
+	// A truncated file is not decoded
+	_, err = imaging.Process(data[:len(data)-1], imaging.Options{})
+	assert.ErrorIs(t, err, imaging.ErrUnsupportedFormat)
+}
+
+func TestLocalStorage(t *testing.T) {
+	dir := t.TempDir()
+	store, err := storage.NewLocalStorage(dir, "http://localhost:8080/uploads/")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, store.Put(ctx, "media/a.jpg", []byte("data"), "image/jpeg"))
+
+	stored, err := os.ReadFile(filepath.Join(dir, "media", "a.jpg"))
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(stored))
+	assert.Equal(t, "http://localhost:8080/uploads/media/a.jpg", store.URL("media/a.jpg"))
+
+	require.NoError(t, store.Delete(ctx, "media/a.jpg"))
+	require.NoError(t, store.Delete(ctx, "media/a.jpg"))
+
+	for _, key := range []string{"../escape.jpg", "media/../../escape.jpg", "/abs.jpg", ""} {
+		assert.ErrorIs(t, store.Put(ctx, key, []byte("x"), "image/jpeg"), storage.ErrInvalidKey, key)
+	}
+}
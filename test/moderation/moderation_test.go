@@ -0,0 +1,169 @@
+// test/moderation/moderation_test.go
+package moderation_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/platform/cache"
+	"github.com/PeterM45/perfolio-api/internal/platform/moderation"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func post(text string, links ...string) *moderation.Content {
+	return &moderation.Content{
+		UserID: "user-1",
+		Kind:   moderation.KindPost,
+		Text:   text,
+		Links:  links,
+	}
+}
+
+func TestWordListFilter(t *testing.T) {
+	filter := moderation.NewWordListFilter([]string{"Free Money"}, []string{"crypto"})
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		text    string
+		action  moderation.Action
+		reasons []string
+	}{
+		{"clean", "Shipped a new portfolio today", moderation.Allow, nil},
+		{"blocked phrase ignores case and punctuation", "Get FREE... money now!", moderation.Reject, []string{`contains blocked term "free money"`}},
+		{"flagged word", "Thoughts on crypto?", moderation.Flag, []string{`contains flagged term "crypto"`}},
+		{"whole words only", "cryptography is hard", moderation.Allow, nil},
+		{"blocked wins over flagged", "free money in crypto", moderation.Reject, []string{`contains blocked term "free money"`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verdict, err := filter.Check(ctx, post(tt.text))
+			require.NoError(t, err)
+			assert.Equal(t, tt.action, verdict.Action)
+			assert.Equal(t, tt.reasons, verdict.Reasons)
+		})
+	}
+}
+
+func TestLinkDensityFilter(t *testing.T) {
+	filter := moderation.NewLinkDensityFilter(3, 0.5)
+	ctx := context.Background()
+
+	verdict, err := filter.Check(ctx, post("Wrote about our launch https://example.com/launch"))
+	require.NoError(t, err)
+	assert.Equal(t, moderation.Allow, verdict.Action, "a single link is an ordinary share")
+
+	verdict, err = filter.Check(ctx, post("see https://a.example www.b.example"))
+	require.NoError(t, err)
+	assert.Equal(t, moderation.Flag, verdict.Action)
+
+	verdict, err = filter.Check(ctx, post("Three posts this week: https://a.example https://b.example", "https://c.example", "https://d.example"))
+	require.NoError(t, err)
+	assert.Equal(t, moderation.Reject, verdict.Action, "embedded links count towards the cap")
+	assert.Equal(t, []string{"contains 4 links; at most 3 are allowed"}, verdict.Reasons)
+}
+
+func TestRepeatedContentFilter(t *testing.T) {
+	filter := moderation.NewRepeatedContentFilter(cache.NewInMemoryCache(time.Minute), time.Minute, 1, 2)
+	ctx := context.Background()
+
+	// Copies are counted once they are stored, which rejected ones are not
+	var actions []moderation.Action
+	var reasons []string
+	for _, text := range []string{"Hire me!", "hire me", "HIRE ME!!", "Hire me."} {
+		verdict, err := filter.Check(ctx, post(text))
+		require.NoError(t, err)
+		actions = append(actions, verdict.Action)
+		reasons = verdict.Reasons
+
+		if verdict.Action != moderation.Reject {
+			require.NoError(t, filter.Record(ctx, post(text)))
+		}
+	}
+	assert.Equal(t, []moderation.Action{moderation.Allow, moderation.Flag, moderation.Reject, moderation.Reject}, actions)
+	assert.Equal(t, []string{"the same content was posted 2 times recently"}, reasons)
+
+	// Other users and other text are counted separately
+	other := post("Hire me!")
+	other.UserID = "user-2"
+	verdict, err := filter.Check(ctx, other)
+	require.NoError(t, err)
+	assert.Equal(t, moderation.Allow, verdict.Action)
+
+	verdict, err = filter.Check(ctx, post("Something new"))
+	require.NoError(t, err)
+	assert.Equal(t, moderation.Allow, verdict.Action)
+
+	// Profile fields are not checked
+	bio := post("Hire me!")
+	bio.Kind = moderation.KindBio
+	verdict, err = filter.Check(ctx, bio)
+	require.NoError(t, err)
+	assert.Equal(t, moderation.Allow, verdict.Action)
+}
+
+func TestRepeatedContentFilter_CheckAloneDoesNotCount(t *testing.T) {
+	filter := moderation.NewRepeatedContentFilter(cache.NewInMemoryCache(time.Minute), time.Minute, 1, 2)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		verdict, err := filter.Check(ctx, post("Hire me!"))
+		require.NoError(t, err)
+		assert.Equal(t, moderation.Allow, verdict.Action)
+	}
+}
+
+func TestRepeatedContentFilter_ConcurrentRecords(t *testing.T) {
+	filter := moderation.NewRepeatedContentFilter(cache.NewInMemoryCache(time.Minute), time.Minute, 0, 50)
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, filter.Record(ctx, post("Hire me!")))
+		}()
+	}
+	wg.Wait()
+
+	verdict, err := filter.Check(ctx, post("Hire me!"))
+	require.NoError(t, err)
+	assert.Equal(t, moderation.Reject, verdict.Action)
+	assert.Equal(t, []string{"the same content was posted 50 times recently"}, verdict.Reasons)
+}
+
+func TestChainRecordsWithRecordingFilters(t *testing.T) {
+	repeated := moderation.NewRepeatedContentFilter(cache.NewInMemoryCache(time.Minute), time.Minute, 0, 1)
+	chain := moderation.NewChain(moderation.NewWordListFilter(nil, nil), repeated)
+	ctx := context.Background()
+
+	require.NoError(t, chain.Record(ctx, post("Hire me!")))
+
+	verdict, err := chain.Check(ctx, post("Hire me!"))
+	require.NoError(t, err)
+	assert.Equal(t, moderation.Reject, verdict.Action)
+}
+
+func TestChainKeepsStrictestVerdictAndAllReasons(t *testing.T) {
+	chain := moderation.NewChain(
+		moderation.NewWordListFilter(nil, []string{"crypto"}),
+		moderation.NewLinkDensityFilter(1, 0),
+	)
+
+	verdict, err := chain.Check(context.Background(), post("crypto https://a.example https://b.example"))
+	require.NoError(t, err)
+	assert.Equal(t, moderation.Reject, verdict.Action)
+	assert.Equal(t, []string{
+		`contains flagged term "crypto"`,
+		"contains 2 links; at most 1 are allowed",
+	}, verdict.Reasons)
+
+	verdict, err = moderation.NewChain().Check(context.Background(), post("anything"))
+	require.NoError(t, err)
+	assert.Equal(t, moderation.Allow, verdict.Action)
+}
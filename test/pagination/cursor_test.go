@@ -0,0 +1,92 @@
+// test/pagination/cursor_test.go
+package pagination_test
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 12, 30, 45, 123456000, time.UTC)
+	pinnedAt := time.Date(2024, 3, 2, 8, 0, 0, 1000, time.UTC)
+
+	tests := []struct {
+		name   string
+		cursor *pagination.Cursor
+	}{
+		{"plain", pagination.NewCursor(createdAt, "post-1")},
+		{"ranked", pagination.NewRankedCursor(12.75, createdAt, "post-2")},
+		{"pinned", pagination.NewPinnedCursor(pinnedAt, createdAt, "post-3")},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			token := tc.cursor.Encode()
+			require.NotEmpty(t, token)
+
+			decoded, err := pagination.Decode(token)
+			require.NoError(t, err)
+			require.NotNil(t, decoded)
+
+			assert.True(t, tc.cursor.CreatedAt.Equal(decoded.CreatedAt))
+			assert.Equal(t, tc.cursor.ID, decoded.ID)
+			assert.Equal(t, tc.cursor.Rank, decoded.Rank)
+			if tc.cursor.PinnedAt == nil {
+				assert.Nil(t, decoded.PinnedAt)
+			} else {
+				require.NotNil(t, decoded.PinnedAt)
+				assert.True(t, tc.cursor.PinnedAt.Equal(*decoded.PinnedAt))
+			}
+		})
+	}
+}
+
+func TestCursor_KeepsTimeZoneInstant(t *testing.T) {
+	zone := time.FixedZone("UTC+5", 5*60*60)
+	createdAt := time.Date(2024, 3, 1, 17, 0, 0, 0, zone)
+
+	decoded, err := pagination.Decode(pagination.NewCursor(createdAt, "post-1").Encode())
+	require.NoError(t, err)
+	assert.True(t, createdAt.Equal(decoded.CreatedAt))
+}
+
+func TestDecode_EmptyTokenIsFirstPage(t *testing.T) {
+	cursor, err := pagination.Decode("")
+	require.NoError(t, err)
+	assert.Nil(t, cursor)
+}
+
+func TestDecode_RejectsInvalidTokens(t *testing.T) {
+	encode := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"not base64", "not a cursor!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"t":"2024-03-01T12:00:00Z","i":"post-1"}`))},
+		{"not json", encode("post-1")},
+		{"json array", encode(`["2024-03-01T12:00:00Z","post-1"]`)},
+		{"missing id", encode(`{"t":"2024-03-01T12:00:00Z"}`)},
+		{"empty id", encode(`{"t":"2024-03-01T12:00:00Z","i":""}`)},
+		{"missing time", encode(`{"i":"post-1"}`)},
+		{"bad time", encode(`{"t":"yesterday","i":"post-1"}`)},
+		{"wrong id type", encode(`{"t":"2024-03-01T12:00:00Z","i":42}`)},
+		{"wrong rank type", encode(`{"r":"high","t":"2024-03-01T12:00:00Z","i":"post-1"}`)},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cursor, err := pagination.Decode(tc.token)
+			assert.ErrorIs(t, err, pagination.ErrInvalidCursor)
+			assert.Nil(t, cursor)
+		})
+	}
+}
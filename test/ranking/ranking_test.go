@@ -0,0 +1,135 @@
+// test/ranking/ranking_test.go
+package ranking_test
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/platform/ranking"
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func candidate(id string, age time.Duration) *ranking.Candidate {
+	return &ranking.Candidate{
+		PostID:    id,
+		AuthorID:  "author-" + id,
+		CreatedAt: now.Add(-age),
+		Reactions: map[string]int{},
+	}
+}
+
+func ids(ranked []ranking.Scored) []string {
+	var result []string
+	for _, scored := range ranked {
+		result = append(result, scored.PostID)
+	}
+	return result
+}
+
+func TestWeightedStrategyScore(t *testing.T) {
+	strategy := ranking.NewWeightedStrategy(ranking.DefaultWeights())
+
+	fresh := candidate("fresh", 0)
+	assert.Equal(t, 1.0, strategy.Score(fresh, now), "a new post without engagement scores 1")
+
+	halfLife := candidate("half-life", 12*time.Hour)
+	assert.InDelta(t, 0.5, strategy.Score(halfLife, now), 1e-9, "the score halves every half-life")
+
+	future := candidate("future", -time.Hour)
+	assert.Equal(t, 1.0, strategy.Score(future, now), "clock skew does not boost a post")
+
+	engaged := candidate("engaged", 0)
+	engaged.Reactions = map[string]int{"like": 2, "insightful": 1, "unknown": 1}
+	engaged.Comments = 2
+	// 1 + 2 likes + 1 insightful (2) + 1 unknown (1) + 2 comments (3 each)
+	assert.InDelta(t, 12.0, strategy.Score(engaged, now), 1e-9)
+
+	affinity := candidate("affinity", 0)
+	affinity.Affinity = 3
+	assert.InDelta(t, 1+0.5*math.Log(4), strategy.Score(affinity, now), 1e-9)
+}
+
+func TestWeightedStrategyUsesConfiguredWeights(t *testing.T) {
+	strategy := ranking.NewWeightedStrategy(ranking.Weights{
+		Reactions: map[string]float64{"like": 0},
+		Comment:   10,
+	})
+
+	c := candidate("post", 0)
+	c.Reactions = map[string]int{"like": 50}
+	c.Comments = 1
+	assert.Equal(t, 11.0, strategy.Score(c, now))
+
+	// A missing half-life falls back to the default of 12 hours
+	old := candidate("old", 12*time.Hour)
+	assert.InDelta(t, 0.5, strategy.Score(old, now), 1e-9)
+}
+
+func TestRankOrdersByEngagementRecencyAndAffinity(t *testing.T) {
+	// A fresh post with a little engagement
+	recent := candidate("recent", time.Hour)
+	recent.Reactions = map[string]int{"like": 3}
+
+	// An older post with a lot of engagement
+	popular := candidate("popular", 20*time.Hour)
+	popular.Reactions = map[string]int{"like": 10, "insightful": 5}
+	popular.Comments = 4
+
+	// A post by an author the viewer often interacts with
+	friend := candidate("friend", 3*time.Hour)
+	friend.Reactions = map[string]int{"like": 3}
+	friend.Affinity = 20
+
+	// Insightful reactions outweigh likes
+	insightful := candidate("insightful", 6*time.Hour)
+	insightful.Reactions = map[string]int{"insightful": 3}
+	liked := candidate("liked", 6*time.Hour)
+	liked.Reactions = map[string]int{"like": 3}
+
+	// Old and untouched
+	stale := candidate("stale", 48*time.Hour)
+
+	ranked := ranking.Rank(ranking.NewWeightedStrategy(ranking.DefaultWeights()), []*ranking.Candidate{
+		stale, liked, recent, insightful, popular, friend,
+	}, now)
+
+	assert.Equal(t, []string{"popular", "friend", "insightful", "recent", "liked", "stale"}, ids(ranked))
+
+	for i := 1; i < len(ranked); i++ {
+		assert.GreaterOrEqual(t, ranked[i-1].Score, ranked[i].Score)
+	}
+}
+
+func TestRankBreaksTiesByRecencyThenID(t *testing.T) {
+	older := candidate("b", 2*time.Hour)
+	newerA := candidate("a", time.Hour)
+	newerC := candidate("c", time.Hour)
+
+	flat := strategyFunc(func(*ranking.Candidate, time.Time) float64 { return 1 })
+
+	ranked := ranking.Rank(flat, []*ranking.Candidate{older, newerA, newerC}, now)
+	assert.Equal(t, []string{"c", "a", "b"}, ids(ranked))
+}
+
+func TestRankUsesTheGivenStrategy(t *testing.T) {
+	byComments := strategyFunc(func(c *ranking.Candidate, _ time.Time) float64 {
+		return float64(c.Comments)
+	})
+
+	quiet := candidate("quiet", 0)
+	busy := candidate("busy", 30*24*time.Hour)
+	busy.Comments = 5
+
+	ranked := ranking.Rank(byComments, []*ranking.Candidate{quiet, busy}, now)
+	assert.Equal(t, []string{"busy", "quiet"}, ids(ranked))
+	assert.Equal(t, 5.0, ranked[0].Score)
+}
+
+type strategyFunc func(c *ranking.Candidate, now time.Time) float64
+
+func (f strategyFunc) Score(c *ranking.Candidate, now time.Time) float64 {
+	return f(c, now)
+}
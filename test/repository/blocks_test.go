@@ -0,0 +1,198 @@
+// test/repository/blocks_test.go
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/platform/database"
+	"github.com/PeterM45/perfolio-api/internal/user/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBlockDB creates alice and bob, who follow each other and have posted
+// once each, and carol, who follows and is followed by both
+func newBlockDB(t *testing.T) *database.DB {
+	t.Helper()
+
+	db := openTestDB(t)
+	for _, id := range []string{"alice", "bob", "carol"} {
+		insertUser(t, db, id)
+	}
+	insertFollow(t, db, "alice", "bob", base)
+	insertFollow(t, db, "bob", "alice", base)
+	for _, id := range []string{"alice", "bob"} {
+		insertFollow(t, db, id, "carol", base)
+		insertFollow(t, db, "carol", id, base)
+	}
+	insertPost(t, db, "alice-post", "alice", base.Add(time.Minute))
+	insertPost(t, db, "bob-post", "bob", base.Add(2*time.Minute))
+	return db
+}
+
+func postIDs(posts []*model.Post) []string {
+	result := make([]string, 0, len(posts))
+	for _, post := range posts {
+		result = append(result, post.ID)
+	}
+	return result
+}
+
+func userIDs(users []*model.User) []string {
+	result := make([]string, 0, len(users))
+	for _, user := range users {
+		result = append(result, user.ID)
+	}
+	return result
+}
+
+func TestBlock_RemovesFollowsAndTimelinesBothWays(t *testing.T) {
+	ctx := context.Background()
+	db := newBlockDB(t)
+	users := repository.NewUserRepository(db)
+	posts := repository.NewPostRepository(db)
+
+	require.NoError(t, users.Block(ctx, "alice", "bob"))
+
+	for _, pair := range [][2]string{{"alice", "bob"}, {"bob", "alice"}} {
+		following, err := users.IsFollowing(ctx, pair[0], pair[1])
+		require.NoError(t, err)
+		assert.False(t, following, "%s follows %s", pair[0], pair[1])
+
+		blocked, err := users.IsBlocked(ctx, pair[0], pair[1])
+		require.NoError(t, err)
+		assert.True(t, blocked)
+
+		var count int
+		err = db.QueryRowContext(ctx, `SELECT COUNT(*) FROM timeline WHERE user_id = $1 AND author_id = $2`, pair[0], pair[1]).Scan(&count)
+		require.NoError(t, err)
+		assert.Zero(t, count, "posts by %s on the timeline of %s", pair[1], pair[0])
+
+		feed, err := posts.GetFeed(ctx, pair[0], nil, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{pair[0] + "-post"}, postIDs(feed))
+	}
+
+	// Follows with everyone else stay
+	for _, id := range []string{"alice", "bob"} {
+		following, err := users.IsFollowing(ctx, id, "carol")
+		require.NoError(t, err)
+		assert.True(t, following)
+	}
+
+	feed, err := posts.GetFeed(ctx, "carol", nil, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob-post", "alice-post"}, postIDs(feed))
+
+	// Lifting the block leaves the follows removed
+	require.NoError(t, users.Unblock(ctx, "alice", "bob"))
+	blocked, err := users.IsBlocked(ctx, "bob", "alice")
+	require.NoError(t, err)
+	assert.False(t, blocked)
+	following, err := users.IsFollowing(ctx, "alice", "bob")
+	require.NoError(t, err)
+	assert.False(t, following)
+}
+
+func TestBlock_FiltersReadPaths(t *testing.T) {
+	ctx := context.Background()
+	db := newBlockDB(t)
+	users := repository.NewUserRepository(db)
+	posts := repository.NewPostRepository(db)
+
+	require.NoError(t, users.Block(ctx, "alice", "bob"))
+
+	for _, pair := range [][2]string{{"alice", "bob"}, {"bob", "alice"}} {
+		viewerID, otherID := pair[0], pair[1]
+
+		found, err := users.Search(ctx, otherID, viewerID, 10)
+		require.NoError(t, err)
+		assert.Empty(t, found, "%s searching for %s", viewerID, otherID)
+
+		followers, err := users.GetFollowers(ctx, "carol", viewerID, nil, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{viewerID}, userIDs(followers))
+
+		following, err := users.GetFollowing(ctx, "carol", viewerID, nil, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{viewerID}, userIDs(following))
+
+		profile, err := posts.GetByUserID(ctx, otherID, viewerID, nil, 10, 0)
+		require.NoError(t, err)
+		assert.Empty(t, profile)
+
+		visible, err := posts.GetVisibleByIDs(ctx, []string{otherID + "-post"}, viewerID)
+		require.NoError(t, err)
+		assert.Empty(t, visible)
+
+		// Authors still see their own posts
+		own, err := posts.GetByUserID(ctx, otherID, otherID, nil, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{otherID + "-post"}, postIDs(own))
+	}
+
+	// Everyone else still sees both
+	for _, id := range []string{"alice", "bob"} {
+		found, err := users.Search(ctx, id, "carol", 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{id}, userIDs(found))
+	}
+	followers, err := users.GetFollowers(ctx, "carol", "carol", nil, 10, 0)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"alice", "bob"}, userIDs(followers))
+}
+
+func TestMute_HidesPostsFromFeedOnly(t *testing.T) {
+	ctx := context.Background()
+	db := newBlockDB(t)
+	users := repository.NewUserRepository(db)
+	posts := repository.NewPostRepository(db)
+
+	require.NoError(t, users.Mute(ctx, "alice", "bob"))
+
+	feed, err := posts.GetFeed(ctx, "alice", nil, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice-post"}, postIDs(feed))
+
+	// The mute is one-way and leaves the profile and follow alone
+	feed, err = posts.GetFeed(ctx, "bob", nil, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob-post", "alice-post"}, postIDs(feed))
+
+	profile, err := posts.GetByUserID(ctx, "bob", "alice", nil, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob-post"}, postIDs(profile))
+
+	following, err := users.IsFollowing(ctx, "alice", "bob")
+	require.NoError(t, err)
+	assert.True(t, following)
+
+	muted, err := users.GetMuted(ctx, "alice", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob"}, userIDs(muted))
+
+	require.NoError(t, users.Unmute(ctx, "alice", "bob"))
+	feed, err = posts.GetFeed(ctx, "alice", nil, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob-post", "alice-post"}, postIDs(feed))
+}
+
+func TestGetExcludedAuthors_FollowsAndBlocksOnly(t *testing.T) {
+	ctx := context.Background()
+	db := newBlockDB(t)
+	users := repository.NewUserRepository(db)
+	posts := repository.NewPostRepository(db)
+	for _, id := range []string{"dave", "erin", "frank"} {
+		insertUser(t, db, id)
+	}
+
+	require.NoError(t, users.Block(ctx, "dave", "alice"))
+	require.NoError(t, users.Mute(ctx, "alice", "erin"))
+
+	excluded, err := posts.GetExcludedAuthors(ctx, "alice", []string{"bob", "dave", "erin", "frank"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"bob": true, "dave": true}, excluded)
+}
@@ -0,0 +1,126 @@
+// test/repository/db_test.go
+package repository_test
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/platform/database"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // PostgreSQL driver
+)
+
+// openTestDB connects to the database named by TEST_DATABASE_URL and migrates
+// a fresh schema that is dropped when the test ends. Tests are skipped when
+// the variable is not set.
+func openTestDB(t *testing.T) *database.DB {
+	t.Helper()
+
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+
+	admin, err := sql.Open("pgx", url)
+	require.NoError(t, err)
+	t.Cleanup(func() { admin.Close() })
+
+	suffix := make([]byte, 6)
+	_, err = rand.Read(suffix)
+	require.NoError(t, err)
+	schema := "test_" + hex.EncodeToString(suffix)
+
+	_, err = admin.ExecContext(ctx, `CREATE SCHEMA `+schema)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		admin.ExecContext(context.Background(), `DROP SCHEMA `+schema+` CASCADE`)
+	})
+
+	// Unknown URL parameters are sent to the server as session settings
+	separator := "?"
+	if strings.Contains(url, "?") {
+		separator = "&"
+	}
+
+	db, err := sql.Open("pgx", url+separator+"search_path="+schema+",public")
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	files, err := filepath.Glob(filepath.Join("..", "..", "scripts", "migrations", "*.up.sql"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	sort.Strings(files)
+
+	for _, file := range files {
+		migration, err := os.ReadFile(file)
+		require.NoError(t, err)
+
+		_, err = db.ExecContext(ctx, string(migration))
+		require.NoError(t, err, filepath.Base(file))
+	}
+
+	return &database.DB{DB: db}
+}
+
+// insertUser stores an active user whose username is its ID
+func insertUser(t *testing.T, db *database.DB, id string) {
+	t.Helper()
+
+	_, err := db.ExecContext(context.Background(),
+		`INSERT INTO users (id, username, auth_provider, is_active) VALUES ($1, $1, $2, TRUE)`,
+		id, model.AuthProviderEmail)
+	require.NoError(t, err)
+}
+
+// insertFollow makes one user follow another at the given time
+func insertFollow(t *testing.T, db *database.DB, followerID, followingID string, at time.Time) {
+	t.Helper()
+
+	_, err := db.ExecContext(context.Background(),
+		`INSERT INTO follows (follower_id, following_id, created_at) VALUES ($1, $2, $3)`,
+		followerID, followingID, at)
+	require.NoError(t, err)
+}
+
+// insertPost stores a published public post created at the given time, and
+// puts it on the timelines of its author and their followers
+func insertPost(t *testing.T, db *database.DB, id, userID string, at time.Time) {
+	t.Helper()
+
+	ctx := context.Background()
+	_, err := db.ExecContext(ctx,
+		`INSERT INTO post (id, user_id, content, visibility, status, kind, created_at)
+		VALUES ($1, $2, $1, $3, $4, $5, $6)`,
+		id, userID, model.VisibilityPublic, model.PostStatusPublished, model.PostKindPost, at)
+	require.NoError(t, err)
+
+	_, err = db.ExecContext(ctx,
+		`INSERT INTO timeline (user_id, post_id, author_id, created_at)
+		SELECT $2, $1, $2, $3
+		UNION ALL
+		SELECT follower_id, $1, $2, $3 FROM follows WHERE following_id = $2`,
+		id, userID, at)
+	require.NoError(t, err)
+}
+
+// requireErrorType asserts that err is an application error of the given type
+func requireErrorType(t *testing.T, err error, errorType apperrors.ErrorType) {
+	t.Helper()
+
+	var appErr *apperrors.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, errorType, appErr.Type(), appErr.Error())
+}
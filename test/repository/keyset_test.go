@@ -0,0 +1,169 @@
+// test/repository/keyset_test.go
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/user/repository"
+	"github.com/PeterM45/perfolio-api/pkg/pagination"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// base is the creation time of the first row in each test
+var base = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// nextPostCursor returns the cursor after the last post of a page, as handed
+// to clients, the way the post service builds it
+func nextPostCursor(t *testing.T, posts []*model.Post) *pagination.Cursor {
+	t.Helper()
+
+	last := posts[len(posts)-1]
+	cursor := pagination.NewCursor(last.CreatedAt, last.ID)
+	if last.PinnedAt != nil {
+		cursor = pagination.NewPinnedCursor(*last.PinnedAt, last.CreatedAt, last.ID)
+	}
+
+	decoded, err := pagination.Decode(cursor.Encode())
+	require.NoError(t, err)
+	return decoded
+}
+
+func TestGetByUserID_PagesAcrossPinnedBoundary(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	repo := repository.NewPostRepository(db)
+	insertUser(t, db, "author")
+
+	// p4 and p5 share a creation time, so their IDs decide the order
+	for i, at := range []time.Time{base, base.Add(time.Minute), base.Add(2 * time.Minute), base.Add(3 * time.Minute), base.Add(3 * time.Minute)} {
+		insertPost(t, db, fmt.Sprintf("p%d", i+1), "author", at)
+	}
+
+	// The oldest post was pinned last, so it comes first
+	for id, at := range map[string]time.Time{"p3": base.Add(time.Hour), "p1": base.Add(2 * time.Hour)} {
+		_, err := db.ExecContext(ctx, `UPDATE post SET pinned_at = $1 WHERE id = $2`, at, id)
+		require.NoError(t, err)
+	}
+
+	want := []string{"p1", "p3", "p5", "p4", "p2"}
+
+	for limit := 1; limit <= len(want); limit++ {
+		t.Run(fmt.Sprintf("limit %d", limit), func(t *testing.T) {
+			var got []string
+			var cursor *pagination.Cursor
+			for page := 0; page <= len(want); page++ {
+				posts, err := repo.GetByUserID(ctx, "author", "author", cursor, limit, 0)
+				require.NoError(t, err)
+				if len(posts) == 0 {
+					break
+				}
+
+				for _, post := range posts {
+					got = append(got, post.ID)
+				}
+				cursor = nextPostCursor(t, posts)
+			}
+
+			assert.Equal(t, want, got)
+		})
+	}
+}
+
+func TestGetFeed_InsertsBetweenPagesSkipNothing(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	repo := repository.NewPostRepository(db)
+	insertUser(t, db, "reader")
+	insertUser(t, db, "author")
+	insertUser(t, db, "celebrity")
+	insertFollow(t, db, "reader", "author", base.Add(-time.Hour))
+	insertFollow(t, db, "reader", "celebrity", base.Add(-time.Hour))
+
+	// Posts by the celebrity are read from the author rather than fanned out
+	var want []string
+	for i := 6; i >= 1; i-- {
+		id := fmt.Sprintf("p%d", i)
+		authorID := "author"
+		if i%2 == 0 {
+			authorID = "celebrity"
+		}
+		insertPost(t, db, id, authorID, base.Add(time.Duration(i)*time.Minute))
+		want = append(want, id)
+	}
+	_, err := db.ExecContext(ctx, `UPDATE post SET fanout_on_read = TRUE WHERE user_id = 'celebrity'`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `DELETE FROM timeline WHERE author_id = 'celebrity' AND user_id <> author_id`)
+	require.NoError(t, err)
+
+	var got []string
+	var cursor *pagination.Cursor
+	for page := 0; page <= len(want); page++ {
+		posts, err := repo.GetFeed(ctx, "reader", cursor, 2, 0)
+		require.NoError(t, err)
+		if len(posts) == 0 {
+			break
+		}
+
+		for _, post := range posts {
+			got = append(got, post.ID)
+		}
+		cursor = nextPostCursor(t, posts)
+
+		// New posts land at the top, ahead of the pages already read
+		insertPost(t, db, fmt.Sprintf("new-%d", page), "author", base.Add(time.Hour+time.Duration(page)*time.Minute))
+	}
+
+	assert.Equal(t, want, got)
+}
+
+func TestGetFollowers_InsertsBetweenPagesSkipNothing(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	repo := repository.NewUserRepository(db)
+	insertUser(t, db, "author")
+
+	// f4 and f5 followed at the same instant, so their IDs decide the order
+	var want []string
+	for i := 6; i >= 1; i-- {
+		id := fmt.Sprintf("f%d", i)
+		insertUser(t, db, id)
+
+		at := base.Add(time.Duration(i) * time.Minute)
+		if i == 5 {
+			at = base.Add(4 * time.Minute)
+		}
+		insertFollow(t, db, id, "author", at)
+		want = append(want, id)
+	}
+
+	var got []string
+	var cursor *pagination.Cursor
+	for page := 0; page <= len(want); page++ {
+		users, err := repo.GetFollowers(ctx, "author", "", cursor, 2, 0)
+		require.NoError(t, err)
+		if len(users) == 0 {
+			break
+		}
+
+		for _, user := range users {
+			got = append(got, user.ID)
+		}
+
+		last := users[len(users)-1]
+		require.NotNil(t, last.FollowedAt)
+		cursor, err = pagination.Decode(pagination.NewCursor(*last.FollowedAt, last.ID).Encode())
+		require.NoError(t, err)
+
+		// New followers land at the top, ahead of the pages already read
+		newID := fmt.Sprintf("new-%d", page)
+		insertUser(t, db, newID)
+		insertFollow(t, db, newID, "author", base.Add(time.Hour+time.Duration(page)*time.Minute))
+	}
+
+	assert.Equal(t, want, got)
+}
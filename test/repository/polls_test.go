@@ -0,0 +1,116 @@
+// test/repository/polls_test.go
+package repository_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/platform/database"
+	"github.com/PeterM45/perfolio-api/internal/user/repository"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createPollPost stores a post by the author with a two-option poll that is
+// open for an hour
+func createPollPost(t *testing.T, repo repository.PostRepository, authorID string) *model.Post {
+	t.Helper()
+
+	post := &model.Post{
+		UserID:     authorID,
+		Content:    "Which one?",
+		Visibility: model.VisibilityPublic,
+		Status:     model.PostStatusPublished,
+		Poll: &model.Poll{
+			ClosesAt: time.Now().Add(time.Hour).UTC(),
+			Options: []*model.PollOption{
+				{ID: uuid.New().String(), Position: 0, Text: "Tabs"},
+				{ID: uuid.New().String(), Position: 1, Text: "Spaces"},
+			},
+		},
+	}
+	require.NoError(t, repo.Create(context.Background(), post))
+	return post
+}
+
+// newPollRepo creates a post repository over a fresh schema holding an author
+// and a voter
+func newPollRepo(t *testing.T) (*database.DB, repository.PostRepository) {
+	t.Helper()
+
+	db := openTestDB(t)
+	insertUser(t, db, "author")
+	insertUser(t, db, "voter")
+	return db, repository.NewPostRepository(db)
+}
+
+func TestVotePoll_ConcurrentVotesCountOnce(t *testing.T) {
+	ctx := context.Background()
+	_, repo := newPollRepo(t)
+	post := createPollPost(t, repo, "author")
+
+	const attempts = 20
+	errs := make(chan error, attempts)
+
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			option := post.Poll.Options[i%len(post.Poll.Options)]
+			errs <- repo.VotePoll(ctx, post.ID, option.ID, "voter")
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		requireErrorType(t, err, apperrors.ErrTypeConflict)
+	}
+	assert.Equal(t, 1, succeeded)
+
+	polls, err := repo.GetPolls(ctx, []string{post.ID}, "voter")
+	require.NoError(t, err)
+	require.Contains(t, polls, post.ID)
+	assert.Equal(t, 1, *polls[post.ID].TotalVotes)
+	assert.NotNil(t, polls[post.ID].ViewerOptionID)
+}
+
+func TestVotePoll_ClosedPoll(t *testing.T) {
+	ctx := context.Background()
+	db, repo := newPollRepo(t)
+	post := createPollPost(t, repo, "author")
+
+	_, err := db.ExecContext(ctx, `UPDATE poll SET closes_at = $1 WHERE post_id = $2`, time.Now().Add(-time.Minute), post.ID)
+	require.NoError(t, err)
+
+	err = repo.VotePoll(ctx, post.ID, post.Poll.Options[0].ID, "voter")
+	requireErrorType(t, err, apperrors.ErrTypeBadRequest)
+}
+
+func TestVotePoll_OptionOfAnotherPoll(t *testing.T) {
+	ctx := context.Background()
+	_, repo := newPollRepo(t)
+	post := createPollPost(t, repo, "author")
+	other := createPollPost(t, repo, "author")
+
+	err := repo.VotePoll(ctx, post.ID, other.Poll.Options[0].ID, "voter")
+	requireErrorType(t, err, apperrors.ErrTypeBadRequest)
+
+	polls, err := repo.GetPolls(ctx, []string{post.ID, other.ID}, "voter")
+	require.NoError(t, err)
+	for _, poll := range polls {
+		assert.Equal(t, 0, *poll.TotalVotes)
+		assert.Nil(t, poll.ViewerOptionID)
+	}
+}
@@ -0,0 +1,57 @@
+// test/repository/reposts_test.go
+package repository_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/user/repository"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreate_RepostTwiceConflicts(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	posts := repository.NewPostRepository(db)
+	insertUser(t, db, "author")
+	insertUser(t, db, "reposter")
+	insertPost(t, db, "original", "author", base)
+
+	repost := func() error {
+		originalID := "original"
+		return posts.Create(ctx, &model.Post{
+			UserID:         "reposter",
+			Visibility:     model.VisibilityPublic,
+			Status:         model.PostStatusPublished,
+			Kind:           model.PostKindRepost,
+			OriginalPostID: &originalID,
+		})
+	}
+
+	require.NoError(t, repost())
+	requireErrorType(t, repost(), apperrors.ErrTypeConflict)
+}
+
+func TestCreate_DuplicateIDIsNotARepostConflict(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	posts := repository.NewPostRepository(db)
+	insertUser(t, db, "author")
+	insertPost(t, db, "post", "author", base)
+
+	err := posts.Create(ctx, &model.Post{
+		ID:         "post",
+		UserID:     "author",
+		Content:    "again",
+		Visibility: model.VisibilityPublic,
+		Status:     model.PostStatusPublished,
+	})
+	require.Error(t, err)
+
+	var appErr *apperrors.Error
+	assert.False(t, errors.As(err, &appErr), err.Error())
+}
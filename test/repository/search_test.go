@@ -0,0 +1,33 @@
+// test/repository/search_test.go
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/PeterM45/perfolio-api/internal/user/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearch_EscapesSnippet(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	insertUser(t, db, "alice")
+	insertPost(t, db, "alice-post", "alice", base)
+
+	_, err := db.ExecContext(ctx, `UPDATE post SET content = $1 WHERE id = $2`,
+		"<script>alert(1)</script> giraffe & friends", "alice-post")
+	require.NoError(t, err)
+
+	posts, err := repository.NewPostRepository(db).Search(ctx, "giraffe", "alice", nil, 10)
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	require.NotNil(t, posts[0].Search)
+
+	snippet := posts[0].Search.Snippet
+	assert.NotContains(t, snippet, "<script>")
+	assert.Contains(t, snippet, "&lt;script&gt;")
+	assert.Contains(t, snippet, "<mark>giraffe</mark> &amp; friends")
+	assert.NotContains(t, snippet, "")
+}
@@ -0,0 +1,240 @@
+// test/repository/timeline_test.go
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/platform/database"
+	"github.com/PeterM45/perfolio-api/internal/user/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTimelineDB creates an author followed by alice and bob, and carol, who
+// follows nobody
+func newTimelineDB(t *testing.T) *database.DB {
+	t.Helper()
+
+	db := openTestDB(t)
+	for _, id := range []string{"author", "alice", "bob", "carol"} {
+		insertUser(t, db, id)
+	}
+	insertFollow(t, db, "alice", "author", base)
+	insertFollow(t, db, "bob", "author", base)
+	return db
+}
+
+// timelineOwners returns the users whose timeline holds a post
+func timelineOwners(t *testing.T, db *database.DB, postID string) []string {
+	t.Helper()
+
+	rows, err := db.QueryContext(context.Background(),
+		`SELECT user_id FROM timeline WHERE post_id = $1 ORDER BY user_id`, postID)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var owners []string
+	for rows.Next() {
+		var owner string
+		require.NoError(t, rows.Scan(&owner))
+		owners = append(owners, owner)
+	}
+	require.NoError(t, rows.Err())
+	return owners
+}
+
+// createPost stores a published public post through the repository, which
+// leaves timelines to FanOut
+func createPost(t *testing.T, posts repository.PostRepository, userID string) *model.Post {
+	t.Helper()
+
+	post := &model.Post{
+		UserID:     userID,
+		Content:    "hello",
+		Visibility: model.VisibilityPublic,
+		Status:     model.PostStatusPublished,
+	}
+	require.NoError(t, posts.Create(context.Background(), post))
+	return post
+}
+
+func fanoutOnRead(t *testing.T, db *database.DB, postID string) bool {
+	t.Helper()
+
+	var onRead bool
+	err := db.QueryRowContext(context.Background(),
+		`SELECT fanout_on_read FROM post WHERE id = $1`, postID).Scan(&onRead)
+	require.NoError(t, err)
+	return onRead
+}
+
+func TestFanOut_WritesFollowerTimelines(t *testing.T) {
+	ctx := context.Background()
+	db := newTimelineDB(t)
+	posts := repository.NewPostRepository(db)
+
+	post := createPost(t, posts, "author")
+	assert.Empty(t, timelineOwners(t, db, post.ID))
+
+	fanned, err := posts.FanOut(ctx, post.ID, 2)
+	require.NoError(t, err)
+	assert.True(t, fanned)
+	assert.Equal(t, []string{"alice", "author", "bob"}, timelineOwners(t, db, post.ID))
+	assert.False(t, fanoutOnRead(t, db, post.ID))
+
+	// Fanning out again does not duplicate rows
+	_, err = posts.FanOut(ctx, post.ID, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alice", "author", "bob"}, timelineOwners(t, db, post.ID))
+
+	feed, err := posts.GetFeed(ctx, "alice", nil, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{post.ID}, postIDs(feed))
+}
+
+func TestFanOut_LargeAccountIsReadFromAuthor(t *testing.T) {
+	ctx := context.Background()
+	db := newTimelineDB(t)
+	posts := repository.NewPostRepository(db)
+
+	post := createPost(t, posts, "author")
+
+	fanned, err := posts.FanOut(ctx, post.ID, 1)
+	require.NoError(t, err)
+	assert.False(t, fanned)
+	assert.Equal(t, []string{"author"}, timelineOwners(t, db, post.ID))
+	assert.True(t, fanoutOnRead(t, db, post.ID))
+
+	// Followers still see the post, read from the author
+	for _, id := range []string{"alice", "bob", "author"} {
+		feed, err := posts.GetFeed(ctx, id, nil, 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{post.ID}, postIDs(feed), id)
+	}
+
+	feed, err := posts.GetFeed(ctx, "carol", nil, 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, feed)
+
+	onRead, err := posts.HasFanoutOnReadPosts(ctx, "author")
+	require.NoError(t, err)
+	assert.True(t, onRead)
+
+	authors, err := posts.GetFanoutOnReadAuthors(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"author"}, authors)
+
+	authors, err = posts.GetFanoutOnReadAuthors(ctx, "carol")
+	require.NoError(t, err)
+	assert.Empty(t, authors)
+}
+
+func TestAddFollow_BackfillsTimeline(t *testing.T) {
+	ctx := context.Background()
+	db := newTimelineDB(t)
+	users := repository.NewUserRepository(db)
+	posts := repository.NewPostRepository(db)
+
+	insertPost(t, db, "older", "author", base.Add(time.Minute))
+	insertPost(t, db, "newer", "author", base.Add(2*time.Minute))
+	insertPost(t, db, "deleted", "author", base.Add(3*time.Minute))
+	insertPost(t, db, "draft", "author", base.Add(4*time.Minute))
+	_, err := db.ExecContext(ctx, `UPDATE post SET deleted_at = $1 WHERE id = 'deleted'`, base.Add(time.Hour))
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `UPDATE post SET status = $1 WHERE id = 'draft'`, model.PostStatusDraft)
+	require.NoError(t, err)
+
+	require.NoError(t, users.AddFollow(ctx, "carol", "author"))
+
+	for _, id := range []string{"older", "newer"} {
+		assert.Contains(t, timelineOwners(t, db, id), "carol", id)
+	}
+	for _, id := range []string{"deleted", "draft"} {
+		assert.NotContains(t, timelineOwners(t, db, id), "carol", id)
+	}
+
+	feed, err := posts.GetFeed(ctx, "carol", nil, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"newer", "older"}, postIDs(feed))
+}
+
+func TestAddFollow_BackfillIsBounded(t *testing.T) {
+	ctx := context.Background()
+	db := newTimelineDB(t)
+	users := repository.NewUserRepository(db)
+
+	// One more post than the backfill takes
+	const backfill = 100
+	for i := 0; i <= backfill; i++ {
+		insertPost(t, db, fmt.Sprintf("p%03d", i), "author", base.Add(time.Duration(i)*time.Minute))
+	}
+
+	require.NoError(t, users.AddFollow(ctx, "carol", "author"))
+
+	var count int
+	err := db.QueryRowContext(ctx, `SELECT COUNT(*) FROM timeline WHERE user_id = 'carol'`).Scan(&count)
+	require.NoError(t, err)
+	assert.Equal(t, backfill, count)
+
+	// The oldest post is the one left out
+	assert.NotContains(t, timelineOwners(t, db, "p000"), "carol")
+}
+
+func TestRemoveFollow_ClearsTimeline(t *testing.T) {
+	ctx := context.Background()
+	db := newTimelineDB(t)
+	users := repository.NewUserRepository(db)
+	posts := repository.NewPostRepository(db)
+	insertUser(t, db, "other")
+	insertFollow(t, db, "alice", "other", base)
+
+	insertPost(t, db, "author-post", "author", base.Add(time.Minute))
+	insertPost(t, db, "other-post", "other", base.Add(2*time.Minute))
+
+	require.NoError(t, users.RemoveFollow(ctx, "alice", "author"))
+
+	assert.Equal(t, []string{"author", "bob"}, timelineOwners(t, db, "author-post"))
+	assert.Equal(t, []string{"alice", "other"}, timelineOwners(t, db, "other-post"))
+
+	feed, err := posts.GetFeed(ctx, "alice", nil, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"other-post"}, postIDs(feed))
+}
+
+func TestGetFeed_OffsetPagesAcrossSources(t *testing.T) {
+	ctx := context.Background()
+	db := newTimelineDB(t)
+	posts := repository.NewPostRepository(db)
+	insertUser(t, db, "celebrity")
+	insertFollow(t, db, "alice", "celebrity", base)
+
+	// Posts alternate between a fanned out author and one read on request
+	var want []string
+	for i := 8; i >= 1; i-- {
+		id := fmt.Sprintf("p%d", i)
+		authorID := "author"
+		if i%2 == 0 {
+			authorID = "celebrity"
+		}
+		insertPost(t, db, id, authorID, base.Add(time.Duration(i)*time.Minute))
+		want = append(want, id)
+	}
+	_, err := db.ExecContext(ctx, `UPDATE post SET fanout_on_read = TRUE WHERE user_id = 'celebrity'`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `DELETE FROM timeline WHERE author_id = 'celebrity' AND user_id <> author_id`)
+	require.NoError(t, err)
+
+	for limit := 1; limit <= 3; limit++ {
+		var got []string
+		for offset := 0; offset < len(want); offset += limit {
+			feed, err := posts.GetFeed(ctx, "alice", nil, limit, offset)
+			require.NoError(t, err)
+			got = append(got, postIDs(feed)...)
+		}
+		assert.Equal(t, want, got, "limit %d", limit)
+	}
+}
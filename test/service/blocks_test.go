@@ -0,0 +1,197 @@
+// test/service/blocks_test.go
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBlockEnv creates alice and bob, who follow each other and have posted
+// once each, and carol, who follows and is followed by both. Every read path
+// is cached before the test blocks or mutes anyone.
+func newBlockEnv(t *testing.T) *env {
+	t.Helper()
+
+	e := newEnv()
+	for _, id := range []string{"alice", "bob", "carol"} {
+		e.users.addUser(id, true)
+	}
+	e.follow("alice", "bob")
+	e.follow("bob", "alice")
+	for _, id := range []string{"alice", "bob"} {
+		e.follow(id, "carol")
+		e.follow("carol", id)
+	}
+
+	now := time.Now()
+	e.posts.addPost(&model.Post{ID: "alice-post", UserID: "alice", Content: "from alice", CreatedAt: now.Add(-2 * time.Minute)})
+	e.posts.addPost(&model.Post{ID: "bob-post", UserID: "bob", Content: "from bob", CreatedAt: now.Add(-time.Minute)})
+
+	assert.Equal(t, []string{"bob-post", "alice-post"}, feedIDs(t, e, "alice"))
+	assert.Equal(t, []string{"bob-post", "alice-post"}, feedIDs(t, e, "bob"))
+	return e
+}
+
+func feedIDs(t *testing.T, e *env, userID string) []string {
+	t.Helper()
+
+	posts, _, err := e.postService.GetFeed(context.Background(), userID, model.FeedModeLatest, "", 10, 0)
+	require.NoError(t, err)
+	return postIDs(posts)
+}
+
+func userIDs(users []*model.User) []string {
+	ids := make([]string, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	return ids
+}
+
+func TestBlockUser_HidesUsersFromEachOther(t *testing.T) {
+	ctx := context.Background()
+	e := newBlockEnv(t)
+
+	// Cache the reads a block must invalidate
+	_, err := e.postService.GetPostByID(ctx, "bob-post", "alice")
+	require.NoError(t, err)
+	_, _, err = e.postService.GetUserPosts(ctx, "bob", "alice", "", 10, 0)
+	require.NoError(t, err)
+	_, err = e.userService.GetProfile(ctx, "bob", "alice")
+	require.NoError(t, err)
+
+	require.NoError(t, e.userService.BlockUser(ctx, "alice", "bob"))
+
+	// Both directions are hidden, whoever blocked whom
+	for _, pair := range [][2]string{{"alice", "bob"}, {"bob", "alice"}} {
+		viewerID, otherID := pair[0], pair[1]
+
+		assert.Equal(t, []string{viewerID + "-post"}, feedIDs(t, e, viewerID), "feed of %s", viewerID)
+
+		_, err := e.postService.GetPostByID(ctx, otherID+"-post", viewerID)
+		requireErrorType(t, err, apperrors.ErrTypeNotFound)
+
+		_, _, err = e.postService.GetUserPosts(ctx, otherID, viewerID, "", 10, 0)
+		requireErrorType(t, err, apperrors.ErrTypeNotFound)
+
+		_, err = e.userService.GetProfile(ctx, otherID, viewerID)
+		requireErrorType(t, err, apperrors.ErrTypeNotFound)
+
+		_, err = e.userService.GetProfileByUsername(ctx, otherID, viewerID)
+		requireErrorType(t, err, apperrors.ErrTypeNotFound)
+
+		_, _, err = e.userService.GetFollowers(ctx, otherID, viewerID, "", 10, 0)
+		requireErrorType(t, err, apperrors.ErrTypeNotFound)
+
+		users, err := e.userService.SearchUsers(ctx, otherID, viewerID, 10)
+		require.NoError(t, err)
+		assert.NotContains(t, userIDs(users), otherID)
+
+		// Lists of third parties leave the other user out
+		followers, _, err := e.userService.GetFollowers(ctx, "carol", viewerID, "", 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{viewerID}, userIDs(followers))
+
+		following, _, err := e.userService.GetFollowing(ctx, "carol", viewerID, "", 10, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []string{viewerID}, userIDs(following))
+	}
+
+	// Everyone else still sees both
+	for _, id := range []string{"alice", "bob"} {
+		users, err := e.userService.SearchUsers(ctx, id, "carol", 10)
+		require.NoError(t, err)
+		assert.Contains(t, userIDs(users), id)
+	}
+
+	followers, _, err := e.userService.GetFollowers(ctx, "carol", "carol", "", 10, 0)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"alice", "bob"}, userIDs(followers))
+}
+
+func TestBlockUser_RemovesFollowsBothWays(t *testing.T) {
+	ctx := context.Background()
+	e := newBlockEnv(t)
+
+	// Cache the follow state and counts
+	for _, pair := range [][2]string{{"alice", "bob"}, {"bob", "alice"}} {
+		following, err := e.userService.IsFollowing(ctx, pair[0], pair[1])
+		require.NoError(t, err)
+		require.True(t, following)
+	}
+	stats, err := e.userService.GetProfileStats(ctx, "bob")
+	require.NoError(t, err)
+	require.Equal(t, 2, stats.FollowerCount)
+
+	require.NoError(t, e.userService.BlockUser(ctx, "alice", "bob"))
+
+	for _, pair := range [][2]string{{"alice", "bob"}, {"bob", "alice"}} {
+		following, err := e.userService.IsFollowing(ctx, pair[0], pair[1])
+		require.NoError(t, err)
+		assert.False(t, following, "%s follows %s", pair[0], pair[1])
+
+		err = e.userService.ToggleFollow(ctx, &model.FollowRequest{FollowingID: pair[1], Action: "follow"}, pair[0])
+		requireErrorType(t, err, apperrors.ErrTypeForbidden)
+	}
+
+	for _, id := range []string{"alice", "bob"} {
+		stats, err := e.userService.GetProfileStats(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, 1, stats.FollowerCount, id)
+		assert.Equal(t, 1, stats.FollowingCount, id)
+	}
+
+	// Lifting the block does not restore the follows
+	require.NoError(t, e.userService.UnblockUser(ctx, "alice", "bob"))
+
+	_, err = e.userService.GetProfile(ctx, "bob", "alice")
+	require.NoError(t, err)
+	following, err := e.userService.IsFollowing(ctx, "alice", "bob")
+	require.NoError(t, err)
+	assert.False(t, following)
+
+	e.follow("alice", "bob")
+	assert.Equal(t, []string{"bob-post", "alice-post"}, feedIDs(t, e, "alice"))
+}
+
+func TestMuteUser_HidesPostsFromFeedOnly(t *testing.T) {
+	ctx := context.Background()
+	e := newBlockEnv(t)
+
+	require.NoError(t, e.userService.MuteUser(ctx, "alice", "bob"))
+
+	assert.Equal(t, []string{"alice-post"}, feedIDs(t, e, "alice"))
+
+	// The mute is one-way and leaves everything else alone
+	assert.Equal(t, []string{"bob-post", "alice-post"}, feedIDs(t, e, "bob"))
+
+	_, err := e.postService.GetPostByID(ctx, "bob-post", "alice")
+	require.NoError(t, err)
+
+	posts, _, err := e.postService.GetUserPosts(ctx, "bob", "alice", "", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"bob-post"}, postIDs(posts))
+
+	following, err := e.userService.IsFollowing(ctx, "alice", "bob")
+	require.NoError(t, err)
+	assert.True(t, following)
+
+	require.NoError(t, e.userService.UnmuteUser(ctx, "alice", "bob"))
+	assert.Equal(t, []string{"bob-post", "alice-post"}, feedIDs(t, e, "alice"))
+}
+
+func TestBlockAndMute_RejectSelfAndUnknownUsers(t *testing.T) {
+	ctx := context.Background()
+	e := newBlockEnv(t)
+
+	requireErrorType(t, e.userService.BlockUser(ctx, "alice", "alice"), apperrors.ErrTypeBadRequest)
+	requireErrorType(t, e.userService.MuteUser(ctx, "alice", "alice"), apperrors.ErrTypeBadRequest)
+	requireErrorType(t, e.userService.BlockUser(ctx, "alice", "nobody"), apperrors.ErrTypeNotFound)
+	requireErrorType(t, e.userService.MuteUser(ctx, "alice", "nobody"), apperrors.ErrTypeNotFound)
+}
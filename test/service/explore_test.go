@@ -0,0 +1,136 @@
+// test/service/explore_test.go
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/user/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addExplorePosts adds a post for each author ID given, newest first, and
+// returns their IDs in that order
+func addExplorePosts(e *env, authorIDs ...string) []string {
+	now := time.Now()
+	ids := make([]string, 0, len(authorIDs))
+	for i, authorID := range authorIDs {
+		id := fmt.Sprintf("%s-%d", authorID, i)
+		e.posts.addPost(&model.Post{
+			ID:        id,
+			UserID:    authorID,
+			Content:   id,
+			CreatedAt: now.Add(-time.Duration(i+1) * time.Minute),
+		})
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func exploreIDs(t *testing.T, e *env, viewerID string) []string {
+	t.Helper()
+
+	posts, _, err := e.postService.GetExplore(context.Background(), viewerID, "", 100)
+	require.NoError(t, err)
+	return postIDs(posts)
+}
+
+func TestBuildExplore_CapsPostsPerAuthor(t *testing.T) {
+	e := newEnvWithConfig(service.FeedConfig{ExploreMaxPerAuthor: 2})
+	e.users.addUser("prolific", true)
+	e.users.addUser("quiet", true)
+	ids := addExplorePosts(e, "prolific", "prolific", "prolific", "quiet", "prolific")
+
+	built, err := e.postService.BuildExplore(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, built)
+
+	// The prolific author's best two posts are kept
+	assert.Equal(t, []string{ids[0], ids[1], ids[3]}, exploreIDs(t, e, ""))
+}
+
+func TestBuildExplore_LeavesOutPostsNotForEveryone(t *testing.T) {
+	e := newEnv()
+	e.users.addUser("author", true)
+	e.users.addUser("suspended", false)
+	ids := addExplorePosts(e, "author", "suspended")
+	e.posts.addPost(&model.Post{ID: "followers-only", UserID: "author", Visibility: model.VisibilityFollowers})
+	e.posts.addPost(&model.Post{ID: "draft", UserID: "author", Status: model.PostStatusDraft})
+	e.posts.addPost(&model.Post{ID: "old", UserID: "author", CreatedAt: time.Now().Add(-30 * 24 * time.Hour)})
+
+	assert.Equal(t, []string{ids[0]}, exploreIDs(t, e, ""))
+}
+
+func TestGetExplore_ExcludesKnownAuthors(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv()
+	for _, id := range []string{"viewer", "followed", "blocked", "muted", "stranger"} {
+		e.users.addUser(id, true)
+	}
+	e.follow("viewer", "followed")
+	require.NoError(t, e.userService.BlockUser(ctx, "blocked", "viewer"))
+	require.NoError(t, e.userService.MuteUser(ctx, "viewer", "muted"))
+	ids := addExplorePosts(e, "viewer", "followed", "blocked", "muted", "stranger")
+
+	// Mutes only apply to the home feed
+	assert.Equal(t, []string{ids[3], ids[4]}, exploreIDs(t, e, "viewer"))
+
+	// Signed-out readers see everything
+	assert.Equal(t, ids, exploreIDs(t, e, ""))
+}
+
+func TestGetExplore_ServedFromCacheUntilRebuilt(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv()
+	e.users.addUser("author", true)
+	e.users.addUser("late", true)
+	ids := addExplorePosts(e, "author")
+
+	_, err := e.postService.BuildExplore(ctx)
+	require.NoError(t, err)
+
+	e.posts.addPost(&model.Post{ID: "late-post", UserID: "late"})
+	assert.Equal(t, ids, exploreIDs(t, e, ""))
+
+	_, err = e.postService.BuildExplore(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"late-post", ids[0]}, exploreIDs(t, e, ""))
+
+	// Without a cached build, the first read builds it
+	e.posts.addPost(&model.Post{ID: "later-post", UserID: "late"})
+	e.cache.Delete("explore")
+	assert.Equal(t, []string{"later-post", "late-post", ids[0]}, exploreIDs(t, e, ""))
+}
+
+func TestGetExplore_Pages(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv()
+	authors := []string{"a", "b", "c", "d", "e"}
+	for _, id := range authors {
+		e.users.addUser(id, true)
+	}
+	want := addExplorePosts(e, authors...)
+
+	var got []string
+	cursor := ""
+	for page := 0; page <= len(want); page++ {
+		posts, nextCursor, err := e.postService.GetExplore(ctx, "", cursor, 2)
+		require.NoError(t, err)
+		got = append(got, postIDs(posts)...)
+
+		// A post published mid-way waits for the next build
+		e.users.addUser(fmt.Sprintf("new-%d", page), true)
+		e.posts.addPost(&model.Post{ID: fmt.Sprintf("new-%d", page), UserID: fmt.Sprintf("new-%d", page)})
+
+		if nextCursor == "" {
+			break
+		}
+		cursor = nextCursor
+	}
+
+	assert.Equal(t, want, got)
+}
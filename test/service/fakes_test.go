@@ -0,0 +1,853 @@
+// test/service/fakes_test.go
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/platform/cache"
+	"github.com/PeterM45/perfolio-api/internal/platform/moderation"
+	"github.com/PeterM45/perfolio-api/internal/user/interfaces"
+	"github.com/PeterM45/perfolio-api/internal/user/repository"
+	"github.com/PeterM45/perfolio-api/internal/user/service"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/PeterM45/perfolio-api/pkg/logger"
+	"github.com/PeterM45/perfolio-api/pkg/pagination"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeUserRepo keeps users and follows in memory. Methods the tests do not
+// need are left to the embedded interface and panic when called.
+type fakeUserRepo struct {
+	repository.UserRepository
+
+	mu      sync.Mutex
+	users   map[string]*model.User
+	follows map[string]map[string]time.Time // follower -> following -> followed at
+	blocks  map[[2]string]bool              // blocker, blocked
+	mutes   map[[2]string]bool              // muter, muted
+}
+
+func newFakeUserRepo() *fakeUserRepo {
+	return &fakeUserRepo{
+		users:   map[string]*model.User{},
+		follows: map[string]map[string]time.Time{},
+		blocks:  map[[2]string]bool{},
+		mutes:   map[[2]string]bool{},
+	}
+}
+
+func (r *fakeUserRepo) addUser(id string, active bool) *model.User {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user := &model.User{ID: id, Username: id, IsActive: active, CreatedAt: time.Now()}
+	r.users[id] = user
+	return user
+}
+
+func (r *fakeUserRepo) GetByID(_ context.Context, id string) (*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok {
+		return nil, apperrors.NotFound(fmt.Sprintf("user: %s", id))
+	}
+	copied := *user
+	return &copied, nil
+}
+
+func (r *fakeUserRepo) GetByUsername(ctx context.Context, username string) (*model.User, error) {
+	return r.GetByID(ctx, username)
+}
+
+func (r *fakeUserRepo) AddFollow(_ context.Context, followerID, followingID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.follows[followerID] == nil {
+		r.follows[followerID] = map[string]time.Time{}
+	}
+	r.follows[followerID][followingID] = time.Now()
+	return nil
+}
+
+func (r *fakeUserRepo) RemoveFollow(_ context.Context, followerID, followingID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.follows[followerID], followingID)
+	return nil
+}
+
+func (r *fakeUserRepo) IsFollowing(_ context.Context, followerID, followingID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.follows[followerID][followingID]
+	return ok, nil
+}
+
+func (r *fakeUserRepo) GetFollowerCount(_ context.Context, userID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := 0
+	for _, following := range r.follows {
+		if _, ok := following[userID]; ok {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeUserRepo) GetFollowingCount(_ context.Context, userID string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.follows[userID]), nil
+}
+
+func (r *fakeUserRepo) GetFollowers(_ context.Context, userID string, viewerID string, cursor *pagination.Cursor, limit, _ int) ([]*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var users []*model.User
+	for followerID, following := range r.follows {
+		if followedAt, ok := following[userID]; ok && !r.blocked(followerID, viewerID) {
+			user := *r.users[followerID]
+			user.FollowedAt = &followedAt
+			users = append(users, &user)
+		}
+	}
+
+	return pageUsers(users, cursor, limit), nil
+}
+
+func (r *fakeUserRepo) GetFollowing(_ context.Context, userID string, viewerID string, cursor *pagination.Cursor, limit, _ int) ([]*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var users []*model.User
+	for followingID, followedAt := range r.follows[userID] {
+		if r.blocked(followingID, viewerID) {
+			continue
+		}
+		followedAt := followedAt
+		user := *r.users[followingID]
+		user.FollowedAt = &followedAt
+		users = append(users, &user)
+	}
+
+	return pageUsers(users, cursor, limit), nil
+}
+
+func (r *fakeUserRepo) Search(_ context.Context, query string, viewerID string, limit int) ([]*model.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var users []*model.User
+	for id, user := range r.users {
+		if strings.Contains(user.Username, query) && !r.blocked(id, viewerID) {
+			copied := *user
+			users = append(users, &copied)
+		}
+	}
+
+	sort.Slice(users, func(i, j int) bool { return users[i].Username < users[j].Username })
+	if len(users) > limit {
+		users = users[:limit]
+	}
+	return users, nil
+}
+
+func (r *fakeUserRepo) Block(_ context.Context, blockerID, blockedID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.blocks[[2]string{blockerID, blockedID}] = true
+	delete(r.follows[blockerID], blockedID)
+	delete(r.follows[blockedID], blockerID)
+	return nil
+}
+
+func (r *fakeUserRepo) Unblock(_ context.Context, blockerID, blockedID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.blocks, [2]string{blockerID, blockedID})
+	return nil
+}
+
+func (r *fakeUserRepo) IsBlocked(_ context.Context, userID, otherID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.blocked(userID, otherID), nil
+}
+
+func (r *fakeUserRepo) Mute(_ context.Context, muterID, mutedID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.mutes[[2]string{muterID, mutedID}] = true
+	return nil
+}
+
+func (r *fakeUserRepo) Unmute(_ context.Context, muterID, mutedID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.mutes, [2]string{muterID, mutedID})
+	return nil
+}
+
+// blocked reports whether either user has blocked the other. The caller
+// holds the lock.
+func (r *fakeUserRepo) blocked(userID, otherID string) bool {
+	return r.blocks[[2]string{userID, otherID}] || r.blocks[[2]string{otherID, userID}]
+}
+
+// muted reports whether the muter has muted the other user
+func (r *fakeUserRepo) muted(muterID, mutedID string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.mutes[[2]string{muterID, mutedID}]
+}
+
+// pageUsers orders a follow list like the repository, most recent follow
+// first, and returns the page after the cursor
+func pageUsers(users []*model.User, cursor *pagination.Cursor, limit int) []*model.User {
+	sort.Slice(users, func(i, j int) bool {
+		if !users[i].FollowedAt.Equal(*users[j].FollowedAt) {
+			return users[i].FollowedAt.After(*users[j].FollowedAt)
+		}
+		return users[i].ID > users[j].ID
+	})
+
+	var page []*model.User
+	for _, user := range users {
+		if cursor != nil && !before(*user.FollowedAt, user.ID, cursor) {
+			continue
+		}
+		if len(page) == limit {
+			break
+		}
+		page = append(page, user)
+	}
+	return page
+}
+
+// before reports whether a row comes after the cursor in newest-first order
+func before(createdAt time.Time, id string, cursor *pagination.Cursor) bool {
+	if !createdAt.Equal(cursor.CreatedAt) {
+		return createdAt.Before(cursor.CreatedAt)
+	}
+	return id < cursor.ID
+}
+
+// fakePostRepo keeps posts in memory. Methods the tests do not need are left
+// to the embedded interface and panic when called.
+type fakePostRepo struct {
+	repository.PostRepository
+
+	mu    sync.Mutex
+	posts map[string]*model.Post
+	polls map[string]*model.Poll
+	votes map[string]map[string]string // post -> user -> option
+	users *fakeUserRepo
+	// onRead holds the posts FanOut left to be read from their author
+	onRead map[string]bool
+	// updateErr makes Update fail
+	updateErr error
+}
+
+func newFakePostRepo(users *fakeUserRepo) *fakePostRepo {
+	return &fakePostRepo{
+		posts:  map[string]*model.Post{},
+		polls:  map[string]*model.Poll{},
+		votes:  map[string]map[string]string{},
+		users:  users,
+		onRead: map[string]bool{},
+	}
+}
+
+func (r *fakePostRepo) addPost(post *model.Post) *model.Post {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if post.Status == "" {
+		post.Status = model.PostStatusPublished
+	}
+	if post.Visibility == "" {
+		post.Visibility = model.VisibilityPublic
+	}
+	if post.Kind == "" {
+		post.Kind = model.PostKindPost
+	}
+	if post.CreatedAt.IsZero() {
+		post.CreatedAt = time.Now()
+	}
+	copied := *post
+	copied.Poll = nil
+	r.posts[post.ID] = &copied
+
+	if post.Poll != nil {
+		poll := *post.Poll
+		poll.PostID = post.ID
+		r.polls[post.ID] = &poll
+	}
+	return post
+}
+
+func (r *fakePostRepo) Create(_ context.Context, post *model.Post) error {
+	r.addPost(post)
+	return nil
+}
+
+func (r *fakePostRepo) GetByID(_ context.Context, id string) (*model.Post, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	post, ok := r.posts[id]
+	if !ok || post.DeletedAt != nil {
+		return nil, apperrors.NotFound(fmt.Sprintf("post: %s", id))
+	}
+	copied := *post
+	return &copied, nil
+}
+
+func (r *fakePostRepo) Update(_ context.Context, post *model.Post) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.updateErr != nil {
+		return r.updateErr
+	}
+	if existing, ok := r.posts[post.ID]; !ok || existing.DeletedAt != nil {
+		return apperrors.NotFound(fmt.Sprintf("post: %s", post.ID))
+	}
+	copied := *post
+	copied.RevisionCount++
+	r.posts[post.ID] = &copied
+	return nil
+}
+
+// Delete moves a post to the trash, like the repository. Plain reposts are
+// removed outright.
+func (r *fakePostRepo) Delete(_ context.Context, id string) ([]*model.Post, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted, ok := r.posts[id]
+	if !ok || deleted.DeletedAt != nil {
+		return nil, apperrors.NotFound(fmt.Sprintf("post: %s", id))
+	}
+	if deleted.Kind == model.PostKindRepost {
+		delete(r.posts, id)
+	} else {
+		now := time.Now().UTC()
+		deleted.DeletedAt = &now
+		deleted.PinnedAt = nil
+	}
+
+	// Plain reposts go with the post
+	var reposts []*model.Post
+	for repostID, post := range r.posts {
+		if post.Kind == model.PostKindRepost && post.OriginalPostID != nil && *post.OriginalPostID == id {
+			reposts = append(reposts, post)
+			delete(r.posts, repostID)
+		}
+	}
+	return reposts, nil
+}
+
+func (r *fakePostRepo) Pin(_ context.Context, id string, userID string, maxPinned int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	post, ok := r.posts[id]
+	if !ok || post.UserID != userID || post.DeletedAt != nil {
+		return apperrors.NotFound(fmt.Sprintf("post: %s", id))
+	}
+	if post.PinnedAt != nil {
+		return nil
+	}
+
+	pinned := 0
+	for _, other := range r.posts {
+		if other.UserID == userID && other.PinnedAt != nil {
+			pinned++
+		}
+	}
+	if pinned >= maxPinned {
+		return apperrors.Conflict(fmt.Sprintf("you can pin at most %d posts", maxPinned))
+	}
+
+	now := time.Now().UTC()
+	post.Pinned = true
+	post.PinnedAt = &now
+	return nil
+}
+
+// setDeletedAt moves a trashed post's deletion time, to age it
+func (r *fakePostRepo) setDeletedAt(id string, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.posts[id].DeletedAt = &at
+}
+
+func (r *fakePostRepo) Restore(_ context.Context, id string, userID string, deletedAfter time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	post, ok := r.posts[id]
+	if !ok || post.UserID != userID || post.DeletedAt == nil || post.DeletedAt.Before(deletedAfter) {
+		return apperrors.NotFound(fmt.Sprintf("deleted post: %s", id))
+	}
+	post.DeletedAt = nil
+	return nil
+}
+
+// trashed returns copies of the trashed posts that match, most recently
+// deleted first
+func (r *fakePostRepo) trashed(match func(post *model.Post) bool) []*model.Post {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var posts []*model.Post
+	for _, post := range r.posts {
+		if post.DeletedAt != nil && match(post) {
+			copied := *post
+			posts = append(posts, &copied)
+		}
+	}
+
+	sort.Slice(posts, func(i, j int) bool {
+		if !posts[i].DeletedAt.Equal(*posts[j].DeletedAt) {
+			return posts[i].DeletedAt.After(*posts[j].DeletedAt)
+		}
+		return posts[i].ID > posts[j].ID
+	})
+	return posts
+}
+
+func (r *fakePostRepo) GetDeletedByUserID(_ context.Context, userID string, deletedAfter time.Time, limit, offset int) ([]*model.Post, error) {
+	posts := r.trashed(func(post *model.Post) bool {
+		return post.UserID == userID && !post.DeletedAt.Before(deletedAfter)
+	})
+	if offset >= len(posts) {
+		return nil, nil
+	}
+	posts = posts[offset:]
+	if len(posts) > limit {
+		posts = posts[:limit]
+	}
+	return posts, nil
+}
+
+// PurgeDeleted removes up to limit posts deleted before the given time,
+// oldest first
+func (r *fakePostRepo) PurgeDeleted(_ context.Context, deletedBefore time.Time, limit int) ([]*model.Post, error) {
+	posts := r.trashed(func(post *model.Post) bool {
+		return post.DeletedAt.Before(deletedBefore)
+	})
+	sort.SliceStable(posts, func(i, j int) bool {
+		return posts[i].DeletedAt.Before(*posts[j].DeletedAt)
+	})
+	if len(posts) > limit {
+		posts = posts[:limit]
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, post := range posts {
+		delete(r.posts, post.ID)
+	}
+	return posts, nil
+}
+
+func (r *fakePostRepo) GetByUserID(_ context.Context, userID string, viewerID string, _ *pagination.Cursor, limit, _ int) ([]*model.Post, error) {
+	return r.list(limit, func(post *model.Post) bool {
+		return post.UserID == userID && r.visible(post, viewerID)
+	}), nil
+}
+
+func (r *fakePostRepo) GetFeed(ctx context.Context, userID string, _ *pagination.Cursor, limit, _ int) ([]*model.Post, error) {
+	return r.list(limit, func(post *model.Post) bool {
+		following, _ := r.users.IsFollowing(ctx, userID, post.UserID)
+		return (post.UserID == userID || following) && !r.users.muted(userID, post.UserID) && r.visible(post, userID)
+	}), nil
+}
+
+func (r *fakePostRepo) GetVisibleByIDs(_ context.Context, ids []string, viewerID string) ([]*model.Post, error) {
+	wanted := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	return r.list(len(ids), func(post *model.Post) bool {
+		return wanted[post.ID] && r.visible(post, viewerID)
+	}), nil
+}
+
+// FanOut records which posts would be read from their author. The fake feed
+// is read from follows, so it has no timelines to write.
+func (r *fakePostRepo) FanOut(ctx context.Context, postID string, maxFollowers int) (bool, error) {
+	r.mu.Lock()
+	post, ok := r.posts[postID]
+	r.mu.Unlock()
+	if !ok {
+		return false, apperrors.NotFound(fmt.Sprintf("post: %s", postID))
+	}
+
+	followers, err := r.users.GetFollowerCount(ctx, post.UserID)
+	if err != nil {
+		return false, err
+	}
+
+	fanOut := followers <= maxFollowers
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onRead[postID] = !fanOut
+	return fanOut, nil
+}
+
+func (r *fakePostRepo) GetFanoutOnReadAuthors(ctx context.Context, userID string) ([]string, error) {
+	r.mu.Lock()
+	authors := map[string]bool{}
+	for id, onRead := range r.onRead {
+		if post, ok := r.posts[id]; ok && onRead {
+			authors[post.UserID] = true
+		}
+	}
+	r.mu.Unlock()
+
+	var authorIDs []string
+	for authorID := range authors {
+		if following, _ := r.users.IsFollowing(ctx, userID, authorID); following {
+			authorIDs = append(authorIDs, authorID)
+		}
+	}
+	sort.Strings(authorIDs)
+	return authorIDs, nil
+}
+
+func (r *fakePostRepo) HasFanoutOnReadPosts(_ context.Context, userID string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, onRead := range r.onRead {
+		if post, ok := r.posts[id]; ok && onRead && post.UserID == userID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakePostRepo) GetExploreCandidates(ctx context.Context, since time.Time, limit int) ([]*model.Post, error) {
+	return r.list(limit, func(post *model.Post) bool {
+		author, err := r.users.GetByID(ctx, post.UserID)
+		return err == nil && author.IsActive &&
+			!post.CreatedAt.Before(since) &&
+			post.Kind != model.PostKindRepost &&
+			post.Visibility == model.VisibilityPublic &&
+			post.HiddenAt == nil &&
+			post.Status == model.PostStatusPublished
+	}), nil
+}
+
+func (r *fakePostRepo) GetExcludedAuthors(ctx context.Context, viewerID string, authorIDs []string) (map[string]bool, error) {
+	excluded := map[string]bool{}
+	for _, authorID := range authorIDs {
+		following, _ := r.users.IsFollowing(ctx, viewerID, authorID)
+		blocked, _ := r.users.IsBlocked(ctx, viewerID, authorID)
+		if following || blocked {
+			excluded[authorID] = true
+		}
+	}
+	return excluded, nil
+}
+
+func (r *fakePostRepo) GetCommentCounts(context.Context, []string) (map[string]int, error) {
+	return map[string]int{}, nil
+}
+
+func (r *fakePostRepo) GetReactionSummaries(context.Context, []string, string) (map[string]*model.ReactionSummary, error) {
+	return map[string]*model.ReactionSummary{}, nil
+}
+
+func (r *fakePostRepo) GetLinkPreviews(context.Context, []string) (map[string]*model.LinkPreview, error) {
+	return map[string]*model.LinkPreview{}, nil
+}
+
+func (r *fakePostRepo) GetMedia(context.Context, []string) (map[string][]*model.Media, error) {
+	return map[string][]*model.Media{}, nil
+}
+
+// GetPolls returns copies of the polls with their full results, like the
+// repository
+func (r *fakePostRepo) GetPolls(_ context.Context, postIDs []string, viewerID string) (map[string]*model.Poll, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	polls := make(map[string]*model.Poll)
+	for _, postID := range postIDs {
+		stored, ok := r.polls[postID]
+		if !ok {
+			continue
+		}
+
+		poll := &model.Poll{PostID: postID, ClosesAt: stored.ClosesAt}
+		total := 0
+		for _, stored := range stored.Options {
+			option := *stored
+			count := 0
+			for _, optionID := range r.votes[postID] {
+				if optionID == option.ID {
+					count++
+				}
+			}
+			option.VoteCount = &count
+			total += count
+			poll.Options = append(poll.Options, &option)
+		}
+		poll.TotalVotes = &total
+
+		if optionID, ok := r.votes[postID][viewerID]; ok {
+			poll.ViewerOptionID = &optionID
+		}
+		polls[postID] = poll
+	}
+	return polls, nil
+}
+
+// VotePoll allows one vote per user and poll, like the primary key on
+// poll_vote
+func (r *fakePostRepo) VotePoll(_ context.Context, postID string, optionID string, userID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.votes[postID][userID]; ok {
+		return apperrors.Conflict("you have already voted in this poll")
+	}
+
+	poll, ok := r.polls[postID]
+	if !ok || !time.Now().Before(poll.ClosesAt) {
+		return apperrors.BadRequest("poll is closed or the option does not exist")
+	}
+
+	for _, option := range poll.Options {
+		if option.ID == optionID {
+			if r.votes[postID] == nil {
+				r.votes[postID] = map[string]string{}
+			}
+			r.votes[postID][userID] = optionID
+			return nil
+		}
+	}
+	return apperrors.BadRequest("poll is closed or the option does not exist")
+}
+
+// visible mirrors the visibility rules of the repository queries
+func (r *fakePostRepo) visible(post *model.Post, viewerID string) bool {
+	if post.UserID == viewerID {
+		return true
+	}
+	if post.Status != model.PostStatusPublished || post.HiddenAt != nil {
+		return false
+	}
+
+	if blocked, _ := r.users.IsBlocked(context.Background(), viewerID, post.UserID); blocked {
+		return false
+	}
+
+	switch post.Visibility {
+	case model.VisibilityPublic:
+		return true
+	case model.VisibilityFollowers:
+		following, _ := r.users.IsFollowing(context.Background(), viewerID, post.UserID)
+		return following
+	default:
+		return false
+	}
+}
+
+// list returns copies of up to limit posts that match, newest first, leaving
+// out trashed posts
+func (r *fakePostRepo) list(limit int, match func(post *model.Post) bool) []*model.Post {
+	r.mu.Lock()
+	var posts []*model.Post
+	for _, post := range r.posts {
+		if post.DeletedAt != nil {
+			continue
+		}
+		copied := *post
+		posts = append(posts, &copied)
+	}
+	r.mu.Unlock()
+
+	sort.Slice(posts, func(i, j int) bool {
+		if !posts[i].CreatedAt.Equal(posts[j].CreatedAt) {
+			return posts[i].CreatedAt.After(posts[j].CreatedAt)
+		}
+		return posts[i].ID > posts[j].ID
+	})
+
+	var result []*model.Post
+	for _, post := range posts {
+		if len(result) == limit {
+			break
+		}
+		if match(post) {
+			result = append(result, post)
+		}
+	}
+	return result
+}
+
+// fakeReportRepo keeps reports in memory. Resolving a report hides reported
+// posts in the post repository.
+type fakeReportRepo struct {
+	repository.ReportRepository
+
+	mu      sync.Mutex
+	reports []*model.Report
+	posts   *fakePostRepo
+}
+
+func (r *fakeReportRepo) Create(_ context.Context, report *model.Report) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if report.Status == "" {
+		report.Status = model.ReportStatusOpen
+	}
+	r.reports = append(r.reports, report)
+	return nil
+}
+
+func (r *fakeReportRepo) GetByID(_ context.Context, id string) (*model.Report, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, report := range r.reports {
+		if report.ID == id {
+			copied := *report
+			return &copied, nil
+		}
+	}
+	return nil, apperrors.NotFound(fmt.Sprintf("report: %s", id))
+}
+
+func (r *fakeReportRepo) Resolve(_ context.Context, action *model.ModerationAction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, report := range r.reports {
+		if report.ID != *action.ReportID {
+			continue
+		}
+		if report.Status != model.ReportStatusOpen {
+			return apperrors.Conflict("report has already been resolved")
+		}
+
+		action.TargetType = report.TargetType
+		action.TargetID = report.TargetID
+		action.TargetUserID = report.TargetUserID
+		report.Status = model.ReportStatusActioned
+
+		if action.Action == model.ModerationActionHide && report.TargetType == model.ReportTargetPost {
+			r.posts.mu.Lock()
+			now := time.Now()
+			r.posts.posts[report.TargetID].HiddenAt = &now
+			r.posts.mu.Unlock()
+		}
+		return nil
+	}
+	return apperrors.NotFound(fmt.Sprintf("report: %s", *action.ReportID))
+}
+
+// env wires the real user and post services to the fakes
+type env struct {
+	users   *fakeUserRepo
+	posts   *fakePostRepo
+	reports *fakeReportRepo
+	cache   cache.Cache
+
+	userService   interfaces.UserService
+	postService   interfaces.PostService
+	reportService interfaces.ReportService
+}
+
+// newEnv creates services over empty fakes. Posts containing a blocked word
+// are rejected by the content filter.
+func newEnv() *env {
+	return newEnvWithConfig(service.FeedConfig{})
+}
+
+// newEnvWithConfig is newEnv with the given feed settings
+func newEnvWithConfig(config service.FeedConfig) *env {
+	log := logger.NewLogger("error")
+	c := cache.NewInMemoryCache(time.Minute)
+	users := newFakeUserRepo()
+	posts := newFakePostRepo(users)
+	reports := &fakeReportRepo{posts: posts}
+
+	content := service.NewContentChecker(
+		moderation.NewChain(moderation.NewWordListFilter([]string{blockedWord}, nil)),
+		reports,
+		log,
+	)
+
+	userService := service.NewUserService(users, content, c, log)
+	postService := service.NewPostService(posts, userService, content, config, c, log)
+
+	return &env{
+		users:         users,
+		posts:         posts,
+		reports:       reports,
+		cache:         c,
+		userService:   userService,
+		postService:   postService,
+		reportService: service.NewReportService(reports, postService, userService, c, log),
+	}
+}
+
+// blockedWord is rejected by the content filter of newEnv
+const blockedWord = "forbiddenword"
+
+func (e *env) follow(followerID, followingID string) {
+	err := e.userService.ToggleFollow(context.Background(), &model.FollowRequest{
+		FollowingID: followingID,
+		Action:      "follow",
+	}, followerID)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// timelineVersion returns the cached timeline version of a user
+func (e *env) timelineVersion(userID string) string {
+	var version string
+	cache.GetInto(e.cache, "timeline_version:"+userID, &version)
+	return version
+}
+
+// requireErrorType asserts that err is an application error of the given type
+func requireErrorType(t *testing.T, err error, errorType apperrors.ErrorType) {
+	t.Helper()
+
+	var appErr *apperrors.Error
+	require.ErrorAs(t, err, &appErr)
+	require.Equal(t, errorType, appErr.Type(), appErr.Error())
+}
@@ -0,0 +1,190 @@
+// test/service/polls_test.go
+package service_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createPoll publishes a post by the author with a poll open for an hour
+func createPoll(t *testing.T, e *env, authorID string) *model.Post {
+	t.Helper()
+
+	post, err := e.postService.CreatePost(context.Background(), authorID, &model.CreatePostRequest{
+		Content: "Which one?",
+		Poll: &model.CreatePollRequest{
+			Options:  []string{"Tabs", "Spaces"},
+			ClosesAt: time.Now().Add(time.Hour),
+		},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, post.Poll)
+	return post
+}
+
+// closePoll moves the closing time of a poll into the past
+func closePoll(e *env, postID string) {
+	e.posts.mu.Lock()
+	defer e.posts.mu.Unlock()
+
+	e.posts.polls[postID].ClosesAt = time.Now().Add(-time.Minute)
+}
+
+func newPollEnv() *env {
+	e := newEnv()
+	e.users.addUser("author", true)
+	e.users.addUser("voter", true)
+	e.users.addUser("other", true)
+	return e
+}
+
+func TestVotePoll(t *testing.T) {
+	ctx := context.Background()
+	e := newPollEnv()
+	post := createPoll(t, e, "author")
+	optionID := post.Poll.Options[1].ID
+
+	poll, err := e.postService.VotePoll(ctx, post.ID, "voter", &model.PollVoteRequest{OptionID: optionID})
+	require.NoError(t, err)
+
+	require.NotNil(t, poll.ViewerOptionID)
+	assert.Equal(t, optionID, *poll.ViewerOptionID)
+	require.NotNil(t, poll.TotalVotes)
+	assert.Equal(t, 1, *poll.TotalVotes)
+	require.NotNil(t, poll.Options[1].VoteCount)
+	assert.Equal(t, 1, *poll.Options[1].VoteCount)
+}
+
+func TestVotePoll_Twice(t *testing.T) {
+	ctx := context.Background()
+	e := newPollEnv()
+	post := createPoll(t, e, "author")
+
+	_, err := e.postService.VotePoll(ctx, post.ID, "voter", &model.PollVoteRequest{OptionID: post.Poll.Options[0].ID})
+	require.NoError(t, err)
+
+	// Changing the vote is not allowed either
+	for _, option := range post.Poll.Options {
+		_, err = e.postService.VotePoll(ctx, post.ID, "voter", &model.PollVoteRequest{OptionID: option.ID})
+		requireErrorType(t, err, apperrors.ErrTypeConflict)
+	}
+
+	poll, err := e.postService.GetPostByID(ctx, post.ID, "voter")
+	require.NoError(t, err)
+	assert.Equal(t, 1, *poll.Poll.TotalVotes)
+}
+
+func TestVotePoll_Concurrent(t *testing.T) {
+	ctx := context.Background()
+	e := newPollEnv()
+	post := createPoll(t, e, "author")
+
+	const attempts = 20
+	errs := make(chan error, attempts)
+
+	var wg sync.WaitGroup
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			option := post.Poll.Options[i%len(post.Poll.Options)]
+			_, err := e.postService.VotePoll(ctx, post.ID, "voter", &model.PollVoteRequest{OptionID: option.ID})
+			errs <- err
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+
+	succeeded := 0
+	for err := range errs {
+		if err == nil {
+			succeeded++
+			continue
+		}
+		requireErrorType(t, err, apperrors.ErrTypeConflict)
+	}
+	assert.Equal(t, 1, succeeded)
+
+	poll, err := e.postService.GetPostByID(ctx, post.ID, "voter")
+	require.NoError(t, err)
+	assert.Equal(t, 1, *poll.Poll.TotalVotes)
+}
+
+func TestVotePoll_Closed(t *testing.T) {
+	ctx := context.Background()
+	e := newPollEnv()
+	post := createPoll(t, e, "author")
+	closePoll(e, post.ID)
+
+	_, err := e.postService.VotePoll(ctx, post.ID, "voter", &model.PollVoteRequest{OptionID: post.Poll.Options[0].ID})
+	requireErrorType(t, err, apperrors.ErrTypeBadRequest)
+	assert.Contains(t, err.Error(), "closed")
+}
+
+func TestVotePoll_OptionOfAnotherPoll(t *testing.T) {
+	ctx := context.Background()
+	e := newPollEnv()
+	post := createPoll(t, e, "author")
+	other := createPoll(t, e, "author")
+
+	_, err := e.postService.VotePoll(ctx, post.ID, "voter", &model.PollVoteRequest{OptionID: other.Poll.Options[0].ID})
+	requireErrorType(t, err, apperrors.ErrTypeBadRequest)
+
+	// Neither poll counted the vote
+	for _, id := range []string{post.ID, other.ID} {
+		closePoll(e, id)
+		got, err := e.postService.GetPostByID(ctx, id, "voter")
+		require.NoError(t, err)
+		assert.Equal(t, 0, *got.Poll.TotalVotes)
+		assert.Nil(t, got.Poll.ViewerOptionID)
+	}
+}
+
+func TestPollResults_HiddenUntilViewerVotes(t *testing.T) {
+	ctx := context.Background()
+	e := newPollEnv()
+	post := createPoll(t, e, "author")
+
+	_, err := e.postService.VotePoll(ctx, post.ID, "other", &model.PollVoteRequest{OptionID: post.Poll.Options[0].ID})
+	require.NoError(t, err)
+
+	// Someone else's vote does not reveal the results
+	got, err := e.postService.GetPostByID(ctx, post.ID, "voter")
+	require.NoError(t, err)
+	assert.False(t, got.Poll.Closed)
+	assert.Nil(t, got.Poll.TotalVotes)
+	for _, option := range got.Poll.Options {
+		assert.Nil(t, option.VoteCount)
+	}
+
+	// Nor do they show to anonymous visitors
+	got, err = e.postService.GetPostByID(ctx, post.ID, "")
+	require.NoError(t, err)
+	assert.Nil(t, got.Poll.TotalVotes)
+
+	// Voting does
+	_, err = e.postService.VotePoll(ctx, post.ID, "voter", &model.PollVoteRequest{OptionID: post.Poll.Options[1].ID})
+	require.NoError(t, err)
+
+	got, err = e.postService.GetPostByID(ctx, post.ID, "voter")
+	require.NoError(t, err)
+	require.NotNil(t, got.Poll.TotalVotes)
+	assert.Equal(t, 2, *got.Poll.TotalVotes)
+	assert.Equal(t, 1, *got.Poll.Options[0].VoteCount)
+	assert.Equal(t, 1, *got.Poll.Options[1].VoteCount)
+
+	// And so does the poll closing
+	closePoll(e, post.ID)
+	got, err = e.postService.GetPostByID(ctx, post.ID, "")
+	require.NoError(t, err)
+	assert.True(t, got.Poll.Closed)
+	require.NotNil(t, got.Poll.TotalVotes)
+	assert.Equal(t, 2, *got.Poll.TotalVotes)
+}
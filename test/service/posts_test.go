@@ -0,0 +1,94 @@
+// test/service/posts_test.go
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdatePost_RejectedEditKeepsCachedPost(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv()
+	e.users.addUser("author", true)
+	e.users.addUser("stranger", true)
+	e.posts.addPost(&model.Post{ID: "post", UserID: "author", Content: "draft notes", Visibility: model.VisibilityPrivate})
+
+	// Load the post so the edit starts from the cached copy
+	_, err := e.postService.GetPostByID(ctx, "post", "author")
+	require.NoError(t, err)
+
+	_, err = e.postService.UpdatePost(ctx, "post", "author", &model.UpdatePostRequest{
+		Content:    "now with " + blockedWord,
+		Visibility: model.VisibilityPublic,
+	})
+	requireErrorType(t, err, apperrors.ErrTypeBadRequest)
+
+	_, err = e.postService.GetPostByID(ctx, "post", "stranger")
+	requireErrorType(t, err, apperrors.ErrTypeNotFound)
+
+	post, err := e.postService.GetPostByID(ctx, "post", "author")
+	require.NoError(t, err)
+	assert.Equal(t, model.VisibilityPrivate, post.Visibility)
+	assert.Equal(t, "draft notes", post.Content)
+}
+
+func TestUpdatePost_FailedSaveKeepsCachedPost(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv()
+	e.users.addUser("author", true)
+	e.users.addUser("stranger", true)
+	e.posts.addPost(&model.Post{ID: "post", UserID: "author", Content: "draft notes", Visibility: model.VisibilityPrivate})
+
+	_, err := e.postService.GetPostByID(ctx, "post", "author")
+	require.NoError(t, err)
+
+	e.posts.updateErr = assert.AnError
+	_, err = e.postService.UpdatePost(ctx, "post", "author", &model.UpdatePostRequest{
+		Content:    "published notes",
+		Visibility: model.VisibilityPublic,
+	})
+	require.ErrorIs(t, err, assert.AnError)
+
+	_, err = e.postService.GetPostByID(ctx, "post", "stranger")
+	requireErrorType(t, err, apperrors.ErrTypeNotFound)
+}
+
+func TestPinPost_ReturnsFreshPost(t *testing.T) {
+	ctx := context.Background()
+	e := newPollEnv()
+	post := createPoll(t, e, "author")
+
+	// Cache the post before pinning it
+	cached, err := e.postService.GetPostByID(ctx, post.ID, "author")
+	require.NoError(t, err)
+	require.False(t, cached.Pinned)
+
+	pinned, err := e.postService.PinPost(ctx, post.ID, "author")
+	require.NoError(t, err)
+	assert.True(t, pinned.Pinned)
+	assert.NotNil(t, pinned.PinnedAt)
+	require.NotNil(t, pinned.Poll)
+	assert.Len(t, pinned.Poll.Options, 2)
+
+	// The copy read before is left as it was
+	assert.False(t, cached.Pinned)
+
+	posts, _, err := e.postService.GetUserPosts(ctx, "author", "author", "", 10, 0)
+	require.NoError(t, err)
+	require.Len(t, posts, 1)
+	assert.True(t, posts[0].Pinned)
+}
+
+func TestPinPost_SomeoneElsesPost(t *testing.T) {
+	ctx := context.Background()
+	e := newPollEnv()
+	post := createPoll(t, e, "author")
+
+	_, err := e.postService.PinPost(ctx, post.ID, "other")
+	requireErrorType(t, err, apperrors.ErrTypeForbidden)
+}
@@ -0,0 +1,51 @@
+// test/service/reports_test.go
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveReport_HiddenPostLeavesCachedTimelines(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv()
+	e.users.addUser("author", true)
+	e.users.addUser("follower", true)
+	e.users.addUser("reporter", true)
+	e.users.addUser("moderator", true)
+	e.follow("follower", "author")
+	e.posts.addPost(&model.Post{ID: "post", UserID: "author", Content: "hello"})
+
+	// Cache the timelines that hold the post
+	feed, _, err := e.postService.GetFeed(ctx, "follower", model.FeedModeLatest, "", 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"post"}, postIDs(feed))
+
+	profile, _, err := e.postService.GetUserPosts(ctx, "author", "reporter", "", 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, []string{"post"}, postIDs(profile))
+
+	report, err := e.reportService.CreateReport(ctx, "reporter", &model.CreateReportRequest{
+		TargetType: model.ReportTargetPost,
+		TargetID:   "post",
+		Reason:     model.ReportReasonSpam,
+	})
+	require.NoError(t, err)
+
+	_, err = e.reportService.ResolveReport(ctx, report.ID, "moderator", &model.ModerationActionRequest{
+		Action: model.ModerationActionHide,
+	})
+	require.NoError(t, err)
+
+	feed, _, err = e.postService.GetFeed(ctx, "follower", model.FeedModeLatest, "", 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, feed)
+
+	profile, _, err = e.postService.GetUserPosts(ctx, "author", "reporter", "", 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, profile)
+}
@@ -0,0 +1,43 @@
+// test/service/reposts_test.go
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepost(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv()
+	e.users.addUser("author", true)
+	e.users.addUser("reposter", true)
+	e.posts.addPost(&model.Post{ID: "post", UserID: "author", Content: "hello"})
+
+	repost, err := e.postService.Repost(ctx, "post", "reposter", &model.RepostRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, model.PostKindRepost, repost.Kind)
+	require.NotNil(t, repost.OriginalPostID)
+	assert.Equal(t, "post", *repost.OriginalPostID)
+}
+
+func TestRepost_SuspendedUser(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv()
+	e.users.addUser("author", true)
+	e.users.addUser("suspended", false)
+	e.posts.addPost(&model.Post{ID: "post", UserID: "author", Content: "hello"})
+
+	for _, req := range []*model.RepostRequest{{}, {Quote: "look at this"}} {
+		_, err := e.postService.Repost(ctx, "post", "suspended", req)
+		requireErrorType(t, err, apperrors.ErrTypeForbidden)
+	}
+
+	posts, _, err := e.postService.GetUserPosts(ctx, "suspended", "suspended", "", 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, posts)
+}
@@ -0,0 +1,261 @@
+// test/service/timelines_test.go
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/internal/user/service"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInvalidateFollowerTimelines(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv()
+	e.users.addUser("author", true)
+	e.users.addUser("follower", true)
+	e.users.addUser("stranger", true)
+	e.follow("follower", "author")
+
+	authorVersion := e.timelineVersion("author")
+	followerVersion := e.timelineVersion("follower")
+	strangerVersion := e.timelineVersion("stranger")
+
+	_, err := e.postService.CreatePost(ctx, "author", &model.CreatePostRequest{Content: "hello"})
+	require.NoError(t, err)
+
+	// Only the author's version changes with the write
+	assert.NotEqual(t, authorVersion, e.timelineVersion("author"))
+	assert.Equal(t, followerVersion, e.timelineVersion("follower"))
+
+	handled, err := e.postService.InvalidateFollowerTimelines(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, handled)
+	assert.NotEqual(t, followerVersion, e.timelineVersion("follower"))
+	assert.Equal(t, strangerVersion, e.timelineVersion("stranger"))
+
+	// Nothing is left for the next run
+	handled, err = e.postService.InvalidateFollowerTimelines(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, handled)
+}
+
+func TestInvalidateFollowerTimelines_CollapsesWrites(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv()
+	e.users.addUser("author", true)
+	e.users.addUser("follower", true)
+	e.follow("follower", "author")
+
+	for i := 0; i < 3; i++ {
+		_, err := e.postService.CreatePost(ctx, "author", &model.CreatePostRequest{Content: "hello"})
+		require.NoError(t, err)
+	}
+
+	handled, err := e.postService.InvalidateFollowerTimelines(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, handled)
+}
+
+func TestInvalidateFollowerTimelines_SkipsLargeAccounts(t *testing.T) {
+	ctx := context.Background()
+	e := newEnvWithConfig(service.FeedConfig{FanOutMaxFollowers: 1})
+	e.users.addUser("author", true)
+	e.users.addUser("follower-1", true)
+	e.users.addUser("follower-2", true)
+	e.follow("follower-1", "author")
+	e.follow("follower-2", "author")
+
+	versions := map[string]string{
+		"follower-1": e.timelineVersion("follower-1"),
+		"follower-2": e.timelineVersion("follower-2"),
+	}
+
+	_, err := e.postService.CreatePost(ctx, "author", &model.CreatePostRequest{Content: "hello"})
+	require.NoError(t, err)
+
+	handled, err := e.postService.InvalidateFollowerTimelines(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, handled)
+
+	// Their feeds follow the author's version, like fan-out is left to reads
+	for followerID, version := range versions {
+		assert.Equal(t, version, e.timelineVersion(followerID), followerID)
+	}
+}
+
+func TestFanOut_SwitchesToReadAboveMaxFollowers(t *testing.T) {
+	ctx := context.Background()
+	e := newEnvWithConfig(service.FeedConfig{FanOutMaxFollowers: 1})
+	e.users.addUser("author", true)
+	e.users.addUser("follower-1", true)
+	e.users.addUser("follower-2", true)
+	e.follow("follower-1", "author")
+
+	small, err := e.postService.CreatePost(ctx, "author", &model.CreatePostRequest{Content: "small"})
+	require.NoError(t, err)
+	assert.False(t, e.posts.onRead[small.ID])
+
+	e.follow("follower-2", "author")
+
+	large, err := e.postService.CreatePost(ctx, "author", &model.CreatePostRequest{Content: "large"})
+	require.NoError(t, err)
+	assert.True(t, e.posts.onRead[large.ID])
+}
+
+func TestTimelines_RefreshAfterWritesOfLargeAccounts(t *testing.T) {
+	ctx := context.Background()
+	e := newEnvWithConfig(service.FeedConfig{FanOutMaxFollowers: 1})
+	e.users.addUser("author", true)
+	e.users.addUser("follower", true)
+	e.users.addUser("other", true)
+	e.follow("follower", "author")
+	e.follow("other", "author")
+
+	post, err := e.postService.CreatePost(ctx, "author", &model.CreatePostRequest{Content: "first draft"})
+	require.NoError(t, err)
+	_, err = e.postService.InvalidateFollowerTimelines(ctx)
+	require.NoError(t, err)
+
+	feed, _ := readTimelines(t, e)
+	require.Equal(t, []string{"first draft"}, feed)
+
+	// The follower's version stays put, but their feed follows the author's
+	followerVersion := e.timelineVersion("follower")
+
+	_, err = e.postService.UpdatePost(ctx, post.ID, "author", &model.UpdatePostRequest{Content: "second draft"})
+	require.NoError(t, err)
+	_, err = e.postService.InvalidateFollowerTimelines(ctx)
+	require.NoError(t, err)
+
+	feed, _ = readTimelines(t, e)
+	assert.Equal(t, []string{"second draft"}, feed)
+	assert.Equal(t, followerVersion, e.timelineVersion("follower"))
+
+	require.NoError(t, e.postService.DeletePost(ctx, post.ID, "author"))
+	_, err = e.postService.InvalidateFollowerTimelines(ctx)
+	require.NoError(t, err)
+
+	feed, _ = readTimelines(t, e)
+	assert.Empty(t, feed)
+}
+
+func TestInvalidateFollowerTimelines_LargeAccountWithoutPostsReadFromThem(t *testing.T) {
+	ctx := context.Background()
+	e := newEnvWithConfig(service.FeedConfig{FanOutMaxFollowers: 1})
+	e.users.addUser("author", true)
+	e.users.addUser("follower-1", true)
+	e.users.addUser("follower-2", true)
+	e.follow("follower-1", "author")
+
+	post, err := e.postService.CreatePost(ctx, "author", &model.CreatePostRequest{Content: "hello"})
+	require.NoError(t, err)
+
+	// The author outgrew fan-out after their last post, which followers still
+	// hold on their timelines
+	e.follow("follower-2", "author")
+	_, err = e.postService.InvalidateFollowerTimelines(ctx)
+	require.NoError(t, err)
+	version := e.timelineVersion("follower-1")
+
+	_, err = e.postService.UpdatePost(ctx, post.ID, "author", &model.UpdatePostRequest{Content: "edited"})
+	require.NoError(t, err)
+	_, err = e.postService.InvalidateFollowerTimelines(ctx)
+	require.NoError(t, err)
+
+	assert.NotEqual(t, version, e.timelineVersion("follower-1"))
+}
+
+// readTimelines reads, and caches, the follower's feed and the author's posts
+// as seen by the follower, and returns the content of their posts
+func readTimelines(t *testing.T, e *env) (feed []string, profile []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	posts, _, err := e.postService.GetFeed(ctx, "follower", model.FeedModeLatest, "", 10, 0)
+	require.NoError(t, err)
+	for _, post := range posts {
+		feed = append(feed, post.Content)
+	}
+
+	posts, _, err = e.postService.GetUserPosts(ctx, "author", "follower", "", 10, 0)
+	require.NoError(t, err)
+	for _, post := range posts {
+		profile = append(profile, post.Content)
+	}
+
+	return feed, profile
+}
+
+func TestTimelines_RefreshAfterWrites(t *testing.T) {
+	ctx := context.Background()
+	e := newEnv()
+	e.users.addUser("author", true)
+	e.users.addUser("follower", true)
+	e.follow("follower", "author")
+
+	// writeAndRead runs a write and the invalidator, checks that both versions
+	// moved on and returns the timelines read afterwards
+	writeAndRead := func(write func()) ([]string, []string) {
+		t.Helper()
+
+		authorVersion := e.timelineVersion("author")
+		followerVersion := e.timelineVersion("follower")
+
+		write()
+		_, err := e.postService.InvalidateFollowerTimelines(ctx)
+		require.NoError(t, err)
+
+		assert.NotEqual(t, authorVersion, e.timelineVersion("author"))
+		assert.NotEqual(t, followerVersion, e.timelineVersion("follower"))
+		return readTimelines(t, e)
+	}
+
+	feed, profile := readTimelines(t, e)
+	assert.Empty(t, feed)
+	assert.Empty(t, profile)
+
+	var post *model.Post
+	feed, profile = writeAndRead(func() {
+		var err error
+		post, err = e.postService.CreatePost(ctx, "author", &model.CreatePostRequest{Content: "first draft"})
+		require.NoError(t, err)
+	})
+	assert.Equal(t, []string{"first draft"}, feed)
+	assert.Equal(t, []string{"first draft"}, profile)
+
+	feed, profile = writeAndRead(func() {
+		_, err := e.postService.UpdatePost(ctx, post.ID, "author", &model.UpdatePostRequest{Content: "second draft"})
+		require.NoError(t, err)
+	})
+	assert.Equal(t, []string{"second draft"}, feed)
+	assert.Equal(t, []string{"second draft"}, profile)
+
+	feed, profile = writeAndRead(func() {
+		require.NoError(t, e.postService.DeletePost(ctx, post.ID, "author"))
+	})
+	assert.Empty(t, feed)
+	assert.Empty(t, profile)
+}
+
+// Guards the test above: without invalidation, pages are read from the cache
+func TestTimelines_ServedFromCache(t *testing.T) {
+	e := newEnv()
+	e.users.addUser("author", true)
+	e.users.addUser("follower", true)
+	e.follow("follower", "author")
+	e.posts.addPost(&model.Post{ID: "post", UserID: "author", Content: "cached"})
+
+	feed, _ := readTimelines(t, e)
+	require.Equal(t, []string{"cached"}, feed)
+
+	// A change that bypasses the service is not seen while the page is cached
+	e.posts.mu.Lock()
+	e.posts.posts["post"].Content = "changed"
+	e.posts.mu.Unlock()
+
+	feed, _ = readTimelines(t, e)
+	assert.Equal(t, []string{"cached"}, feed)
+}
@@ -0,0 +1,153 @@
+// test/service/trash_test.go
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTrashEnv creates an author with a post in their trash and one that is
+// still published
+func newTrashEnv(t *testing.T) *env {
+	t.Helper()
+
+	e := newEnv()
+	e.users.addUser("author", true)
+	e.users.addUser("other", true)
+	e.posts.addPost(&model.Post{ID: "trashed", UserID: "author", Content: "trashed"})
+	e.posts.addPost(&model.Post{ID: "kept", UserID: "author", Content: "kept"})
+
+	require.NoError(t, e.postService.DeletePost(context.Background(), "trashed", "author"))
+	return e
+}
+
+func TestDeletePost_MovesToTrash(t *testing.T) {
+	ctx := context.Background()
+	e := newTrashEnv(t)
+
+	_, err := e.postService.GetPostByID(ctx, "trashed", "author")
+	requireErrorType(t, err, apperrors.ErrTypeNotFound)
+
+	posts, _, err := e.postService.GetUserPosts(ctx, "author", "author", "", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"kept"}, postIDs(posts))
+
+	trash, err := e.postService.GetTrash(ctx, "author", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"trashed"}, postIDs(trash))
+
+	// Deleting it again finds nothing to delete
+	err = e.postService.DeletePost(ctx, "trashed", "author")
+	requireErrorType(t, err, apperrors.ErrTypeNotFound)
+}
+
+func TestGetTrash_OnlyOwnPostsWithinRetention(t *testing.T) {
+	ctx := context.Background()
+	e := newTrashEnv(t)
+	e.posts.addPost(&model.Post{ID: "expired", UserID: "author", Content: "expired"})
+	e.posts.addPost(&model.Post{ID: "other-post", UserID: "other", Content: "other"})
+	require.NoError(t, e.postService.DeletePost(ctx, "expired", "author"))
+	require.NoError(t, e.postService.DeletePost(ctx, "other-post", "other"))
+	e.posts.setDeletedAt("expired", time.Now().Add(-model.TrashRetention-time.Hour))
+
+	trash, err := e.postService.GetTrash(ctx, "author", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"trashed"}, postIDs(trash))
+}
+
+func TestRestorePost(t *testing.T) {
+	ctx := context.Background()
+	e := newTrashEnv(t)
+
+	// Just inside the window
+	e.posts.setDeletedAt("trashed", time.Now().Add(-model.TrashRetention+time.Hour))
+
+	post, err := e.postService.RestorePost(ctx, "trashed", "author")
+	require.NoError(t, err)
+	assert.Equal(t, "trashed", post.ID)
+	assert.Nil(t, post.DeletedAt)
+
+	posts, _, err := e.postService.GetUserPosts(ctx, "author", "author", "", 10, 0)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"trashed", "kept"}, postIDs(posts))
+
+	trash, err := e.postService.GetTrash(ctx, "author", 10, 0)
+	require.NoError(t, err)
+	assert.Empty(t, trash)
+}
+
+func TestRestorePost_AfterRetention(t *testing.T) {
+	ctx := context.Background()
+	e := newTrashEnv(t)
+	e.posts.setDeletedAt("trashed", time.Now().Add(-model.TrashRetention-time.Hour))
+
+	_, err := e.postService.RestorePost(ctx, "trashed", "author")
+	requireErrorType(t, err, apperrors.ErrTypeNotFound)
+
+	_, err = e.postService.GetPostByID(ctx, "trashed", "author")
+	requireErrorType(t, err, apperrors.ErrTypeNotFound)
+}
+
+func TestRestorePost_SomeoneElsesPost(t *testing.T) {
+	ctx := context.Background()
+	e := newTrashEnv(t)
+
+	_, err := e.postService.RestorePost(ctx, "trashed", "other")
+	requireErrorType(t, err, apperrors.ErrTypeNotFound)
+
+	// It stays in its author's trash
+	trash, err := e.postService.GetTrash(ctx, "author", 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"trashed"}, postIDs(trash))
+}
+
+func TestRestorePost_NotDeleted(t *testing.T) {
+	ctx := context.Background()
+	e := newTrashEnv(t)
+
+	_, err := e.postService.RestorePost(ctx, "kept", "author")
+	requireErrorType(t, err, apperrors.ErrTypeNotFound)
+}
+
+func TestPurgeDeletedPosts_RemovesOnlyExpired(t *testing.T) {
+	ctx := context.Background()
+	e := newTrashEnv(t)
+	for _, id := range []string{"expired-1", "expired-2"} {
+		e.posts.addPost(&model.Post{ID: id, UserID: "author", Content: id})
+		require.NoError(t, e.postService.DeletePost(ctx, id, "author"))
+		e.posts.setDeletedAt(id, time.Now().Add(-model.TrashRetention-time.Hour))
+	}
+
+	// Runs are bounded by their limit
+	purged, err := e.postService.PurgeDeletedPosts(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	purged, err = e.postService.PurgeDeletedPosts(ctx, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	purged, err = e.postService.PurgeDeletedPosts(ctx, 10)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+
+	e.posts.mu.Lock()
+	_, expired1 := e.posts.posts["expired-1"]
+	_, expired2 := e.posts.posts["expired-2"]
+	e.posts.mu.Unlock()
+	assert.False(t, expired1)
+	assert.False(t, expired2)
+
+	// Posts still in the window, and live ones, are left alone
+	_, err = e.postService.GetPostByID(ctx, "kept", "author")
+	require.NoError(t, err)
+
+	_, err = e.postService.RestorePost(ctx, "trashed", "author")
+	require.NoError(t, err)
+}
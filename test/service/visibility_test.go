@@ -0,0 +1,91 @@
+// test/service/visibility_test.go
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/common/model"
+	"github.com/PeterM45/perfolio-api/pkg/apperrors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newVisibilityEnv creates an author with one post of each visibility, a
+// follower and a stranger
+func newVisibilityEnv() *env {
+	e := newEnv()
+	e.users.addUser("author", true)
+	e.users.addUser("follower", true)
+	e.users.addUser("stranger", true)
+	e.follow("follower", "author")
+
+	now := time.Now()
+	e.posts.addPost(&model.Post{ID: "public", UserID: "author", Visibility: model.VisibilityPublic, CreatedAt: now.Add(-3 * time.Minute)})
+	e.posts.addPost(&model.Post{ID: "followers", UserID: "author", Visibility: model.VisibilityFollowers, CreatedAt: now.Add(-2 * time.Minute)})
+	e.posts.addPost(&model.Post{ID: "private", UserID: "author", Visibility: model.VisibilityPrivate, CreatedAt: now.Add(-time.Minute)})
+	return e
+}
+
+var visibilityCases = []struct {
+	name     string
+	viewerID string
+	visible  []string // newest first
+}{
+	{"owner", "author", []string{"private", "followers", "public"}},
+	{"follower", "follower", []string{"followers", "public"}},
+	{"stranger", "stranger", []string{"public"}},
+	{"anonymous", "", []string{"public"}},
+}
+
+func TestGetPostByID_Visibility(t *testing.T) {
+	ctx := context.Background()
+	e := newVisibilityEnv()
+
+	for _, tc := range visibilityCases {
+		for _, postID := range []string{"public", "followers", "private"} {
+			t.Run(tc.name+"/"+postID, func(t *testing.T) {
+				post, err := e.postService.GetPostByID(ctx, postID, tc.viewerID)
+				if contains(tc.visible, postID) {
+					require.NoError(t, err)
+					assert.Equal(t, postID, post.ID)
+				} else {
+					requireErrorType(t, err, apperrors.ErrTypeNotFound)
+				}
+			})
+		}
+	}
+}
+
+func TestGetUserPosts_Audience(t *testing.T) {
+	ctx := context.Background()
+	e := newVisibilityEnv()
+
+	// Viewers share one cache, so a viewer placed in the wrong audience would
+	// be served another class's cached page
+	for _, tc := range visibilityCases {
+		t.Run(tc.name, func(t *testing.T) {
+			posts, _, err := e.postService.GetUserPosts(ctx, "author", tc.viewerID, "", 10, 0)
+			require.NoError(t, err)
+			assert.Equal(t, tc.visible, postIDs(posts))
+		})
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func postIDs(posts []*model.Post) []string {
+	ids := make([]string, 0, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+	}
+	return ids
+}
@@ -0,0 +1,156 @@
+// test/unfurl/unfurl_test.go
+package unfurl_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PeterM45/perfolio-api/internal/platform/unfurl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const articlePage = `<!DOCTYPE html>
+<html>
+<head>
+	<title>Fallback title</title>
+	<meta property="og:title" content="Shipping a portfolio in a weekend">
+	<meta property="og:description" content="What we learned building it.">
+	<meta property="og:image" content="/images/cover.png">
+	<meta property="og:site_name" content="Perfolio Blog">
+	<meta name="twitter:title" content="Twitter title">
+</head>
+<body><p>Article</p></body>
+</html>`
+
+func newTestServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/article", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(articlePage))
+	})
+	mux.HandleFunc("/twitter", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><title>Plain</title>
+			<meta name="twitter:title" content="Card title">
+			<meta name="twitter:image" content="https://cdn.example.com/card.jpg">
+			<meta name="description" content="Meta description">
+		</head></html>`))
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/article", http.StatusFound)
+	})
+	mux.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	})
+	mux.HandleFunc("/huge", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><head><title>Huge</title>"))
+		w.Write([]byte(strings.Repeat("<!-- padding -->", 10000)))
+		w.Write([]byte(`<meta property="og:title" content="Past the cap"></head></html>`))
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(articlePage))
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestUnfurler_OpenGraph(t *testing.T) {
+	server := newTestServer(t)
+	u := unfurl.New(unfurl.Config{AllowPrivate: true})
+
+	meta, err := u.Fetch(context.Background(), server.URL+"/article")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Shipping a portfolio in a weekend", meta.Title)
+	assert.Equal(t, "What we learned building it.", meta.Description)
+	assert.Equal(t, server.URL+"/images/cover.png", meta.ImageURL)
+	assert.Equal(t, "Perfolio Blog", meta.SiteName)
+	assert.Equal(t, server.URL+"/article", meta.URL)
+}
+
+func TestUnfurler_TwitterCardFallback(t *testing.T) {
+	server := newTestServer(t)
+	u := unfurl.New(unfurl.Config{AllowPrivate: true})
+
+	meta, err := u.Fetch(context.Background(), server.URL+"/twitter")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Card title", meta.Title)
+	assert.Equal(t, "Meta description", meta.Description)
+	assert.Equal(t, "https://cdn.example.com/card.jpg", meta.ImageURL)
+}
+
+func TestUnfurler_FollowsRedirects(t *testing.T) {
+	server := newTestServer(t)
+	u := unfurl.New(unfurl.Config{AllowPrivate: true})
+
+	meta, err := u.Fetch(context.Background(), server.URL+"/redirect")
+	require.NoError(t, err)
+
+	assert.Equal(t, server.URL+"/article", meta.URL)
+	assert.Equal(t, "Shipping a portfolio in a weekend", meta.Title)
+}
+
+func TestUnfurler_BlocksPrivateAddresses(t *testing.T) {
+	server := newTestServer(t)
+	u := unfurl.New(unfurl.Config{})
+
+	_, err := u.Fetch(context.Background(), server.URL+"/article")
+	assert.ErrorIs(t, err, unfurl.ErrBlockedAddress)
+
+	for _, target := range []string{
+		"http://10.0.0.1/",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://[::1]/",
+	} {
+		_, err := u.Fetch(context.Background(), target)
+		assert.ErrorIs(t, err, unfurl.ErrBlockedAddress, target)
+	}
+}
+
+func TestUnfurler_RejectsUnsupportedURLs(t *testing.T) {
+	u := unfurl.New(unfurl.Config{AllowPrivate: true})
+
+	for _, target := range []string{"ftp://example.com/", "file:///etc/passwd", "not a url"} {
+		_, err := u.Fetch(context.Background(), target)
+		assert.ErrorIs(t, err, unfurl.ErrUnsupportedURL, target)
+	}
+}
+
+func TestUnfurler_RejectsNonHTML(t *testing.T) {
+	server := newTestServer(t)
+	u := unfurl.New(unfurl.Config{AllowPrivate: true})
+
+	_, err := u.Fetch(context.Background(), server.URL+"/json")
+	assert.ErrorIs(t, err, unfurl.ErrNotHTML)
+}
+
+func TestUnfurler_CapsResponseSize(t *testing.T) {
+	server := newTestServer(t)
+	u := unfurl.New(unfurl.Config{AllowPrivate: true, MaxBodyBytes: 4096})
+
+	meta, err := u.Fetch(context.Background(), server.URL+"/huge")
+	require.NoError(t, err)
+
+	assert.Equal(t, "Huge", meta.Title)
+}
+
+func TestUnfurler_Timeout(t *testing.T) {
+	server := newTestServer(t)
+	u := unfurl.New(unfurl.Config{AllowPrivate: true, Timeout: 100 * time.Millisecond})
+
+	_, err := u.Fetch(context.Background(), server.URL+"/slow")
+	assert.Error(t, err)
+}